@@ -0,0 +1,127 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+
+	"github.com/brojonat/kaggo/server/api"
+	"github.com/brojonat/kaggo/server/db/jsonb"
+	"github.com/urfave/cli/v2"
+)
+
+// helper that does the (authenticated) request and prints the response body
+func do_request_print_body(r *http.Request) error {
+	r.Header.Add("Authorization", fmt.Sprintf("Bearer %s", os.Getenv("AUTH_TOKEN")))
+	res, err := http.DefaultClient.Do(r)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return err
+	}
+	if res.StatusCode != http.StatusOK {
+		return fmt.Errorf("bad response from server: %s: %s", res.Status, body)
+	}
+	fmt.Println(string(body))
+	return nil
+}
+
+func add_alert_rule(ctx *cli.Context) error {
+	p := api.CreateAlertRulePayload{
+		Email:       ctx.String("email"),
+		RequestKind: ctx.String("request-kind"),
+		ID:          ctx.String("id"),
+		Metric:      ctx.String("metric"),
+		Condition:   ctx.String("condition"),
+		Params: jsonb.AlertRuleParams{
+			Op:         ctx.String("op"),
+			Threshold:  ctx.Float64("threshold"),
+			Window:     ctx.String("window"),
+			Pct:        ctx.Float64("pct"),
+			ZScore:     ctx.Float64("z-score"),
+			MinSamples: ctx.Int("min-samples"),
+		},
+		Notifier:        ctx.String("notifier"),
+		Target:          ctx.String("target"),
+		CooldownSeconds: int(ctx.Duration("cooldown").Seconds()),
+	}
+	body, err := json.Marshal(p)
+	if err != nil {
+		return fmt.Errorf("could not serialize payload: %w", err)
+	}
+	r, err := http.NewRequest(
+		http.MethodPost,
+		ctx.String("endpoint")+"/alerts",
+		bytes.NewReader(body),
+	)
+	if err != nil {
+		return err
+	}
+	return do_request_print_body(r)
+}
+
+func list_alert_rules(ctx *cli.Context) error {
+	r, err := http.NewRequest(http.MethodGet, ctx.String("endpoint")+"/alerts", nil)
+	if err != nil {
+		return err
+	}
+	q := r.URL.Query()
+	q.Add("email", ctx.String("email"))
+	r.URL.RawQuery = q.Encode()
+	return do_request_print_body(r)
+}
+
+func delete_alert_rules(ctx *cli.Context) error {
+	r, err := http.NewRequest(http.MethodDelete, ctx.String("endpoint")+"/alerts", nil)
+	if err != nil {
+		return err
+	}
+	q := r.URL.Query()
+	q.Add("email", ctx.String("email"))
+	for _, id := range ctx.Int64Slice("rule-id") {
+		q.Add("rule_id", strconv.FormatInt(id, 10))
+	}
+	r.URL.RawQuery = q.Encode()
+	return do_request_print_body(r)
+}
+
+func alert_history(ctx *cli.Context) error {
+	r, err := http.NewRequest(http.MethodGet, ctx.String("endpoint")+"/alerts/history", nil)
+	if err != nil {
+		return err
+	}
+	q := r.URL.Query()
+	q.Add("email", ctx.String("email"))
+	if dur := ctx.String("dur"); dur != "" {
+		q.Add("dur", dur)
+	}
+	r.URL.RawQuery = q.Encode()
+	return do_request_print_body(r)
+}
+
+func test_notifier(ctx *cli.Context) error {
+	p := api.TestNotifierPayload{
+		Notifier: ctx.String("notifier"),
+		Target:   ctx.String("target"),
+	}
+	body, err := json.Marshal(p)
+	if err != nil {
+		return fmt.Errorf("could not serialize payload: %w", err)
+	}
+	r, err := http.NewRequest(
+		http.MethodPost,
+		ctx.String("endpoint")+"/alerts/test",
+		bytes.NewReader(body),
+	)
+	if err != nil {
+		return err
+	}
+	return do_request_print_body(r)
+}
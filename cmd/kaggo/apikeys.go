@@ -0,0 +1,60 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/brojonat/kaggo/server/api"
+	"github.com/urfave/cli/v2"
+)
+
+func create_api_key(ctx *cli.Context) error {
+	p := api.CreateAPIKeyPayload{
+		Email:     ctx.String("email"),
+		Name:      ctx.String("name"),
+		Scopes:    ctx.StringSlice("scope"),
+		ExpiresIn: ctx.String("expires-in"),
+	}
+	body, err := json.Marshal(p)
+	if err != nil {
+		return fmt.Errorf("could not serialize payload: %w", err)
+	}
+	r, err := http.NewRequest(
+		http.MethodPost,
+		ctx.String("endpoint")+"/api-keys",
+		bytes.NewReader(body),
+	)
+	if err != nil {
+		return err
+	}
+	return do_request_print_body(r)
+}
+
+func list_api_keys(ctx *cli.Context) error {
+	r, err := http.NewRequest(http.MethodGet, ctx.String("endpoint")+"/api-keys", nil)
+	if err != nil {
+		return err
+	}
+	if email := ctx.String("email"); email != "" {
+		q := r.URL.Query()
+		q.Add("email", email)
+		r.URL.RawQuery = q.Encode()
+	}
+	return do_request_print_body(r)
+}
+
+func revoke_api_keys(ctx *cli.Context) error {
+	r, err := http.NewRequest(http.MethodDelete, ctx.String("endpoint")+"/api-keys", nil)
+	if err != nil {
+		return err
+	}
+	q := r.URL.Query()
+	for _, id := range ctx.Int64Slice("key-id") {
+		q.Add("key_id", strconv.FormatInt(id, 10))
+	}
+	r.URL.RawQuery = q.Encode()
+	return do_request_print_body(r)
+}
@@ -0,0 +1,47 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/brojonat/kaggo/server/api"
+	"github.com/urfave/cli/v2"
+)
+
+// parse_time accepts a full RFC3339 timestamp or just a (UTC) date
+func parse_time(s string) (time.Time, error) {
+	if t, err := time.Parse(time.DateOnly, s); err == nil {
+		return t, nil
+	}
+	return time.Parse(time.RFC3339, s)
+}
+
+func run_backfill(ctx *cli.Context) error {
+	p := api.BackfillPayload{
+		RequestKind: ctx.String("request-kind"),
+		IDs:         ctx.StringSlice("id"),
+		Metrics:     ctx.StringSlice("metric"),
+		End:         time.Now(),
+	}
+	var err error
+	if p.Start, err = parse_time(ctx.String("from")); err != nil {
+		return fmt.Errorf("could not parse from: %w", err)
+	}
+	if s := ctx.String("to"); s != "" {
+		if p.End, err = parse_time(s); err != nil {
+			return fmt.Errorf("could not parse to: %w", err)
+		}
+	}
+	b, err := json.Marshal(p)
+	if err != nil {
+		return err
+	}
+	r, err := http.NewRequest(http.MethodPost, ctx.String("endpoint")+"/backfill", bytes.NewReader(b))
+	if err != nil {
+		return err
+	}
+	return do_request_print_body(r)
+}
@@ -0,0 +1,83 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"slices"
+
+	kt "github.com/brojonat/kaggo/temporal/v19700101"
+	"github.com/urfave/cli/v2"
+)
+
+// validate_extractors parses and compiles the extractor specs (either the
+// embedded defaults or the ones in --dir) and checks that they line up with the
+// registered request kinds. If a fixture is supplied, the spec for the supplied
+// request kind is applied to it and the extracted payload is printed.
+func validate_extractors(ctx *cli.Context) error {
+	if dir := ctx.String("dir"); dir != "" {
+		if err := kt.LoadExtractorSpecs(os.DirFS(dir)); err != nil {
+			return fmt.Errorf("invalid extractor specs: %w", err)
+		}
+	}
+	if err := kt.ValidateRequestKindRegistry(); err != nil {
+		return err
+	}
+
+	fixture := ctx.String("fixture")
+	if fixture == "" {
+		for _, rk := range kt.GetSupportedRequestKinds() {
+			s := kt.GetExtractorSpec(rk)
+			if s == nil {
+				fmt.Printf("%s: no extractor spec\n", rk)
+				continue
+			}
+			var sections []string
+			if s.Metrics != nil {
+				sections = append(sections, "metrics")
+			}
+			if s.Metadata != nil {
+				sections = append(sections, "metadata")
+			}
+			fmt.Printf("%s: ok %v\n", rk, sections)
+		}
+		return nil
+	}
+
+	rk := ctx.String("request-kind")
+	if !slices.Contains(kt.GetSupportedRequestKinds(), rk) {
+		return fmt.Errorf("must supply a supported request-kind with a fixture")
+	}
+	s := kt.GetExtractorSpec(rk)
+	if s == nil {
+		return fmt.Errorf("no extractor spec for %s", rk)
+	}
+	b, err := os.ReadFile(fixture)
+	if err != nil {
+		return err
+	}
+
+	var out interface{}
+	if ctx.Bool("metadata") {
+		out, err = s.ExtractMetadata(b)
+		if err != nil {
+			return err
+		}
+	} else {
+		payload, err := s.ExtractMetrics(b)
+		if err != nil {
+			return err
+		}
+		if payload == nil {
+			fmt.Println("skipped (skip_if matched)")
+			return nil
+		}
+		out = json.RawMessage(payload)
+	}
+	b, err = json.MarshalIndent(out, "", "  ")
+	if err != nil {
+		return err
+	}
+	fmt.Println(string(b))
+	return nil
+}
@@ -8,6 +8,7 @@ import (
 	"path/filepath"
 	"slices"
 	"strings"
+	"time"
 
 	"github.com/urfave/cli/v2"
 )
@@ -90,6 +91,31 @@ func main() {
 									return delete_user(ctx)
 								},
 							},
+							{
+								Name:  "issue-token",
+								Usage: "Issue a bearer token for a user (requires SERVER_SECRET_KEY)",
+								Flags: []cli.Flag{
+									&cli.StringFlag{
+										Name:    "endpoint",
+										Aliases: []string{"end", "e"},
+										Value:   "https://api.kaggo.brojonat.com",
+										Usage:   "Kaggo server endpoint",
+									},
+									&cli.StringFlag{
+										Name:     "email",
+										Required: true,
+										Usage:    "User's email",
+									},
+									&cli.StringFlag{
+										Name:  "role",
+										Value: "customer",
+										Usage: "Token role (admin or customer)",
+									},
+								},
+								Action: func(ctx *cli.Context) error {
+									return issue_token(ctx)
+								},
+							},
 							{
 								Name:  "grant-metric",
 								Usage: "Grant a metric to a user",
@@ -166,6 +192,463 @@ func main() {
 							},
 						},
 					},
+					{
+						Name:  "alerts",
+						Usage: "Administrative alert rule commands",
+						Subcommands: []*cli.Command{
+							{
+								Name:  "add",
+								Usage: "Add an alert rule for a user",
+								Flags: []cli.Flag{
+									&cli.StringFlag{
+										Name:    "endpoint",
+										Aliases: []string{"end", "e"},
+										Value:   "https://api.kaggo.brojonat.com",
+										Usage:   "Kaggo server endpoint",
+									},
+									&cli.StringFlag{
+										Name:     "email",
+										Required: true,
+										Usage:    "User's email",
+									},
+									&cli.StringFlag{
+										Name:     "request-kind",
+										Aliases:  []string{"rk", "r"},
+										Required: true,
+										Usage:    "Metric request kind",
+									},
+									&cli.StringFlag{
+										Name:     "id",
+										Aliases:  []string{"i"},
+										Required: true,
+										Usage:    "Metric identifier",
+									},
+									&cli.StringFlag{
+										Name:     "metric",
+										Aliases:  []string{"m"},
+										Required: true,
+										Usage:    "Short metric name (e.g., score)",
+									},
+									&cli.StringFlag{
+										Name:     "condition",
+										Aliases:  []string{"c"},
+										Required: true,
+										Usage:    "Condition (threshold, pct-change, or z-score)",
+									},
+									&cli.StringFlag{
+										Name:  "op",
+										Value: "above",
+										Usage: "Threshold comparison (above or below)",
+									},
+									&cli.Float64Flag{
+										Name:  "threshold",
+										Usage: "Threshold value",
+									},
+									&cli.StringFlag{
+										Name:  "window",
+										Usage: "Lookback window for pct-change and z-score rules (e.g., 24h)",
+									},
+									&cli.Float64Flag{
+										Name:  "pct",
+										Usage: "Absolute percent change that fires a pct-change rule",
+									},
+									&cli.Float64Flag{
+										Name:  "z-score",
+										Usage: "Absolute z-score that fires a z-score rule",
+									},
+									&cli.IntFlag{
+										Name:  "min-samples",
+										Usage: "Minimum samples in the window for a z-score rule",
+									},
+									&cli.StringFlag{
+										Name:     "notifier",
+										Aliases:  []string{"n"},
+										Required: true,
+										Usage:    "Notifier (webhook or smtp)",
+									},
+									&cli.StringFlag{
+										Name:     "target",
+										Aliases:  []string{"t"},
+										Required: true,
+										Usage:    "Notifier target (webhook URL or email address)",
+									},
+									&cli.DurationFlag{
+										Name:  "cooldown",
+										Value: time.Hour,
+										Usage: "Minimum time between firings",
+									},
+								},
+								Action: func(ctx *cli.Context) error {
+									return add_alert_rule(ctx)
+								},
+							},
+							{
+								Name:  "list",
+								Usage: "List a user's alert rules",
+								Flags: []cli.Flag{
+									&cli.StringFlag{
+										Name:    "endpoint",
+										Aliases: []string{"end", "e"},
+										Value:   "https://api.kaggo.brojonat.com",
+										Usage:   "Kaggo server endpoint",
+									},
+									&cli.StringFlag{
+										Name:     "email",
+										Required: true,
+										Usage:    "User's email",
+									},
+								},
+								Action: func(ctx *cli.Context) error {
+									return list_alert_rules(ctx)
+								},
+							},
+							{
+								Name:  "delete",
+								Usage: "Delete a user's alert rule(s)",
+								Flags: []cli.Flag{
+									&cli.StringFlag{
+										Name:    "endpoint",
+										Aliases: []string{"end", "e"},
+										Value:   "https://api.kaggo.brojonat.com",
+										Usage:   "Kaggo server endpoint",
+									},
+									&cli.StringFlag{
+										Name:     "email",
+										Required: true,
+										Usage:    "User's email",
+									},
+									&cli.Int64SliceFlag{
+										Name:     "rule-id",
+										Required: true,
+										Usage:    "Rule ID(s) to delete",
+									},
+								},
+								Action: func(ctx *cli.Context) error {
+									return delete_alert_rules(ctx)
+								},
+							},
+							{
+								Name:  "history",
+								Usage: "Show the alerts fired for a user's rules",
+								Flags: []cli.Flag{
+									&cli.StringFlag{
+										Name:    "endpoint",
+										Aliases: []string{"end", "e"},
+										Value:   "https://api.kaggo.brojonat.com",
+										Usage:   "Kaggo server endpoint",
+									},
+									&cli.StringFlag{
+										Name:     "email",
+										Required: true,
+										Usage:    "User's email",
+									},
+									&cli.StringFlag{
+										Name:  "dur",
+										Usage: "How far back to look (e.g., 7d); defaults to a week",
+									},
+								},
+								Action: func(ctx *cli.Context) error {
+									return alert_history(ctx)
+								},
+							},
+							{
+								Name:  "test-notifier",
+								Usage: "Send a test alert through a notifier",
+								Flags: []cli.Flag{
+									&cli.StringFlag{
+										Name:    "endpoint",
+										Aliases: []string{"end", "e"},
+										Value:   "https://api.kaggo.brojonat.com",
+										Usage:   "Kaggo server endpoint",
+									},
+									&cli.StringFlag{
+										Name:     "notifier",
+										Aliases:  []string{"n"},
+										Required: true,
+										Usage:    "Notifier (webhook or smtp)",
+									},
+									&cli.StringFlag{
+										Name:     "target",
+										Aliases:  []string{"t"},
+										Required: true,
+										Usage:    "Notifier target (webhook URL or email address)",
+									},
+								},
+								Action: func(ctx *cli.Context) error {
+									return test_notifier(ctx)
+								},
+							},
+						},
+					},
+					{
+						Name:  "webhooks",
+						Usage: "Administrative webhook commands",
+						Subcommands: []*cli.Command{
+							{
+								Name:  "add",
+								Usage: "Add a webhook for a user (prints the signing secret)",
+								Flags: []cli.Flag{
+									&cli.StringFlag{
+										Name:    "endpoint",
+										Aliases: []string{"end", "e"},
+										Value:   "https://api.kaggo.brojonat.com",
+										Usage:   "Kaggo server endpoint",
+									},
+									&cli.StringFlag{
+										Name:     "email",
+										Required: true,
+										Usage:    "User's email",
+									},
+									&cli.StringFlag{
+										Name:     "url",
+										Aliases:  []string{"u"},
+										Required: true,
+										Usage:    "Webhook endpoint URL",
+									},
+									&cli.StringSliceFlag{
+										Name:  "event",
+										Usage: "Event type(s) to deliver (content.discovered, schedule.created, schedule.expired, metadata.refreshed); defaults to all",
+									},
+								},
+								Action: func(ctx *cli.Context) error {
+									return add_webhook(ctx)
+								},
+							},
+							{
+								Name:  "list",
+								Usage: "List a user's webhooks",
+								Flags: []cli.Flag{
+									&cli.StringFlag{
+										Name:    "endpoint",
+										Aliases: []string{"end", "e"},
+										Value:   "https://api.kaggo.brojonat.com",
+										Usage:   "Kaggo server endpoint",
+									},
+									&cli.StringFlag{
+										Name:     "email",
+										Required: true,
+										Usage:    "User's email",
+									},
+								},
+								Action: func(ctx *cli.Context) error {
+									return list_webhooks(ctx)
+								},
+							},
+							{
+								Name:  "delete",
+								Usage: "Delete a user's webhook(s)",
+								Flags: []cli.Flag{
+									&cli.StringFlag{
+										Name:    "endpoint",
+										Aliases: []string{"end", "e"},
+										Value:   "https://api.kaggo.brojonat.com",
+										Usage:   "Kaggo server endpoint",
+									},
+									&cli.StringFlag{
+										Name:     "email",
+										Required: true,
+										Usage:    "User's email",
+									},
+									&cli.Int64SliceFlag{
+										Name:     "webhook-id",
+										Required: true,
+										Usage:    "Webhook ID(s) to delete",
+									},
+								},
+								Action: func(ctx *cli.Context) error {
+									return delete_webhooks(ctx)
+								},
+							},
+							{
+								Name:  "dead-letters",
+								Usage: "Show the events that couldn't be delivered to a user's webhooks",
+								Flags: []cli.Flag{
+									&cli.StringFlag{
+										Name:    "endpoint",
+										Aliases: []string{"end", "e"},
+										Value:   "https://api.kaggo.brojonat.com",
+										Usage:   "Kaggo server endpoint",
+									},
+									&cli.StringFlag{
+										Name:     "email",
+										Required: true,
+										Usage:    "User's email",
+									},
+									&cli.StringFlag{
+										Name:  "dur",
+										Usage: "How far back to look (e.g., 7d); defaults to a week",
+									},
+								},
+								Action: func(ctx *cli.Context) error {
+									return webhook_dead_letters(ctx)
+								},
+							},
+						},
+					},
+					{
+						Name:  "api-keys",
+						Usage: "Administrative API key commands",
+						Subcommands: []*cli.Command{
+							{
+								Name:  "create",
+								Usage: "Create an API key for a user (prints the key)",
+								Flags: []cli.Flag{
+									&cli.StringFlag{
+										Name:    "endpoint",
+										Aliases: []string{"end", "e"},
+										Value:   "https://api.kaggo.brojonat.com",
+										Usage:   "Kaggo server endpoint",
+									},
+									&cli.StringFlag{
+										Name:     "email",
+										Required: true,
+										Usage:    "Email of the key's owner",
+									},
+									&cli.StringFlag{
+										Name:  "name",
+										Usage: "Human readable name for the key (e.g., worker-prod)",
+									},
+									&cli.StringSliceFlag{
+										Name:     "scope",
+										Required: true,
+										Usage:    "Scope(s) to grant (ingest, read, schedule-admin, user-admin)",
+									},
+									&cli.StringFlag{
+										Name:  "expires-in",
+										Usage: "Lifetime of the key (e.g., 90d); defaults to never expiring",
+									},
+								},
+								Action: func(ctx *cli.Context) error {
+									return create_api_key(ctx)
+								},
+							},
+							{
+								Name:  "list",
+								Usage: "List API keys",
+								Flags: []cli.Flag{
+									&cli.StringFlag{
+										Name:    "endpoint",
+										Aliases: []string{"end", "e"},
+										Value:   "https://api.kaggo.brojonat.com",
+										Usage:   "Kaggo server endpoint",
+									},
+									&cli.StringFlag{
+										Name:  "email",
+										Usage: "Only list the keys owned by this user",
+									},
+								},
+								Action: func(ctx *cli.Context) error {
+									return list_api_keys(ctx)
+								},
+							},
+							{
+								Name:  "revoke",
+								Usage: "Revoke API key(s)",
+								Flags: []cli.Flag{
+									&cli.StringFlag{
+										Name:    "endpoint",
+										Aliases: []string{"end", "e"},
+										Value:   "https://api.kaggo.brojonat.com",
+										Usage:   "Kaggo server endpoint",
+									},
+									&cli.Int64SliceFlag{
+										Name:     "key-id",
+										Required: true,
+										Usage:    "Key ID(s) to revoke",
+									},
+								},
+								Action: func(ctx *cli.Context) error {
+									return revoke_api_keys(ctx)
+								},
+							},
+						},
+					},
+					{
+						Name:  "rate-limits",
+						Usage: "Provider rate limit commands",
+						Subcommands: []*cli.Command{
+							{
+								Name:  "list",
+								Usage: "Show the rate limit buckets, including how many requests each has delayed or skipped and why",
+								Flags: []cli.Flag{
+									&cli.StringFlag{
+										Name:    "endpoint",
+										Aliases: []string{"end", "e"},
+										Value:   "https://api.kaggo.brojonat.com",
+										Usage:   "Kaggo server endpoint",
+									},
+								},
+								Action: func(ctx *cli.Context) error {
+									return list_rate_limits(ctx)
+								},
+							},
+						},
+					},
+					{
+						Name:  "quota",
+						Usage: "Provider quota commands",
+						Subcommands: []*cli.Command{
+							{
+								Name:  "list",
+								Usage: "Show the quota units spent per API key",
+								Flags: []cli.Flag{
+									&cli.StringFlag{
+										Name:    "endpoint",
+										Aliases: []string{"end", "e"},
+										Value:   "https://api.kaggo.brojonat.com",
+										Usage:   "Kaggo server endpoint",
+									},
+									&cli.StringFlag{
+										Name:  "dur",
+										Usage: "How far back to look (e.g., 7d); defaults to the current quota period",
+									},
+								},
+								Action: func(ctx *cli.Context) error {
+									return list_quota(ctx)
+								},
+							},
+						},
+					},
+					{
+						Name:  "backfill",
+						Usage: "Replay archived responses through the current extractors into the metric tables",
+						Flags: []cli.Flag{
+							&cli.StringFlag{
+								Name:    "endpoint",
+								Aliases: []string{"end", "e"},
+								Value:   "https://api.kaggo.brojonat.com",
+								Usage:   "Kaggo server endpoint",
+							},
+							&cli.StringFlag{
+								Name:     "request-kind",
+								Aliases:  []string{"rk", "r"},
+								Required: true,
+								Usage:    "Request kind to backfill",
+							},
+							&cli.StringSliceFlag{
+								Name:    "id",
+								Aliases: []string{"i"},
+								Usage:   "Identifier(s) to backfill; defaults to all",
+							},
+							&cli.StringSliceFlag{
+								Name:    "metric",
+								Aliases: []string{"m"},
+								Usage:   "Metric(s) to backfill (e.g., favorites); defaults to all",
+							},
+							&cli.StringFlag{
+								Name:     "from",
+								Required: true,
+								Usage:    "Start of the range (RFC3339 or YYYY-MM-DD)",
+							},
+							&cli.StringFlag{
+								Name:  "to",
+								Usage: "End of the range (RFC3339 or YYYY-MM-DD); defaults to now",
+							},
+						},
+						Action: func(ctx *cli.Context) error {
+							return run_backfill(ctx)
+						},
+					},
 					{
 						Name:  "listener",
 						Usage: "Listener operations",
@@ -240,6 +723,15 @@ func main() {
 										Required: true,
 										Usage:    "Identifier for the schedule",
 									},
+									&cli.StringFlag{
+										Name:  "preset",
+										Value: "default",
+										Usage: "Schedule preset (aggressive, default, archival)",
+									},
+									&cli.BoolFlag{
+										Name:  "fixed-cadence",
+										Usage: "Keep the schedule on its preset even if the kind's cadence is adaptive",
+									},
 								},
 								Action: func(ctx *cli.Context) error {
 									return create_schedule(ctx)
@@ -281,11 +773,69 @@ func main() {
 										Aliases: []string{"f"},
 										Usage:   "Output file location",
 									},
+									&cli.StringFlag{
+										Name:    "request-kind",
+										Aliases: []string{"rk", "r"},
+										Usage:   "Only dump schedules of this request kind",
+									},
+									&cli.StringFlag{
+										Name:  "owner-email",
+										Usage: "Only dump schedules created by this user",
+									},
+									&cli.StringFlag{
+										Name:  "tier",
+										Usage: "Only dump schedules created with this preset (or custom)",
+									},
 								},
 								Action: func(ctx *cli.Context) error {
 									return dump_schedules(ctx)
 								},
 							},
+							{
+								Name:  "list-workflows",
+								Usage: "List workflow executions by request kind, entity, owner, tier or status",
+								Flags: []cli.Flag{
+									&cli.StringFlag{
+										Name:    "endpoint",
+										Aliases: []string{"end", "e"},
+										Value:   "https://api.kaggo.brojonat.com",
+										Usage:   "Kaggo server endpoint",
+									},
+									&cli.StringFlag{
+										Name:    "request-kind",
+										Aliases: []string{"rk", "r"},
+										Usage:   "Request kind",
+									},
+									&cli.StringFlag{
+										Name:  "id",
+										Usage: "Entity ID",
+									},
+									&cli.StringFlag{
+										Name:  "owner-email",
+										Usage: "Email of the user that created the schedule",
+									},
+									&cli.StringFlag{
+										Name:  "tier",
+										Usage: "Preset the schedule was created with (or custom)",
+									},
+									&cli.StringFlag{
+										Name:  "status",
+										Usage: "Execution status (e.g., Running, Failed)",
+									},
+									&cli.IntFlag{
+										Name:  "page-size",
+										Value: 100,
+										Usage: "Workflows per page",
+									},
+									&cli.BoolFlag{
+										Name:  "all",
+										Usage: "Fetch every page",
+									},
+								},
+								Action: func(ctx *cli.Context) error {
+									return list_workflows(ctx)
+								},
+							},
 							{
 								Name:  "load-schedules",
 								Usage: "Load schedules from file",
@@ -309,7 +859,7 @@ func main() {
 							},
 							{
 								Name:  "reupload-schedules",
-								Usage: "Delete and reupload schedules of the supplied type.",
+								Usage: "Reset schedules of the supplied type to the kind's default spec (keeping their end time).",
 								Flags: []cli.Flag{
 									&cli.StringFlag{
 										Name:    "endpoint",
@@ -318,20 +868,175 @@ func main() {
 										Usage:   "Kaggo server endpoint",
 									},
 									&cli.StringFlag{
-										Name:     "file",
-										Aliases:  []string{"f"},
+										Name:     "request-kind",
+										Aliases:  []string{"rk", "r"},
 										Required: true,
-										Usage:    "Input file location",
+										Usage:    "Request kind to reupload",
+									},
+								},
+								Action: func(ctx *cli.Context) error {
+									return reupload_schedules(ctx)
+								},
+							},
+							{
+								Name:  "describe-schedule",
+								Usage: "Describe a schedule, including its recent and upcoming actions",
+								Flags: []cli.Flag{
+									&cli.StringFlag{
+										Name:    "endpoint",
+										Aliases: []string{"end", "e"},
+										Value:   "https://api.kaggo.brojonat.com",
+										Usage:   "Kaggo server endpoint",
+									},
+									&cli.StringFlag{
+										Name:     "schedule-id",
+										Aliases:  []string{"schedule_id", "sid", "s"},
+										Required: true,
+										Usage:    "Schedule to describe",
+									},
+								},
+								Action: func(ctx *cli.Context) error {
+									return describe_schedule(ctx)
+								},
+							},
+							{
+								Name:  "schedule-capacity",
+								Usage: "Show the projected number of schedule runs per request kind",
+								Flags: []cli.Flag{
+									&cli.StringFlag{
+										Name:    "endpoint",
+										Aliases: []string{"end", "e"},
+										Value:   "https://api.kaggo.brojonat.com",
+										Usage:   "Kaggo server endpoint",
+									},
+									&cli.StringFlag{
+										Name:  "horizon",
+										Value: "24h",
+										Usage: "How far ahead to project (e.g., 24h, 7d)",
+									},
+									&cli.StringFlag{
+										Name:  "bucket",
+										Value: "1h",
+										Usage: "Width of the buckets runs are counted in",
+									},
+									&cli.StringFlag{
+										Name:    "request-kind",
+										Aliases: []string{"rk"},
+										Usage:   "Only include schedules of this request kind",
+									},
+								},
+								Action: func(ctx *cli.Context) error {
+									return schedule_capacity(ctx)
+								},
+							},
+							{
+								Name:  "watch-schedule-expiry",
+								Usage: "Start end-of-life review and expiry watchers for existing schedules with an end time",
+								Flags: []cli.Flag{
+									&cli.StringFlag{
+										Name:    "endpoint",
+										Aliases: []string{"end", "e"},
+										Value:   "https://api.kaggo.brojonat.com",
+										Usage:   "Kaggo server endpoint",
 									},
+									&cli.StringFlag{
+										Name:    "request-kind",
+										Aliases: []string{"rk"},
+										Usage:   "Only include schedules of this request kind",
+									},
+								},
+								Action: func(ctx *cli.Context) error {
+									return watch_schedule_expiry(ctx)
+								},
+							},
+							{
+								Name:  "preview-schedule",
+								Usage: "Show the next runs of a schedule preset or spec file without creating a schedule",
+								Flags: []cli.Flag{
 									&cli.StringFlag{
 										Name:     "request-kind",
 										Aliases:  []string{"rk", "r"},
 										Required: true,
-										Usage:    "Request kind to reupload",
+										Usage:    "Request kind the schedule is for",
+									},
+									&cli.StringFlag{
+										Name:  "preset",
+										Value: "default",
+										Usage: "Schedule preset (aggressive, default, archival)",
+									},
+									&cli.StringFlag{
+										Name:  "spec-file",
+										Usage: "JSON client.ScheduleSpec to preview instead of a preset",
+									},
+									&cli.DurationFlag{
+										Name:  "horizon",
+										Value: 72 * time.Hour,
+										Usage: "How far ahead to look",
+									},
+									&cli.IntFlag{
+										Name:  "n",
+										Value: 10,
+										Usage: "Maximum number of runs to show",
 									},
 								},
 								Action: func(ctx *cli.Context) error {
-									return reupload_schedules(ctx)
+									return preview_schedule(ctx)
+								},
+							},
+							{
+								Name:  "update-schedules",
+								Usage: "Pause, unpause, update the spec of, extend, or set the note of one or more schedules",
+								Flags: []cli.Flag{
+									&cli.StringFlag{
+										Name:    "endpoint",
+										Aliases: []string{"end", "e"},
+										Value:   "https://api.kaggo.brojonat.com",
+										Usage:   "Kaggo server endpoint",
+									},
+									&cli.StringFlag{
+										Name:     "action",
+										Aliases:  []string{"a"},
+										Required: true,
+										Usage:    "Action (pause, unpause, update-spec, extend, set-note)",
+									},
+									&cli.StringSliceFlag{
+										Name:    "schedule-id",
+										Aliases: []string{"schedule_id", "sid", "s"},
+										Usage:   "Schedule(s) to update",
+									},
+									&cli.StringFlag{
+										Name:    "request-kind",
+										Aliases: []string{"rk", "r"},
+										Usage:   "Update every schedule of this request kind",
+									},
+									&cli.StringSliceFlag{
+										Name:    "id",
+										Aliases: []string{"i"},
+										Usage:   "With --request-kind, only update the schedules for these identifier(s)",
+									},
+									&cli.StringFlag{
+										Name:  "note",
+										Usage: "Schedule note",
+									},
+									&cli.StringFlag{
+										Name:  "spec-file",
+										Usage: "JSON schedule spec for update-spec",
+									},
+									&cli.StringFlag{
+										Name:  "preset",
+										Usage: "Schedule preset for update-spec (aggressive, default, archival)",
+									},
+									&cli.StringFlag{
+										Name:  "extend-by",
+										Usage: "Duration to extend the end time by (e.g., 7d or 168h)",
+									},
+									&cli.StringFlag{
+										Name:  "end-at",
+										Usage: "New end time (RFC 3339)",
+									},
+								},
+								Action: func(ctx *cli.Context) error {
+									return update_schedules(ctx)
 								},
 							},
 							{
@@ -351,6 +1056,42 @@ func main() {
 							},
 						},
 					},
+					{
+						Name:  "extractors",
+						Usage: "Commands for managing the JMESPath extractor specs",
+						Subcommands: []*cli.Command{
+							{
+								Name:  "validate",
+								Usage: "Validate extractor specs, optionally applying one to a fixture",
+								Flags: []cli.Flag{
+									&cli.StringFlag{
+										Name:    "dir",
+										Aliases: []string{"d"},
+										Value:   os.Getenv("EXTRACTOR_SPEC_DIR"),
+										Usage:   "Directory of extractor specs (defaults to the embedded specs)",
+									},
+									&cli.StringFlag{
+										Name:    "request-kind",
+										Aliases: []string{"rk", "r"},
+										Usage:   "Request kind of the fixture",
+									},
+									&cli.StringFlag{
+										Name:    "fixture",
+										Aliases: []string{"f"},
+										Usage:   "Path to a recorded response body to extract from",
+									},
+									&cli.BoolFlag{
+										Name:    "metadata",
+										Aliases: []string{"md", "m"},
+										Usage:   "Apply the metadata extractor instead of the metrics extractor",
+									},
+								},
+								Action: func(ctx *cli.Context) error {
+									return validate_extractors(ctx)
+								},
+							},
+						},
+					},
 					{
 						Name:  "workflow",
 						Usage: "Commands for managing workflows",
@@ -452,6 +1193,32 @@ func main() {
 								Value:   os.Getenv("TEMPORAL_HOST"),
 								Usage:   "Temporal endpoint",
 							},
+							&cli.StringFlag{
+								Name:  "extractor-dir",
+								Value: os.Getenv("EXTRACTOR_SPEC_DIR"),
+								Usage: "Directory of extractor specs to use instead of the embedded specs",
+							},
+							&cli.StringFlag{
+								Name:  "batch-kinds",
+								Value: os.Getenv("BATCH_POLLING_KINDS"),
+								Usage: "Comma separated request kinds to poll in batches (e.g., youtube.video,youtube.channel)",
+							},
+							&cli.StringFlag{
+								Name:    "database",
+								Aliases: []string{"db", "d"},
+								Value:   os.Getenv("DATABASE_URL"),
+								Usage:   "Database endpoint; if set, OAuth tokens are shared with the other workers through it",
+							},
+							&cli.StringFlag{
+								Name:  "archive",
+								Value: os.Getenv("RESPONSE_ARCHIVE_URL"),
+								Usage: "Blob store to archive every response to (file:///path, s3://host/bucket[/prefix], or s3+http://host/bucket[/prefix])",
+							},
+							&cli.BoolFlag{
+								Name:  "direct-ingest",
+								Value: os.Getenv("KAGGO_DIRECT_INGEST") == "true",
+								Usage: "Write metrics directly to the database (requires --database) instead of uploading them to the server",
+							},
 						},
 						Action: func(ctx *cli.Context) error {
 							return run_worker(ctx)
@@ -0,0 +1,28 @@
+package main
+
+import (
+	"net/http"
+
+	"github.com/urfave/cli/v2"
+)
+
+func list_rate_limits(ctx *cli.Context) error {
+	r, err := http.NewRequest(http.MethodGet, ctx.String("endpoint")+"/rate-limits", nil)
+	if err != nil {
+		return err
+	}
+	return do_request_print_body(r)
+}
+
+func list_quota(ctx *cli.Context) error {
+	r, err := http.NewRequest(http.MethodGet, ctx.String("endpoint")+"/quota", nil)
+	if err != nil {
+		return err
+	}
+	if dur := ctx.String("dur"); dur != "" {
+		q := r.URL.Query()
+		q.Add("dur", dur)
+		r.URL.RawQuery = q.Encode()
+	}
+	return do_request_print_body(r)
+}
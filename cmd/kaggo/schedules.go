@@ -7,30 +7,76 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
 	"os"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/brojonat/kaggo/server/api"
+	"github.com/brojonat/kaggo/server/schedspec"
 	kt "github.com/brojonat/kaggo/temporal/v19700101"
 	"github.com/urfave/cli/v2"
 	"go.temporal.io/sdk/client"
 )
 
-func dump_schedules(ctx *cli.Context) error {
-	r, err := http.NewRequest(http.MethodGet, ctx.String("endpoint")+"/schedule", nil)
-	if err != nil {
-		return err
+// list_schedules fetches every page of schedules matching the filters.
+func list_schedules(ctx *cli.Context, filters url.Values) ([]api.ScheduleListEntry, error) {
+	schedules := []api.ScheduleListEntry{}
+	token := ""
+	for {
+		r, err := http.NewRequest(http.MethodGet, ctx.String("endpoint")+"/schedule", nil)
+		if err != nil {
+			return nil, err
+		}
+		r.Header.Add("Authorization", fmt.Sprintf("Bearer %s", os.Getenv("AUTH_TOKEN")))
+		q := url.Values{}
+		for k, vs := range filters {
+			for _, v := range vs {
+				if v != "" {
+					q.Add(k, v)
+				}
+			}
+		}
+		q.Set("page_size", "1000")
+		if token != "" {
+			q.Set("page_token", token)
+		}
+		r.URL.RawQuery = q.Encode()
+		res, err := http.DefaultClient.Do(r)
+		if err != nil {
+			return nil, err
+		}
+		b, err := io.ReadAll(res.Body)
+		res.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		if res.StatusCode != http.StatusOK {
+			return nil, fmt.Errorf("bad response from server: %s: %s", res.Status, b)
+		}
+		var page api.ScheduleListResponse
+		if err = json.Unmarshal(b, &page); err != nil {
+			return nil, err
+		}
+		schedules = append(schedules, page.Schedules...)
+		if page.NextPageToken == "" {
+			return schedules, nil
+		}
+		token = page.NextPageToken
 	}
-	r.Header.Add("Authorization", fmt.Sprintf("Bearer %s", os.Getenv("AUTH_TOKEN")))
-	res, err := http.DefaultClient.Do(r)
+}
+
+func dump_schedules(ctx *cli.Context) error {
+	schedules, err := list_schedules(ctx, url.Values{
+		"request_kind": {ctx.String("request-kind")},
+		"owner_email":  {ctx.String("owner-email")},
+		"tier":         {ctx.String("tier")},
+	})
 	if err != nil {
 		return err
 	}
-	defer res.Body.Close()
-	if res.StatusCode != http.StatusOK {
-		return fmt.Errorf("bad response from server: %s", res.Status)
-	}
-	b, err := io.ReadAll(res.Body)
+	b, err := json.Marshal(schedules)
 	if err != nil {
 		return err
 	}
@@ -51,29 +97,7 @@ func delete_all_schedules(ctx *cli.Context) error {
 	if !c || err != nil {
 		return fmt.Errorf("confirmation failed, aborting")
 	}
-	r, err := http.NewRequest(http.MethodGet, ctx.String("endpoint")+"/schedule", nil)
-	if err != nil {
-		return err
-	}
-	r.Header.Add("Authorization", fmt.Sprintf("Bearer %s", os.Getenv("AUTH_TOKEN")))
-	res, err := http.DefaultClient.Do(r)
-	if err != nil {
-		return err
-	}
-	defer res.Body.Close()
-	if res.StatusCode != http.StatusOK {
-		return fmt.Errorf("bad response from server: %s", res.Status)
-	}
-	b, err := io.ReadAll(res.Body)
-	if err != nil {
-		return err
-	}
-
-	var schedules []struct {
-		ID   string              `json:"ID"`
-		Spec client.ScheduleSpec `json:"Spec"`
-	}
-	err = json.Unmarshal(b, &schedules)
+	schedules, err := list_schedules(ctx, url.Values{})
 	if err != nil {
 		return err
 	}
@@ -95,7 +119,7 @@ func delete_all_schedules(ctx *cli.Context) error {
 			continue
 		}
 		defer res.Body.Close()
-		b, err = io.ReadAll(res.Body)
+		b, err := io.ReadAll(res.Body)
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "error reading response for schedule delete %d (%s): %s\n", i, sched.ID, err.Error())
 			continue
@@ -119,20 +143,30 @@ func load_schedules(ctx *cli.Context) error {
 	if err != nil {
 		return err
 	}
-	var body []struct {
-		ID   string              `json:"ID"`
-		Spec client.ScheduleSpec `json:"Spec"`
-	}
+	var body []api.ScheduleListEntry
 	err = json.Unmarshal(b, &body)
 	if err != nil {
 		return err
 	}
 	for i, sched := range body {
-		parts := strings.Split(sched.ID, " ")
+		// dumps made before schedules had search attributes only have the
+		// schedule ID ("<request_kind> <id> <hash>")
+		if sched.RequestKind == "" {
+			parts := strings.SplitN(sched.ID, " ", 3)
+			if len(parts) < 3 {
+				fmt.Fprintf(os.Stderr, "unexpected schedule id %d (%s)\n", i, sched.ID)
+				continue
+			}
+			sched.RequestKind, sched.EntityID = parts[0], parts[1]
+		}
+		if sched.Spec == nil {
+			fmt.Fprintf(os.Stderr, "schedule %d (%s) has no spec\n", i, sched.ID)
+			continue
+		}
 		payload := api.GenericScheduleRequestPayload{
-			RequestKind: parts[0],
-			ID:          parts[1],
-			Schedule:    sched.Spec,
+			RequestKind: sched.RequestKind,
+			ID:          sched.EntityID,
+			Schedule:    *sched.Spec,
 		}
 		b, err := json.Marshal(payload)
 		if err != nil {
@@ -185,13 +219,11 @@ func load_schedules(ctx *cli.Context) error {
 }
 
 func create_schedule(ctx *cli.Context) error {
-	rk := ctx.String("request-kind")
-	id := ctx.String("id")
-	sched := kt.GetDefaultScheduleSpec(rk, id)
 	payload := api.GenericScheduleRequestPayload{
-		RequestKind: rk,
-		ID:          id,
-		Schedule:    sched,
+		RequestKind:  ctx.String("request-kind"),
+		ID:           ctx.String("id"),
+		Preset:       ctx.String("preset"),
+		FixedCadence: ctx.Bool("fixed-cadence"),
 	}
 	b, err := json.Marshal(payload)
 	if err != nil {
@@ -241,111 +273,202 @@ func delete_schedule(ctx *cli.Context) error {
 	return nil
 }
 
-// This will read a schedule dump file, iterate over the schedules, and for any
-// schedules matching the supplied request kind, it will delete the existing
-// schedule and immediately re-create it. This is useful for situations such as
-// after we've changed the default schedule configuration and we'd like to
-// replace existing schedules with schedules that have the new configuration.
+// Resets every schedule of the supplied request kind to the kind's current
+// default spec in place, keeping their EndAt. This is useful for situations
+// such as after we've changed the default schedule configuration and we'd like
+// existing schedules to pick up the new configuration.
 func reupload_schedules(ctx *cli.Context) error {
-	b, err := os.ReadFile(ctx.String("file"))
+	p := api.ScheduleUpdatePayload{
+		Action:      api.ScheduleActionUpdateSpec,
+		RequestKind: ctx.String("request-kind"),
+		Preset:      kt.SchedulePresetDefault,
+	}
+	body, err := json.Marshal(p)
+	if err != nil {
+		return fmt.Errorf("could not serialize payload: %w", err)
+	}
+	r, err := http.NewRequest(http.MethodPut, ctx.String("endpoint")+"/schedule/bulk", bytes.NewReader(body))
 	if err != nil {
 		return err
 	}
-	var body []struct {
-		ID   string              `json:"ID"`
-		Spec client.ScheduleSpec `json:"Spec"`
+	return do_request_print_body(r)
+}
+
+func describe_schedule(ctx *cli.Context) error {
+	r, err := http.NewRequest(
+		http.MethodGet,
+		ctx.String("endpoint")+"/schedule/"+url.PathEscape(ctx.String("schedule-id")),
+		nil,
+	)
+	if err != nil {
+		return err
 	}
-	err = json.Unmarshal(b, &body)
+	return do_request_print_body(r)
+}
+
+// Applies an action to a single schedule, or in bulk to the listed schedules
+// or to every schedule of a request kind (optionally only those for the
+// supplied IDs).
+func update_schedules(ctx *cli.Context) error {
+	p := api.ScheduleUpdatePayload{
+		Action:      ctx.String("action"),
+		RequestKind: ctx.String("request-kind"),
+		IDs:         ctx.StringSlice("id"),
+		Note:        ctx.String("note"),
+		ExtendBy:    ctx.String("extend-by"),
+		Preset:      ctx.String("preset"),
+	}
+	if ctx.String("end-at") != "" {
+		t, err := time.Parse(time.RFC3339, ctx.String("end-at"))
+		if err != nil {
+			return fmt.Errorf("bad end-at: %w", err)
+		}
+		p.EndAt = &t
+	}
+	if ctx.String("spec-file") != "" {
+		b, err := os.ReadFile(ctx.String("spec-file"))
+		if err != nil {
+			return err
+		}
+		var spec client.ScheduleSpec
+		if err = json.Unmarshal(b, &spec); err != nil {
+			return fmt.Errorf("could not parse spec file: %w", err)
+		}
+		p.Schedule = &spec
+	}
+	path := "/schedule/bulk"
+	sids := ctx.StringSlice("schedule-id")
+	if len(sids) == 1 && p.RequestKind == "" {
+		path = "/schedule"
+		p.ScheduleID = sids[0]
+	} else {
+		p.ScheduleIDs = sids
+	}
+	body, err := json.Marshal(p)
+	if err != nil {
+		return fmt.Errorf("could not serialize payload: %w", err)
+	}
+	r, err := http.NewRequest(http.MethodPut, ctx.String("endpoint")+path, bytes.NewReader(body))
 	if err != nil {
 		return err
 	}
+	return do_request_print_body(r)
+}
 
-	// iterate over all the schedules in the file
-	for i, sched := range body {
+// Prints the projected number of runs per kind over the horizon.
+func schedule_capacity(ctx *cli.Context) error {
+	r, err := http.NewRequest(http.MethodGet, ctx.String("endpoint")+"/schedule/capacity", nil)
+	if err != nil {
+		return err
+	}
+	q := r.URL.Query()
+	q.Add("horizon", ctx.String("horizon"))
+	q.Add("bucket", ctx.String("bucket"))
+	if ctx.String("request-kind") != "" {
+		q.Add("request_kind", ctx.String("request-kind"))
+	}
+	r.URL.RawQuery = q.Encode()
+	return do_request_print_body(r)
+}
 
-		parts := strings.Split(sched.ID, " ")
-		rk := parts[0]
-		id := parts[1]
+func watch_schedule_expiry(ctx *cli.Context) error {
+	b, err := json.Marshal(api.ScheduleWatchExpiryPayload{RequestKind: ctx.String("request-kind")})
+	if err != nil {
+		return err
+	}
+	r, err := http.NewRequest(http.MethodPost, ctx.String("endpoint")+"/schedule/watch-expiry", bytes.NewReader(b))
+	if err != nil {
+		return err
+	}
+	return do_request_print_body(r)
+}
 
-		// no-op if the schedule doesn't match the supplied kind
-		if rk != ctx.String("request-kind") {
-			continue
+// Prints the next runs of a preset or a spec file for a request kind without
+// creating a schedule. The spec is validated for the kind, too.
+func preview_schedule(ctx *cli.Context) error {
+	rk := ctx.String("request-kind")
+	var spec client.ScheduleSpec
+	if ctx.String("spec-file") != "" {
+		b, err := os.ReadFile(ctx.String("spec-file"))
+		if err != nil {
+			return err
+		}
+		if err = json.Unmarshal(b, &spec); err != nil {
+			return fmt.Errorf("could not parse spec file: %w", err)
+		}
+		if err = kt.ValidateScheduleSpec(rk, spec); err != nil {
+			return fmt.Errorf("invalid spec: %w", err)
+		}
+	} else {
+		var err error
+		if spec, err = kt.GetSchedulePreset(rk, ctx.String("preset")); err != nil {
+			return err
 		}
+	}
+	sp, err := schedspec.New(spec)
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	runs := sp.Runs(now, now.Add(ctx.Duration("horizon")), ctx.Int("n"))
+	for _, t := range runs {
+		fmt.Println(t.Format(time.RFC3339))
+	}
+	if len(runs) == 0 {
+		fmt.Fprintln(os.Stderr, "no runs in the horizon")
+	}
+	if j := sp.Jitter(); j > 0 {
+		fmt.Fprintf(os.Stderr, "each run may be delayed by up to %s (jitter)\n", j)
+	}
+	return nil
+}
 
-		// first delete
-		r, err := http.NewRequest(http.MethodDelete, ctx.String("endpoint")+"/schedule", nil)
+// Lists workflow executions (one page, or every page with --all) matching
+// the filters.
+func list_workflows(ctx *cli.Context) error {
+	token := ""
+	for {
+		r, err := http.NewRequest(http.MethodGet, ctx.String("endpoint")+"/workflow", nil)
 		if err != nil {
 			return err
 		}
-		r.Header.Add("Authorization", fmt.Sprintf("Bearer %s", os.Getenv("AUTH_TOKEN")))
-		q := r.URL.Query()
-		q.Add("schedule_id", sched.ID)
+		q := url.Values{}
+		for _, k := range []string{"request-kind", "id", "owner-email", "tier", "status"} {
+			if v := ctx.String(k); v != "" {
+				q.Add(strings.ReplaceAll(k, "-", "_"), v)
+			}
+		}
+		q.Add("page_size", strconv.Itoa(ctx.Int("page-size")))
+		if token != "" {
+			q.Add("page_token", token)
+		}
 		r.URL.RawQuery = q.Encode()
+		r.Header.Add("Authorization", fmt.Sprintf("Bearer %s", os.Getenv("AUTH_TOKEN")))
 		res, err := http.DefaultClient.Do(r)
 		if err != nil {
 			return err
 		}
-		defer res.Body.Close()
 		b, err := io.ReadAll(res.Body)
+		res.Body.Close()
 		if err != nil {
 			return err
 		}
 		if res.StatusCode != http.StatusOK {
-			return fmt.Errorf("bad response from server: %d: %s", res.StatusCode, string(b))
-		}
-
-		// now re-upload using a (new) default schedule spec
-		payload := api.GenericScheduleRequestPayload{
-			RequestKind: rk,
-			ID:          id,
-			Schedule:    kt.GetDefaultScheduleSpec(rk, id),
+			return fmt.Errorf("bad response from server: %s: %s", res.Status, b)
 		}
-		b, err = json.Marshal(payload)
-		if err != nil {
-			fmt.Fprintf(os.Stderr, "error creating payload for schedule %d (%s): %s\n", i, sched.ID, err.Error())
-			continue
-		}
-		r, err = http.NewRequest(
-			http.MethodPost,
-			ctx.String("endpoint")+"/schedule",
-			bytes.NewReader(b),
-		)
-		if err != nil {
-			fmt.Fprintf(os.Stderr, "error making request to schedule %d (%s): %s\n", i, sched.ID, err.Error())
-			continue
-		}
-
-		r.Header.Add("Authorization", fmt.Sprintf("Bearer %s", os.Getenv("AUTH_TOKEN")))
-
-		// NOTE: don't block for the metadata operation. We don't want that to
-		// impact the schedule creation here because this is typically used to
-		// bulk (re)create schedules. If you want to bulk create schedules that
-		// need their metadata fetched, then you'll need to update the CLI to
-		// accept that as an additional flag and pass it here.
-		q = r.URL.Query()
-		q.Add("skip-metadata", "true")
-		r.URL.RawQuery = q.Encode()
-		res, err = http.DefaultClient.Do(r)
-		if err != nil {
-			fmt.Fprintf(os.Stderr, "error uploading schedule %d (%s): %s\n", i, sched.ID, err.Error())
-			continue
+		var page api.WorkflowListResponse
+		if err = json.Unmarshal(b, &page); err != nil {
+			return err
 		}
-		defer res.Body.Close()
-		b, err = io.ReadAll(res.Body)
-		if err != nil {
-			fmt.Fprintf(os.Stderr, "error reading response for schedule upload %d (%s): %s\n", i, sched.ID, err.Error())
-			continue
+		for _, wf := range page.Workflows {
+			fmt.Printf("%s\t%s\t%s\t%s\n", wf.StartTime.Format(time.RFC3339), wf.Status, wf.WorkflowType, wf.WorkflowID)
 		}
-		var rbody api.DefaultJSONResponse
-		err = json.Unmarshal(b, &rbody)
-		if err != nil {
-			fmt.Fprintf(os.Stderr, "error parsing \"%s\" response for schedule upload %d (%s): %s\n", res.Status, i, sched.ID, err.Error())
-			continue
-		}
-		if res.StatusCode != http.StatusOK {
-			fmt.Fprintf(os.Stderr, "%s response uploading schedule %d (%s): %s\n", res.Status, i, sched.ID, rbody.Error)
-			continue
+		if page.NextPageToken == "" || !ctx.Bool("all") {
+			if page.NextPageToken != "" {
+				fmt.Fprintf(os.Stderr, "more results; use --all to fetch every page\n")
+			}
+			return nil
 		}
+		token = page.NextPageToken
 	}
-	return nil
 }
@@ -1,9 +1,13 @@
 package main
 
 import (
+	"fmt"
 	"log/slog"
+	"os"
+	"strings"
 
 	"github.com/brojonat/kaggo/server"
+	kt "github.com/brojonat/kaggo/temporal/v19700101"
 	"github.com/brojonat/kaggo/worker"
 	"github.com/urfave/cli/v2"
 )
@@ -26,5 +30,17 @@ func serve_http(ctx *cli.Context) error {
 func run_worker(ctx *cli.Context) error {
 	logger := getDefaultLogger(slog.Level(ctx.Int("log-level")))
 	thp := ctx.String("temporal-host")
-	return worker.RunWorker(ctx.Context, logger, thp)
+	if dir := ctx.String("extractor-dir"); dir != "" {
+		if err := kt.LoadExtractorSpecs(os.DirFS(dir)); err != nil {
+			return fmt.Errorf("error loading extractor specs: %w", err)
+		}
+		logger.Info("loaded extractor specs", "dir", dir)
+	}
+	if kinds := ctx.String("batch-kinds"); kinds != "" {
+		if err := kt.SetBatchPollingKinds(strings.Split(kinds, ",")); err != nil {
+			return fmt.Errorf("error enabling batched polling: %w", err)
+		}
+		logger.Info("enabled batched polling", "request_kinds", kinds)
+	}
+	return worker.RunWorker(ctx.Context, logger, thp, ctx.String("database"), ctx.String("archive"), ctx.Bool("direct-ingest"))
 }
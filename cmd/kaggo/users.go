@@ -164,3 +164,34 @@ func remove_metric(ctx *cli.Context) error {
 	}
 	return nil
 }
+
+// Issues a token for the user. This uses basic auth with the server secret,
+// so it must be run by someone with access to SERVER_SECRET_KEY.
+func issue_token(ctx *cli.Context) error {
+	r, err := http.NewRequest(http.MethodPost, ctx.String("endpoint")+"/token", nil)
+	if err != nil {
+		return err
+	}
+	q := r.URL.Query()
+	q.Add("role", ctx.String("role"))
+	r.URL.RawQuery = q.Encode()
+	r.SetBasicAuth(ctx.String("email"), os.Getenv("SERVER_SECRET_KEY"))
+	res, err := http.DefaultClient.Do(r)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return err
+	}
+	if res.StatusCode != http.StatusOK {
+		return fmt.Errorf("bad response from server: %s: %s", res.Status, body)
+	}
+	var resp api.DefaultJSONResponse
+	if err = json.Unmarshal(body, &resp); err != nil {
+		return fmt.Errorf("could not parse response: %w", err)
+	}
+	fmt.Println(resp.Message)
+	return nil
+}
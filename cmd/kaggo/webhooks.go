@@ -0,0 +1,72 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/brojonat/kaggo/server/api"
+	"github.com/urfave/cli/v2"
+)
+
+func add_webhook(ctx *cli.Context) error {
+	p := api.CreateWebhookPayload{
+		Email:  ctx.String("email"),
+		URL:    ctx.String("url"),
+		Events: ctx.StringSlice("event"),
+	}
+	body, err := json.Marshal(p)
+	if err != nil {
+		return fmt.Errorf("could not serialize payload: %w", err)
+	}
+	r, err := http.NewRequest(
+		http.MethodPost,
+		ctx.String("endpoint")+"/webhooks",
+		bytes.NewReader(body),
+	)
+	if err != nil {
+		return err
+	}
+	return do_request_print_body(r)
+}
+
+func list_webhooks(ctx *cli.Context) error {
+	r, err := http.NewRequest(http.MethodGet, ctx.String("endpoint")+"/webhooks", nil)
+	if err != nil {
+		return err
+	}
+	q := r.URL.Query()
+	q.Add("email", ctx.String("email"))
+	r.URL.RawQuery = q.Encode()
+	return do_request_print_body(r)
+}
+
+func delete_webhooks(ctx *cli.Context) error {
+	r, err := http.NewRequest(http.MethodDelete, ctx.String("endpoint")+"/webhooks", nil)
+	if err != nil {
+		return err
+	}
+	q := r.URL.Query()
+	q.Add("email", ctx.String("email"))
+	for _, id := range ctx.Int64Slice("webhook-id") {
+		q.Add("webhook_id", strconv.FormatInt(id, 10))
+	}
+	r.URL.RawQuery = q.Encode()
+	return do_request_print_body(r)
+}
+
+func webhook_dead_letters(ctx *cli.Context) error {
+	r, err := http.NewRequest(http.MethodGet, ctx.String("endpoint")+"/webhooks/dead-letters", nil)
+	if err != nil {
+		return err
+	}
+	q := r.URL.Query()
+	q.Add("email", ctx.String("email"))
+	if dur := ctx.String("dur"); dur != "" {
+		q.Add("dur", dur)
+	}
+	r.URL.RawQuery = q.Encode()
+	return do_request_print_body(r)
+}
@@ -0,0 +1,162 @@
+package alerts
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/brojonat/kaggo/server/db/dbgen"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const (
+	// DefaultCooldown is the cooldown of rules that don't set one.
+	DefaultCooldown = time.Hour
+	// DeliveryTimeout bounds delivering (and recording) a single alert.
+	DeliveryTimeout = 30 * time.Second
+	// Samples older than MaxSampleAge don't trigger alerts; they're being
+	// backfilled, and whatever they'd have alerted on is long gone.
+	MaxSampleAge = 15 * time.Minute
+)
+
+// Alerter evaluates alert rules as metric samples are ingested and delivers
+// the resulting alerts through its notifiers. The server evaluates the samples
+// uploaded to it; workers that write samples straight to the database
+// evaluate their own.
+type Alerter struct {
+	l         *slog.Logger
+	q         *dbgen.Queries
+	notifiers map[string]Notifier
+}
+
+func NewAlerter(l *slog.Logger, q *dbgen.Queries, ns map[string]Notifier) *Alerter {
+	return &Alerter{l: l, q: q, notifiers: ns}
+}
+
+// Notifier returns the notifier of the supplied kind, if it's configured.
+func (a *Alerter) Notifier(kind string) (Notifier, bool) {
+	n, ok := a.notifiers[kind]
+	return n, ok
+}
+
+// Evaluate checks every enabled rule on the supplied samples, which were just
+// written and observed at ts; samples older than MaxSampleAge are ignored.
+// Errors are logged rather than returned; a broken rule shouldn't fail
+// ingestion.
+func (a *Alerter) Evaluate(ctx context.Context, rk, id string, ms map[string]float64, ts time.Time) {
+	if len(ms) == 0 || time.Since(ts) >= MaxSampleAge {
+		return
+	}
+	for m, v := range ms {
+		rules, err := a.q.GetAlertRulesForMetric(ctx, dbgen.GetAlertRulesForMetricParams{
+			RequestKind: rk,
+			ID:          id,
+			Metric:      m,
+		})
+		if err != nil {
+			a.l.Error("error getting alert rules", "request_kind", rk, "id", id, "metric", m, "error", err.Error())
+			continue
+		}
+		for _, rule := range rules {
+			if err := a.evaluateRule(ctx, rule, v, ts); err != nil {
+				a.l.Error("error evaluating alert rule", "rule_id", rule.RuleID, "error", err.Error())
+			}
+		}
+	}
+}
+
+func (a *Alerter) evaluateRule(ctx context.Context, rule dbgen.AlertRule, v float64, ts time.Time) error {
+	// this is only a shortcut; the cooldown is enforced when the rule is marked
+	// as fired below
+	cooldown := time.Duration(rule.CooldownSeconds) * time.Second
+	if rule.TsLastFired.Valid && ts.Sub(rule.TsLastFired.Time) < cooldown {
+		return nil
+	}
+
+	var ws WindowStats
+	if NeedsWindow(rule.Condition) {
+		window, err := ParseWindow(rule.Params)
+		if err != nil {
+			return err
+		}
+		row, err := a.q.GetMetricSampleWindowStats(ctx, dbgen.GetMetricSampleWindowStatsParams{
+			RequestKind: rule.RequestKind,
+			ID:          rule.ID,
+			Metric:      rule.Metric,
+			TsStart:     pgtype.Timestamptz{Time: ts.Add(-window), Valid: true},
+			TsEnd:       pgtype.Timestamptz{Time: ts, Valid: true},
+		})
+		if err != nil {
+			return fmt.Errorf("error getting window stats: %w", err)
+		}
+		ws = WindowStats{
+			Count:  int(row.Count),
+			First:  row.First,
+			Mean:   row.Mean,
+			StdDev: row.Stddev,
+		}
+	}
+
+	fire, msg := Evaluate(rule.Condition, rule.Params, v, ws)
+	if !fire {
+		return nil
+	}
+
+	// Mark the rule as fired before delivering. The update only succeeds
+	// outside the cooldown, so if concurrent ingests both get here, only one of
+	// them delivers.
+	_, err := a.q.SetAlertRuleLastFired(ctx, dbgen.SetAlertRuleLastFiredParams{
+		Ts:     pgtype.Timestamptz{Time: ts, Valid: true},
+		RuleID: rule.RuleID,
+	})
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("error setting last fired: %w", err)
+	}
+	alert := Alert{
+		RuleID:      rule.RuleID,
+		Email:       rule.Email,
+		RequestKind: rule.RequestKind,
+		ID:          rule.ID,
+		Metric:      rule.Metric,
+		Condition:   rule.Condition,
+		Value:       v,
+		Ts:          ts,
+		Message:     fmt.Sprintf("%s.%s %s: %s", rule.RequestKind, rule.Metric, rule.ID, msg),
+	}
+	go a.deliver(rule, alert)
+	return nil
+}
+
+// deliver sends the alert and records it in the alert history along with the
+// delivery error, if any. This runs outside of the ingest request.
+func (a *Alerter) deliver(rule dbgen.AlertRule, alert Alert) {
+	ctx, cancel := context.WithTimeout(context.Background(), DeliveryTimeout)
+	defer cancel()
+
+	var derr string
+	n, ok := a.notifiers[rule.Notifier]
+	if !ok {
+		derr = fmt.Sprintf("notifier %s is not configured", rule.Notifier)
+	} else if err := n.Notify(ctx, rule.Target, alert); err != nil {
+		derr = err.Error()
+	}
+	if derr != "" {
+		a.l.Error("error delivering alert", "rule_id", rule.RuleID, "error", derr)
+	}
+	err := a.q.InsertAlertHistory(ctx, dbgen.InsertAlertHistoryParams{
+		RuleID:  rule.RuleID,
+		Ts:      pgtype.Timestamptz{Time: alert.Ts, Valid: true},
+		Value:   alert.Value,
+		Message: alert.Message,
+		Error:   derr,
+	})
+	if err != nil {
+		a.l.Error("error recording alert", "rule_id", rule.RuleID, "error", err.Error())
+	}
+}
@@ -0,0 +1,163 @@
+package alerts
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/brojonat/kaggo/server/db/dbgen"
+	"github.com/brojonat/kaggo/server/db/jsonb"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// fakeDB is a dbgen.DBTX that records Exec and QueryRow calls; queries are
+// counted and fail. Evaluating a threshold rule doesn't need anything else.
+// QueryRow is only used to mark a rule as fired, which fails with no rows if
+// firedElsewhere is set (i.e., a concurrent ingest got there first).
+type fakeDB struct {
+	mu             sync.Mutex
+	execs          []string
+	queries        int
+	firedElsewhere bool
+}
+
+func (db *fakeDB) Exec(ctx context.Context, sql string, args ...interface{}) (pgconn.CommandTag, error) {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+	db.execs = append(db.execs, sql)
+	return pgconn.CommandTag{}, nil
+}
+
+func (db *fakeDB) Query(ctx context.Context, sql string, args ...interface{}) (pgx.Rows, error) {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+	db.queries++
+	return nil, errors.New("not implemented")
+}
+
+func (db *fakeDB) QueryRow(ctx context.Context, sql string, args ...interface{}) pgx.Row {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+	db.execs = append(db.execs, sql)
+	if db.firedElsewhere {
+		return fakeRow{err: pgx.ErrNoRows}
+	}
+	return fakeRow{}
+}
+
+// fakeRow leaves the destinations alone and fails with err, if it's set.
+type fakeRow struct {
+	err error
+}
+
+func (r fakeRow) Scan(dest ...any) error {
+	return r.err
+}
+
+func (db *fakeDB) CopyFrom(ctx context.Context, tableName pgx.Identifier, columnNames []string, rowSrc pgx.CopyFromSource) (int64, error) {
+	return 0, errors.New("not implemented")
+}
+
+func (db *fakeDB) count() int {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+	return len(db.execs)
+}
+
+// fakeNotifier hands the alerts it's asked to deliver to a channel.
+type fakeNotifier struct {
+	alerts chan Alert
+}
+
+func (n *fakeNotifier) ValidateTarget(target string) error { return nil }
+
+func (n *fakeNotifier) Notify(ctx context.Context, target string, a Alert) error {
+	n.alerts <- a
+	return nil
+}
+
+func TestEvaluateRuleCooldown(t *testing.T) {
+	now := time.Now()
+	rule := dbgen.AlertRule{
+		RuleID:          1,
+		RequestKind:     "internal.random",
+		ID:              "1234",
+		Metric:          "value",
+		Condition:       ConditionThreshold,
+		Params:          jsonb.AlertRuleParams{Op: OpAbove, Threshold: 10},
+		Notifier:        "fake",
+		CooldownSeconds: 3600,
+	}
+	cases := []struct {
+		name           string
+		lastFired      time.Time
+		firedElsewhere bool
+		v              float64
+		fire           bool
+		writes         int
+	}{
+		{"never fired", time.Time{}, false, 11, true, 2},
+		{"within cooldown", now.Add(-30 * time.Minute), false, 11, false, 0},
+		{"after cooldown", now.Add(-2 * time.Hour), false, 11, true, 2},
+		{"below threshold", time.Time{}, false, 9, false, 0},
+		{"fired by a concurrent ingest", time.Time{}, true, 11, false, 1},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			db := &fakeDB{firedElsewhere: c.firedElsewhere}
+			n := &fakeNotifier{alerts: make(chan Alert, 1)}
+			a := NewAlerter(slog.New(slog.NewTextHandler(io.Discard, nil)), dbgen.New(db), map[string]Notifier{"fake": n})
+			r := rule
+			r.TsLastFired = pgtype.Timestamptz{Time: c.lastFired, Valid: !c.lastFired.IsZero()}
+			if err := a.evaluateRule(context.Background(), r, c.v, now); err != nil {
+				t.Fatal(err)
+			}
+			if !c.fire {
+				if db.count() != c.writes {
+					t.Errorf("expected %d writes, got %d", c.writes, db.count())
+				}
+				select {
+				case alert := <-n.alerts:
+					t.Errorf("expected no alert, got %+v", alert)
+				case <-time.After(10 * time.Millisecond):
+				}
+				return
+			}
+			select {
+			case alert := <-n.alerts:
+				if alert.RuleID != rule.RuleID || alert.Value != c.v || !alert.Ts.Equal(now) {
+					t.Errorf("unexpected alert %+v", alert)
+				}
+			case <-time.After(time.Second):
+				t.Fatal("expected the alert to be delivered")
+			}
+			// last fired, then the history entry once it's delivered
+			deadline := time.Now().Add(time.Second)
+			for db.count() < 2 && time.Now().Before(deadline) {
+				time.Sleep(time.Millisecond)
+			}
+			if db.count() != c.writes {
+				t.Errorf("expected %d writes, got %d", c.writes, db.count())
+			}
+		})
+	}
+}
+
+func TestEvaluateIgnoresOldSamples(t *testing.T) {
+	db := &fakeDB{}
+	a := NewAlerter(slog.New(slog.NewTextHandler(io.Discard, nil)), dbgen.New(db), nil)
+	a.Evaluate(context.Background(), "internal.random", "1234", map[string]float64{"value": 1}, time.Now().Add(-MaxSampleAge))
+	if db.queries != 0 {
+		t.Errorf("expected the rules not to be queried, got %d queries", db.queries)
+	}
+	a.Evaluate(context.Background(), "internal.random", "1234", map[string]float64{"value": 1}, time.Now())
+	if db.queries != 1 {
+		t.Errorf("expected the rules to be queried once, got %d queries", db.queries)
+	}
+}
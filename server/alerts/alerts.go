@@ -0,0 +1,131 @@
+// Package alerts evaluates alert rules against incoming metric samples and
+// delivers the resulting alerts through pluggable notifiers.
+package alerts
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/brojonat/kaggo/server/db/jsonb"
+)
+
+// Supported rule conditions.
+const (
+	ConditionThreshold = "threshold"
+	ConditionPctChange = "pct-change"
+	ConditionZScore    = "z-score"
+)
+
+// Supported threshold comparisons.
+const (
+	OpAbove = "above"
+	OpBelow = "below"
+)
+
+// The minimum number of samples in the window before a z-score rule is
+// evaluated, unless the rule says otherwise.
+const DefaultMinSamples = 10
+
+// Alert is a single rule firing. This is what notifiers deliver.
+type Alert struct {
+	RuleID      int64     `json:"rule_id"`
+	Email       string    `json:"email"`
+	RequestKind string    `json:"request_kind"`
+	ID          string    `json:"id"`
+	Metric      string    `json:"metric"`
+	Condition   string    `json:"condition"`
+	Value       float64   `json:"value"`
+	Ts          time.Time `json:"ts"`
+	Message     string    `json:"message"`
+}
+
+// WindowStats summarize the samples in a rule's lookback window, not including
+// the sample being evaluated. First is the oldest sample in the window.
+type WindowStats struct {
+	Count  int
+	First  float64
+	Mean   float64
+	StdDev float64
+}
+
+// NeedsWindow reports whether the condition is evaluated against a lookback
+// window.
+func NeedsWindow(cond string) bool {
+	return cond == ConditionPctChange || cond == ConditionZScore
+}
+
+// ParseWindow returns the lookback window of the rule.
+func ParseWindow(p jsonb.AlertRuleParams) (time.Duration, error) {
+	d, err := time.ParseDuration(p.Window)
+	if err != nil {
+		return 0, fmt.Errorf("bad window %q: %w", p.Window, err)
+	}
+	if d <= 0 {
+		return 0, fmt.Errorf("window must be positive")
+	}
+	return d, nil
+}
+
+// ValidateRule checks that the params are sensible for the condition.
+func ValidateRule(cond string, p jsonb.AlertRuleParams) error {
+	switch cond {
+	case ConditionThreshold:
+		if p.Op != OpAbove && p.Op != OpBelow {
+			return fmt.Errorf("threshold op must be %q or %q", OpAbove, OpBelow)
+		}
+		return nil
+	case ConditionPctChange:
+		if p.Pct <= 0 {
+			return fmt.Errorf("pct must be positive")
+		}
+		_, err := ParseWindow(p)
+		return err
+	case ConditionZScore:
+		if p.ZScore <= 0 {
+			return fmt.Errorf("z_score must be positive")
+		}
+		if p.MinSamples < 0 {
+			return fmt.Errorf("min_samples must not be negative")
+		}
+		_, err := ParseWindow(p)
+		return err
+	}
+	return fmt.Errorf("unsupported condition: %s", cond)
+}
+
+// Evaluate reports whether the value v satisfies the rule's condition. If it
+// does, the returned string describes why. The window stats are ignored for
+// conditions that don't need them.
+func Evaluate(cond string, p jsonb.AlertRuleParams, v float64, ws WindowStats) (bool, string) {
+	switch cond {
+	case ConditionThreshold:
+		if p.Op == OpAbove && v > p.Threshold {
+			return true, fmt.Sprintf("value %g is above %g", v, p.Threshold)
+		}
+		if p.Op == OpBelow && v < p.Threshold {
+			return true, fmt.Sprintf("value %g is below %g", v, p.Threshold)
+		}
+	case ConditionPctChange:
+		if ws.Count == 0 || ws.First == 0 {
+			return false, ""
+		}
+		pct := 100 * (v - ws.First) / math.Abs(ws.First)
+		if math.Abs(pct) >= p.Pct {
+			return true, fmt.Sprintf("value %g changed %+.1f%% from %g over %s", v, pct, ws.First, p.Window)
+		}
+	case ConditionZScore:
+		min := p.MinSamples
+		if min == 0 {
+			min = DefaultMinSamples
+		}
+		if ws.Count < min || ws.StdDev == 0 {
+			return false, ""
+		}
+		z := (v - ws.Mean) / ws.StdDev
+		if math.Abs(z) >= p.ZScore {
+			return true, fmt.Sprintf("value %g has a z-score of %+.2f over %s (mean %g, stddev %g)", v, z, p.Window, ws.Mean, ws.StdDev)
+		}
+	}
+	return false, ""
+}
@@ -0,0 +1,76 @@
+package alerts
+
+import (
+	"testing"
+
+	"github.com/brojonat/kaggo/server/db/jsonb"
+)
+
+func TestEvaluate(t *testing.T) {
+	above := jsonb.AlertRuleParams{Op: OpAbove, Threshold: 100}
+	below := jsonb.AlertRuleParams{Op: OpBelow, Threshold: 100}
+	pct := jsonb.AlertRuleParams{Pct: 50, Window: "24h"}
+	z := jsonb.AlertRuleParams{ZScore: 3, Window: "24h"}
+	cases := []struct {
+		name string
+		cond string
+		p    jsonb.AlertRuleParams
+		v    float64
+		ws   WindowStats
+		fire bool
+	}{
+		{"above fires", ConditionThreshold, above, 101, WindowStats{}, true},
+		{"above at threshold", ConditionThreshold, above, 100, WindowStats{}, false},
+		{"above under threshold", ConditionThreshold, above, 99, WindowStats{}, false},
+		{"below fires", ConditionThreshold, below, 99, WindowStats{}, true},
+		{"below over threshold", ConditionThreshold, below, 101, WindowStats{}, false},
+		{"pct rise", ConditionPctChange, pct, 150, WindowStats{Count: 5, First: 100}, true},
+		{"pct drop", ConditionPctChange, pct, 50, WindowStats{Count: 5, First: 100}, true},
+		{"pct small change", ConditionPctChange, pct, 120, WindowStats{Count: 5, First: 100}, false},
+		{"pct empty window", ConditionPctChange, pct, 150, WindowStats{}, false},
+		{"pct zero first", ConditionPctChange, pct, 150, WindowStats{Count: 5}, false},
+		{"z fires", ConditionZScore, z, 40, WindowStats{Count: 10, Mean: 10, StdDev: 10}, true},
+		{"z within", ConditionZScore, z, 30, WindowStats{Count: 10, Mean: 10, StdDev: 10}, false},
+		{"z too few samples", ConditionZScore, z, 40, WindowStats{Count: 9, Mean: 10, StdDev: 10}, false},
+		{"z min samples", ConditionZScore, jsonb.AlertRuleParams{ZScore: 3, Window: "24h", MinSamples: 2}, 40, WindowStats{Count: 2, Mean: 10, StdDev: 10}, true},
+		{"z no spread", ConditionZScore, z, 40, WindowStats{Count: 10, Mean: 10}, false},
+		{"unknown condition", "nope", above, 101, WindowStats{}, false},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			fire, msg := Evaluate(c.cond, c.p, c.v, c.ws)
+			if fire != c.fire {
+				t.Fatalf("expected fire=%v, got %v (%s)", c.fire, fire, msg)
+			}
+			if fire && msg == "" {
+				t.Errorf("expected a message")
+			}
+		})
+	}
+}
+
+func TestValidateRule(t *testing.T) {
+	cases := []struct {
+		name string
+		cond string
+		p    jsonb.AlertRuleParams
+		ok   bool
+	}{
+		{"threshold", ConditionThreshold, jsonb.AlertRuleParams{Op: OpAbove}, true},
+		{"threshold bad op", ConditionThreshold, jsonb.AlertRuleParams{Op: "sideways"}, false},
+		{"pct", ConditionPctChange, jsonb.AlertRuleParams{Pct: 10, Window: "1h"}, true},
+		{"pct no pct", ConditionPctChange, jsonb.AlertRuleParams{Window: "1h"}, false},
+		{"pct bad window", ConditionPctChange, jsonb.AlertRuleParams{Pct: 10, Window: "soon"}, false},
+		{"pct negative window", ConditionPctChange, jsonb.AlertRuleParams{Pct: 10, Window: "-1h"}, false},
+		{"z", ConditionZScore, jsonb.AlertRuleParams{ZScore: 3, Window: "1h"}, true},
+		{"z negative min samples", ConditionZScore, jsonb.AlertRuleParams{ZScore: 3, Window: "1h", MinSamples: -1}, false},
+		{"unknown", "nope", jsonb.AlertRuleParams{}, false},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if err := ValidateRule(c.cond, c.p); (err == nil) != c.ok {
+				t.Errorf("expected ok=%v, got %v", c.ok, err)
+			}
+		})
+	}
+}
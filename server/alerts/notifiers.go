@@ -0,0 +1,200 @@
+package alerts
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/mail"
+	"net/smtp"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+)
+
+// Supported notifier kinds. A rule's notifier is one of these and its target
+// is interpreted by the corresponding Notifier.
+const (
+	NotifierWebhook = "webhook"
+	NotifierSMTP    = "smtp"
+)
+
+// Notifier delivers alerts to a target (e.g., a URL or an email address).
+type Notifier interface {
+	// ValidateTarget is called when a rule is created.
+	ValidateTarget(target string) error
+	Notify(ctx context.Context, target string, a Alert) error
+}
+
+// GetDefaultNotifiers returns the notifiers available to alert rules. The
+// webhook notifier is always available; the SMTP notifier is only available if
+// SMTP_HOST is set (see NewSMTPNotifierFromEnv).
+func GetDefaultNotifiers() map[string]Notifier {
+	ns := map[string]Notifier{
+		NotifierWebhook: &WebhookNotifier{Client: NewPublicHTTPClient()},
+	}
+	if n := NewSMTPNotifierFromEnv(); n != nil {
+		ns[NotifierSMTP] = n
+	}
+	return ns
+}
+
+// WebhookNotifier POSTs the alert as JSON to the target URL. Any non-2xx
+// response is an error. Targets must resolve to public addresses, and Client
+// should re-check that when it connects (see NewPublicHTTPClient).
+type WebhookNotifier struct {
+	Client *http.Client
+}
+
+func (n *WebhookNotifier) ValidateTarget(target string) error {
+	u, err := url.Parse(target)
+	if err != nil {
+		return fmt.Errorf("bad webhook url: %w", err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return fmt.Errorf("bad webhook url: scheme must be http or https")
+	}
+	if u.Host == "" {
+		return fmt.Errorf("bad webhook url: missing host")
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), targetLookupTimeout)
+	defer cancel()
+	if err := checkPublicHost(ctx, u.Hostname()); err != nil {
+		return fmt.Errorf("bad webhook url: %w", err)
+	}
+	return nil
+}
+
+func (n *WebhookNotifier) Notify(ctx context.Context, target string, a Alert) error {
+	b, err := json.Marshal(a)
+	if err != nil {
+		return fmt.Errorf("error serializing alert: %w", err)
+	}
+	r, err := http.NewRequestWithContext(ctx, http.MethodPost, target, bytes.NewReader(b))
+	if err != nil {
+		return fmt.Errorf("error making webhook request: %w", err)
+	}
+	r.Header.Set("Content-Type", "application/json")
+	res, err := n.Client.Do(r)
+	if err != nil {
+		return fmt.Errorf("error doing webhook request: %w", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		b, _ = io.ReadAll(io.LimitReader(res.Body, 1024))
+		return fmt.Errorf("bad webhook response: %d: %s", res.StatusCode, b)
+	}
+	return nil
+}
+
+// SMTPNotifier emails the alert to the target address.
+type SMTPNotifier struct {
+	// Addr is the host:port of the SMTP server.
+	Addr string
+	From string
+	// Auth is optional; it's only used if the server supports AUTH.
+	Auth smtp.Auth
+}
+
+// NewSMTPNotifierFromEnv configures an SMTPNotifier from SMTP_HOST, SMTP_PORT
+// (default 587), SMTP_FROM, and optionally SMTP_USERNAME/SMTP_PASSWORD. It
+// returns nil if SMTP_HOST isn't set.
+func NewSMTPNotifierFromEnv() *SMTPNotifier {
+	host := os.Getenv("SMTP_HOST")
+	if host == "" {
+		return nil
+	}
+	port := os.Getenv("SMTP_PORT")
+	if port == "" {
+		port = "587"
+	}
+	n := &SMTPNotifier{
+		Addr: net.JoinHostPort(host, port),
+		From: os.Getenv("SMTP_FROM"),
+	}
+	if user := os.Getenv("SMTP_USERNAME"); user != "" {
+		n.Auth = smtp.PlainAuth("", user, os.Getenv("SMTP_PASSWORD"), host)
+	}
+	return n
+}
+
+func (n *SMTPNotifier) ValidateTarget(target string) error {
+	if _, err := mail.ParseAddress(target); err != nil {
+		return fmt.Errorf("bad email address: %w", err)
+	}
+	return nil
+}
+
+// Notify sends the alert to the target's address; a display name in the target
+// (e.g., "Jane <jane@example.com>") only goes in the To header.
+func (n *SMTPNotifier) Notify(ctx context.Context, target string, a Alert) error {
+	to, err := mail.ParseAddress(target)
+	if err != nil {
+		return fmt.Errorf("bad email address: %w", err)
+	}
+	host, _, err := net.SplitHostPort(n.Addr)
+	if err != nil {
+		return fmt.Errorf("bad smtp address: %w", err)
+	}
+	var d net.Dialer
+	conn, err := d.DialContext(ctx, "tcp", n.Addr)
+	if err != nil {
+		return fmt.Errorf("error dialing smtp server: %w", err)
+	}
+	if dl, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(dl)
+	}
+	c, err := smtp.NewClient(conn, host)
+	if err != nil {
+		conn.Close()
+		return fmt.Errorf("error initializing smtp client: %w", err)
+	}
+	defer c.Close()
+
+	if ok, _ := c.Extension("STARTTLS"); ok {
+		if err = c.StartTLS(&tls.Config{ServerName: host}); err != nil {
+			return fmt.Errorf("error starting tls: %w", err)
+		}
+	}
+	if ok, _ := c.Extension("AUTH"); ok && n.Auth != nil {
+		if err = c.Auth(n.Auth); err != nil {
+			return fmt.Errorf("error authenticating: %w", err)
+		}
+	}
+	if err = c.Mail(n.From); err != nil {
+		return fmt.Errorf("error setting sender: %w", err)
+	}
+	if err = c.Rcpt(to.Address); err != nil {
+		return fmt.Errorf("error setting recipient: %w", err)
+	}
+	w, err := c.Data()
+	if err != nil {
+		return fmt.Errorf("error starting message: %w", err)
+	}
+	subject := fmt.Sprintf("[kaggo] %s.%s alert for %s", a.RequestKind, a.Metric, a.ID)
+	body := strings.Join([]string{
+		"From: " + n.From,
+		"To: " + to.String(),
+		"Subject: " + subject,
+		"Content-Type: text/plain; charset=UTF-8",
+		"",
+		a.Message,
+		"",
+		fmt.Sprintf("rule: %d", a.RuleID),
+		fmt.Sprintf("condition: %s", a.Condition),
+		fmt.Sprintf("time: %s", a.Ts.Format(time.RFC3339)),
+		"",
+	}, "\r\n")
+	if _, err = w.Write([]byte(body)); err != nil {
+		return fmt.Errorf("error writing message: %w", err)
+	}
+	if err = w.Close(); err != nil {
+		return fmt.Errorf("error sending message: %w", err)
+	}
+	return c.Quit()
+}
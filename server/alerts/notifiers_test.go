@@ -0,0 +1,209 @@
+package alerts
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func testAlert() Alert {
+	return Alert{
+		RuleID:      7,
+		Email:       "owner@example.com",
+		RequestKind: "internal.random",
+		ID:          "1234",
+		Metric:      "value",
+		Condition:   ConditionThreshold,
+		Value:       11,
+		Ts:          time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+		Message:     "internal.random.value 1234: value 11 is above 10",
+	}
+}
+
+// allowPrivateTargets lets the test deliver to local servers.
+func allowPrivateTargets(t *testing.T) {
+	AllowPrivateTargets = true
+	t.Cleanup(func() { AllowPrivateTargets = false })
+}
+
+func TestWebhookNotifier(t *testing.T) {
+	allowPrivateTargets(t)
+	var got Alert
+	var contentType string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		contentType = r.Header.Get("Content-Type")
+		if err := json.NewDecoder(r.Body).Decode(&got); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+
+	n := &WebhookNotifier{Client: srv.Client()}
+	if err := n.ValidateTarget(srv.URL); err != nil {
+		t.Fatal(err)
+	}
+	a := testAlert()
+	if err := n.Notify(context.Background(), srv.URL, a); err != nil {
+		t.Fatal(err)
+	}
+	if contentType != "application/json" {
+		t.Errorf("expected a JSON request, got %q", contentType)
+	}
+	if got != a {
+		t.Errorf("expected %+v, got %+v", a, got)
+	}
+}
+
+func TestWebhookNotifierBadResponse(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "nope", http.StatusInternalServerError)
+	}))
+	defer srv.Close()
+
+	n := &WebhookNotifier{Client: srv.Client()}
+	err := n.Notify(context.Background(), srv.URL, testAlert())
+	if err == nil || !strings.Contains(err.Error(), "500") {
+		t.Errorf("expected an error with the status, got %v", err)
+	}
+}
+
+func TestWebhookNotifierValidateTarget(t *testing.T) {
+	n := &WebhookNotifier{}
+	for target, ok := range map[string]bool{
+		"https://8.8.8.8/hook":                     true,
+		"https://[2001:4860:4860::8888]/hook":      true,
+		"http://localhost:8080":                    false,
+		"http://127.0.0.1:8080":                    false,
+		"http://[::1]/hook":                        false,
+		"http://10.0.0.1/hook":                     false,
+		"http://192.168.1.1/hook":                  false,
+		"http://100.64.0.1/hook":                   false,
+		"http://169.254.169.254/latest/meta-data/": false,
+		"http://[::ffff:127.0.0.1]/hook":           false,
+		"http://0.0.0.0/hook":                      false,
+		"ftp://example.com":                        false,
+		"https://":                                 false,
+		"example.com/hook":                         false,
+	} {
+		if err := n.ValidateTarget(target); (err == nil) != ok {
+			t.Errorf("%s: expected ok=%v, got %v", target, ok, err)
+		}
+	}
+}
+
+// fakeSMTPServer accepts a single connection and speaks just enough SMTP to
+// take one message, which it sends on the returned channel.
+func fakeSMTPServer(t *testing.T) (string, <-chan string) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { ln.Close() })
+	msgs := make(chan string, 1)
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		r := bufio.NewReader(conn)
+		reply := func(s string) { conn.Write([]byte(s + "\r\n")) }
+		reply("220 localhost fake smtp")
+		var envelope []string
+		for {
+			line, err := r.ReadString('\n')
+			if err != nil {
+				return
+			}
+			cmd := strings.ToUpper(strings.TrimSpace(line))
+			switch {
+			case strings.HasPrefix(cmd, "EHLO"), strings.HasPrefix(cmd, "HELO"):
+				reply("250 localhost")
+			case strings.HasPrefix(cmd, "MAIL FROM"), strings.HasPrefix(cmd, "RCPT TO"):
+				envelope = append(envelope, strings.TrimSpace(line))
+				reply("250 ok")
+			case cmd == "DATA":
+				reply("354 go ahead")
+				var data strings.Builder
+				for {
+					l, err := r.ReadString('\n')
+					if err != nil {
+						return
+					}
+					if l == ".\r\n" {
+						break
+					}
+					data.WriteString(l)
+				}
+				msgs <- strings.Join(envelope, "\r\n") + "\r\n" + data.String()
+				reply("250 queued")
+			case cmd == "QUIT":
+				reply("221 bye")
+				return
+			default:
+				reply("502 not implemented")
+			}
+		}
+	}()
+	return ln.Addr().String(), msgs
+}
+
+// The client re-checks the address when it connects, so a target that passed
+// validation can't be pointed at a private address later.
+func TestPublicHTTPClientRefusesPrivateAddresses(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+
+	n := &WebhookNotifier{Client: NewPublicHTTPClient()}
+	err := n.Notify(context.Background(), srv.URL, testAlert())
+	if !errors.Is(err, ErrNotPublic) {
+		t.Errorf("expected ErrNotPublic, got %v", err)
+	}
+}
+
+func TestSMTPNotifier(t *testing.T) {
+	addr, msgs := fakeSMTPServer(t)
+	n := &SMTPNotifier{Addr: addr, From: "kaggo@example.com"}
+	target := "Owner <owner@example.com>"
+	if err := n.ValidateTarget(target); err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	a := testAlert()
+	if err := n.Notify(ctx, target, a); err != nil {
+		t.Fatal(err)
+	}
+	msg := <-msgs
+	for _, want := range []string{
+		"MAIL FROM:<kaggo@example.com>",
+		"RCPT TO:<owner@example.com>",
+		`To: "Owner" <owner@example.com>`,
+		"Subject: [kaggo] internal.random.value alert for 1234",
+		a.Message,
+		"rule: 7",
+		"time: 2024-01-02T03:04:05Z",
+	} {
+		if !strings.Contains(msg, want) {
+			t.Errorf("expected the message to contain %q:\n%s", want, msg)
+		}
+	}
+}
+
+func TestSMTPNotifierValidateTarget(t *testing.T) {
+	n := &SMTPNotifier{}
+	if err := n.ValidateTarget("not an address"); err == nil {
+		t.Error("expected an error")
+	}
+}
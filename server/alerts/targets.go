@@ -0,0 +1,99 @@
+package alerts
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"syscall"
+	"time"
+)
+
+// Webhook targets (for alert rules and for webhooks) are supplied by users, so
+// they're restricted to public addresses; otherwise a target like localhost or
+// the cloud metadata endpoint would have the server or a worker make requests
+// inside our network on the user's behalf. Targets are checked when they're
+// validated and again when the connection is made, since the host may resolve
+// to something else by then.
+
+// ErrNotPublic is returned (wrapped) for targets that aren't public.
+var ErrNotPublic = errors.New("address is not public")
+
+// AllowPrivateTargets turns the checks off. It's only meant for tests that
+// deliver to local servers.
+var AllowPrivateTargets = false
+
+// targetLookupTimeout bounds resolving a target's host during validation.
+const targetLookupTimeout = 5 * time.Second
+
+// shared address space (RFC 6598) and "this network"; netip doesn't classify
+// either as private
+var nonPublicPrefixes = []netip.Prefix{
+	netip.MustParsePrefix("100.64.0.0/10"),
+	netip.MustParsePrefix("0.0.0.0/8"),
+}
+
+// checkPublicAddr returns an error if ip isn't a public unicast address.
+func checkPublicAddr(ip netip.Addr) error {
+	if AllowPrivateTargets {
+		return nil
+	}
+	ip = ip.Unmap()
+	if !ip.IsValid() || ip.IsLoopback() || ip.IsPrivate() || ip.IsUnspecified() ||
+		ip.IsLinkLocalUnicast() || ip.IsMulticast() || ip.IsLinkLocalMulticast() || ip.IsInterfaceLocalMulticast() {
+		return fmt.Errorf("%s: %w", ip, ErrNotPublic)
+	}
+	for _, p := range nonPublicPrefixes {
+		if p.Contains(ip) {
+			return fmt.Errorf("%s: %w", ip, ErrNotPublic)
+		}
+	}
+	return nil
+}
+
+// checkPublicHost resolves host and returns an error if any of its addresses
+// isn't public.
+func checkPublicHost(ctx context.Context, host string) error {
+	if ip, err := netip.ParseAddr(host); err == nil {
+		return checkPublicAddr(ip)
+	}
+	if AllowPrivateTargets {
+		return nil
+	}
+	ips, err := net.DefaultResolver.LookupNetIP(ctx, "ip", host)
+	if err != nil {
+		return fmt.Errorf("error resolving %s: %w", host, err)
+	}
+	for _, ip := range ips {
+		if err := checkPublicAddr(ip); err != nil {
+			return fmt.Errorf("%s: %w", host, err)
+		}
+	}
+	return nil
+}
+
+// NewPublicHTTPClient returns a client that refuses to connect to addresses
+// that aren't public. It doesn't use a proxy, since the proxy would be the
+// address that's checked.
+func NewPublicHTTPClient() *http.Client {
+	d := &net.Dialer{
+		Timeout: 30 * time.Second,
+		Control: func(network, address string, c syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+			ip, err := netip.ParseAddr(host)
+			if err != nil {
+				return err
+			}
+			return checkPublicAddr(ip)
+		},
+	}
+	t := http.DefaultTransport.(*http.Transport).Clone()
+	t.Proxy = nil
+	t.DialContext = d.DialContext
+	return &http.Client{Transport: t}
+}
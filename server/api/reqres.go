@@ -1,6 +1,8 @@
 package api
 
 import (
+	"time"
+
 	"github.com/brojonat/kaggo/server/db/jsonb"
 	"go.temporal.io/sdk/client"
 )
@@ -29,10 +31,328 @@ type UserMetricOperationPayload struct {
 	ID          string `json:"id"`
 }
 
+type CreateAlertRulePayload struct {
+	Email       string                `json:"email"`
+	RequestKind string                `json:"request_kind"`
+	ID          string                `json:"id"`
+	Metric      string                `json:"metric"`
+	Condition   string                `json:"condition"`
+	Params      jsonb.AlertRuleParams `json:"params"`
+	Notifier    string                `json:"notifier"`
+	Target      string                `json:"target"`
+	// CooldownSeconds is the minimum time between firings; defaults to an hour.
+	CooldownSeconds int `json:"cooldown_seconds,omitempty"`
+}
+
+type TestNotifierPayload struct {
+	Notifier string `json:"notifier"`
+	Target   string `json:"target"`
+}
+
+type CreateAPIKeyPayload struct {
+	Email  string   `json:"email"`
+	Name   string   `json:"name"`
+	Scopes []string `json:"scopes"`
+	// ExpiresIn is a duration (e.g., 90d); keys without one never expire.
+	ExpiresIn string `json:"expires_in,omitempty"`
+}
+
+// CreateAPIKeyResponse carries the plaintext key. Only its hash is stored, so
+// this is the one and only time the key is available.
+type CreateAPIKeyResponse struct {
+	KeyID     int64     `json:"key_id"`
+	Key       string    `json:"key"`
+	KeyPrefix string    `json:"key_prefix"`
+	Scopes    []string  `json:"scopes"`
+	ExpiresAt time.Time `json:"expires_at,omitempty"`
+}
+
+// Webhook event types
+const (
+	WebhookEventContentDiscovered = "content.discovered"
+	WebhookEventScheduleCreated   = "schedule.created"
+	WebhookEventScheduleExpired   = "schedule.expired"
+	WebhookEventMetadataRefreshed = "metadata.refreshed"
+)
+
+type CreateWebhookPayload struct {
+	Email string `json:"email"`
+	URL   string `json:"url"`
+	// Events are the event types to deliver; empty means all of them.
+	Events []string `json:"events,omitempty"`
+}
+
+// WebhookEvent is the body POSTed to webhook endpoints. The parent fields are
+// set for content discovered under a watched entity (e.g., the channel a new
+// video was posted to).
+type WebhookEvent struct {
+	EventID           string    `json:"event_id"`
+	Type              string    `json:"type"`
+	Ts                time.Time `json:"ts"`
+	RequestKind       string    `json:"request_kind"`
+	ID                string    `json:"id"`
+	ParentRequestKind string    `json:"parent_request_kind,omitempty"`
+	ParentID          string    `json:"parent_id,omitempty"`
+	ScheduleID        string    `json:"schedule_id,omitempty"`
+}
+
+// WebhookSignPayload asks the server to sign a webhook delivery attempt.
+type WebhookSignPayload struct {
+	WebhookID int64  `json:"webhook_id"`
+	Body      []byte `json:"body"`
+}
+
+// WebhookSignResponse is the signature of the body at Timestamp (Unix
+// seconds). Gone is set instead if the webhook was deleted or disabled.
+type WebhookSignResponse struct {
+	Timestamp int64  `json:"timestamp,omitempty"`
+	Signature string `json:"signature,omitempty"`
+	Gone      bool   `json:"gone,omitempty"`
+}
+
+type WebhookDeadLetterPayload struct {
+	WebhookID int64  `json:"webhook_id"`
+	EventID   string `json:"event_id"`
+	EventType string `json:"event_type"`
+	Payload   string `json:"payload"`
+	Error     string `json:"error"`
+}
+
+type ScheduleExpiryPayload struct {
+	ScheduleID string `json:"schedule_id"`
+}
+
+// ScheduleWatchExpiryPayload starts expiry watchers for existing schedules
+// with an EndAt, optionally only those of RequestKind.
+type ScheduleWatchExpiryPayload struct {
+	RequestKind string `json:"request_kind,omitempty"`
+}
+
+// ScheduleWatchExpiryResponse lists the schedules that are watched and why
+// starting a watcher failed for the rest.
+type ScheduleWatchExpiryResponse struct {
+	Watched []string          `json:"watched"`
+	Failed  map[string]string `json:"failed"`
+}
+
+// SchedulePauseFailingPayload is sent by a polling workflow whose schedule
+// has failed permanently (e.g., the content was deleted) Failures times in a
+// row; Reason is the kind of failure (e.g., "not-found").
+type SchedulePauseFailingPayload struct {
+	ScheduleID string `json:"schedule_id"`
+	Failures   int    `json:"failures"`
+	Reason     string `json:"reason"`
+}
+
+// ScheduleCadencePayload is sent by a polling workflow to have the cadence of
+// its schedule reviewed.
+type ScheduleCadencePayload struct {
+	ScheduleID string `json:"schedule_id"`
+}
+
+// ScheduleExpiryResponse tells the expiry watcher whether it's done; if not,
+// it should check back at EndAt (the schedule was extended).
+type ScheduleExpiryResponse struct {
+	Done  bool      `json:"done"`
+	EndAt time.Time `json:"end_at"`
+}
+
+// Schedule update actions (PUT /schedule and PUT /schedule/bulk).
+const (
+	ScheduleActionPause      = "pause"
+	ScheduleActionUnpause    = "unpause"
+	ScheduleActionUpdateSpec = "update-spec"
+	ScheduleActionExtend     = "extend"
+	ScheduleActionSetNote    = "set-note"
+)
+
+// ScheduleUpdatePayload applies Action to ScheduleID (PUT /schedule) or, in
+// bulk, to every schedule of RequestKind (optionally only those for IDs) or
+// listed in ScheduleIDs (PUT /schedule/bulk). update-spec replaces the spec
+// with Schedule or the Preset for each schedule's kind, but keeps the current
+// StartAt/EndAt unless Schedule sets them (presets never do). extend
+// sets the EndAt to EndAt, or pushes it out by ExtendBy (e.g., "7d" or "168h").
+type ScheduleUpdatePayload struct {
+	Action      string               `json:"action"`
+	ScheduleID  string               `json:"schedule_id,omitempty"`
+	ScheduleIDs []string             `json:"schedule_ids,omitempty"`
+	RequestKind string               `json:"request_kind,omitempty"`
+	IDs         []string             `json:"ids,omitempty"`
+	Note        string               `json:"note,omitempty"`
+	Schedule    *client.ScheduleSpec `json:"schedule_spec,omitempty"`
+	Preset      string               `json:"preset,omitempty"`
+	EndAt       *time.Time           `json:"end_at,omitempty"`
+	ExtendBy    string               `json:"extend_by,omitempty"`
+}
+
+// ScheduleBulkUpdateResponse lists the schedules a bulk update applied to and
+// why it failed for the rest.
+type ScheduleBulkUpdateResponse struct {
+	Updated []string          `json:"updated"`
+	Failed  map[string]string `json:"failed"`
+}
+
+// ScheduleDescription is a schedule as returned by GET /schedule/{id}.
+type ScheduleDescription struct {
+	ID               string                             `json:"id"`
+	RequestKind      string                             `json:"request_kind"`
+	EntityID         string                             `json:"entity_id"`
+	OwnerEmail       string                             `json:"owner_email,omitempty"`
+	Tier             string                             `json:"tier,omitempty"`
+	Spec             *client.ScheduleSpec               `json:"spec"`
+	Paused           bool                               `json:"paused"`
+	Note             string                             `json:"note"`
+	FixedCadence     bool                               `json:"fixed_cadence"`
+	NumActions       int                                `json:"num_actions"`
+	RecentActions    []client.ScheduleActionResult      `json:"recent_actions"`
+	RunningWorkflows []client.ScheduleWorkflowExecution `json:"running_workflows"`
+	NextActionTimes  []time.Time                        `json:"next_action_times"`
+	CreatedAt        time.Time                          `json:"created_at"`
+	LastUpdateAt     time.Time                          `json:"last_update_at"`
+}
+
+// ScheduleListResponse is a page of schedules (GET /schedule). Pass
+// NextPageToken back as page_token to get the next page; it's empty on the
+// last page.
+type ScheduleListResponse struct {
+	Schedules     []ScheduleListEntry `json:"schedules"`
+	NextPageToken string              `json:"next_page_token,omitempty"`
+}
+
+// ScheduleListEntry is a schedule and its search attributes.
+type ScheduleListEntry struct {
+	ID          string               `json:"id"`
+	RequestKind string               `json:"request_kind"`
+	EntityID    string               `json:"entity_id"`
+	OwnerEmail  string               `json:"owner_email,omitempty"`
+	Tier        string               `json:"tier,omitempty"`
+	Spec        *client.ScheduleSpec `json:"spec"`
+	Paused      bool                 `json:"paused"`
+	Note        string               `json:"note,omitempty"`
+}
+
+// WorkflowListResponse is a page of workflow executions (GET /workflow).
+type WorkflowListResponse struct {
+	Workflows     []WorkflowListEntry `json:"workflows"`
+	NextPageToken string              `json:"next_page_token,omitempty"`
+}
+
+// WorkflowListEntry is a workflow execution and its search attributes.
+// ScheduleID is set if it was started by a schedule.
+type WorkflowListEntry struct {
+	WorkflowID   string     `json:"workflow_id"`
+	RunID        string     `json:"run_id"`
+	WorkflowType string     `json:"workflow_type"`
+	Status       string     `json:"status"`
+	StartTime    time.Time  `json:"start_time"`
+	CloseTime    *time.Time `json:"close_time,omitempty"`
+	ScheduleID   string     `json:"schedule_id,omitempty"`
+	RequestKind  string     `json:"request_kind,omitempty"`
+	EntityID     string     `json:"entity_id,omitempty"`
+	OwnerEmail   string     `json:"owner_email,omitempty"`
+	Tier         string     `json:"tier,omitempty"`
+}
+
+// ScheduleCapacityResponse is the projected polling load (GET
+// /schedule/capacity): how many runs schedules will start in each Bucket-wide
+// bucket from Start to End, by kind.
+type ScheduleCapacityResponse struct {
+	Start  time.Time          `json:"start"`
+	End    time.Time          `json:"end"`
+	Bucket string             `json:"bucket"`
+	Kinds  []ScheduleCapacity `json:"kinds"`
+}
+
+// ScheduleCapacity is one kind's projected load. Paused schedules are counted
+// but don't run; neither do the Unprojected ones, whose spec is missing or
+// can't be evaluated. Buckets holds the runs per bucket; PeakRuns is the
+// largest of them and PeakAt is the start of that bucket.
+type ScheduleCapacity struct {
+	RequestKind string    `json:"request_kind"`
+	Schedules   int       `json:"schedules"`
+	Paused      int       `json:"paused"`
+	Unprojected int       `json:"unprojected"`
+	Runs        int       `json:"runs"`
+	RunsPerHour float64   `json:"runs_per_hour"`
+	PeakRuns    int       `json:"peak_runs"`
+	PeakAt      time.Time `json:"peak_at"`
+	Buckets     []int     `json:"buckets"`
+}
+
+// RateLimitObservation is a provider's rate limit as reported in its response
+// headers. Window is the period over which Limit requests are allowed; it's
+// used to estimate how quickly the budget refills.
+type RateLimitObservation struct {
+	Key           string    `json:"key"`
+	Limit         float64   `json:"limit"`
+	Remaining     float64   `json:"remaining"`
+	Reset         time.Time `json:"reset"`
+	WindowSeconds float64   `json:"window_seconds"`
+}
+
+// RateLimitAcquirePayload asks for a token from the key's bucket. The caller
+// is willing to wait up to MaxWaitSeconds for one.
+type RateLimitAcquirePayload struct {
+	Key            string  `json:"key"`
+	RequestKind    string  `json:"request_kind"`
+	MaxWaitSeconds float64 `json:"max_wait_seconds"`
+}
+
+// RateLimitAcquireResponse tells the caller whether to send its request and,
+// if so, how long to wait first. Reason is set whenever the request was
+// delayed or skipped.
+type RateLimitAcquireResponse struct {
+	Allowed     bool    `json:"allowed"`
+	WaitSeconds float64 `json:"wait_seconds,omitempty"`
+	Reason      string  `json:"reason,omitempty"`
+}
+
+// QuotaChargePayload asks the ledger to charge Cost units to one of Keys
+// (digests of the API keys in the pool), each of which may spend Budget units
+// per quota period. The RequestKind and IDs being requested are used to
+// prioritize requests when the budget is running short.
+type QuotaChargePayload struct {
+	Keys        []string `json:"keys"`
+	Cost        int64    `json:"cost"`
+	Budget      int64    `json:"budget"`
+	RequestKind string   `json:"request_kind,omitempty"`
+	IDs         []string `json:"ids,omitempty"`
+}
+
+// QuotaChargeResponse names the key that was charged, if any, and the budget
+// remaining for each key in the pool. Reason is set if nothing was charged
+// and the request should be skipped.
+type QuotaChargeResponse struct {
+	Allowed   bool             `json:"allowed"`
+	Key       string           `json:"key,omitempty"`
+	Remaining map[string]int64 `json:"remaining"`
+	Reason    string           `json:"reason,omitempty"`
+}
+
+// BackfillPayload starts a backfill of the archived responses for a kind
+// fetched in [Start, End). IDs and Metrics are optional filters.
+type BackfillPayload struct {
+	RequestKind string    `json:"request_kind"`
+	IDs         []string  `json:"ids,omitempty"`
+	Metrics     []string  `json:"metrics,omitempty"`
+	Start       time.Time `json:"start"`
+	End         time.Time `json:"end"`
+}
+
 type GenericScheduleRequestPayload struct {
 	RequestKind string              `json:"request_kind"`
 	ID          string              `json:"id"`
 	Schedule    client.ScheduleSpec `json:"schedule_spec,omitempty"`
+	// Preset names a spec to use instead of Schedule (e.g., "default"; see
+	// temporal/v19700101/presets.go).
+	Preset string `json:"preset,omitempty"`
+	// FixedCadence keeps the schedule on its spec even if the kind's cadence
+	// is adaptive.
+	FixedCadence bool `json:"fixed_cadence,omitempty"`
+	// The parent is set when the schedule is for newly discovered content
+	// (e.g., a video posted to a subscribed channel).
+	ParentRequestKind string `json:"parent_request_kind,omitempty"`
+	ParentID          string `json:"parent_id,omitempty"`
 }
 
 type AddListenerSubPayload struct {
@@ -46,119 +366,154 @@ type MetricMetadataPayload struct {
 	Data        jsonb.MetadataJSON `json:"data"`
 }
 
+// MetricSamplesPayload is the generic metric upload. Metrics maps short metric
+// names (e.g., "score") to their values; the stored metric name is the
+// RequestKind joined with the short name (e.g., "reddit.post.score").
+type MetricSamplesPayload struct {
+	RequestKind string             `json:"request_kind"`
+	ID          string             `json:"id"`
+	Metrics     map[string]float64 `json:"metrics"`
+	// Ts is when the metrics were observed; it defaults to the time of the
+	// upload.
+	Ts *time.Time `json:"ts,omitempty"`
+	// IdempotencyKey identifies the upload and is stored with its samples.
+	// Samples that were already recorded for the same metric and Ts are
+	// dropped, so retries that set a Ts are harmless. It requires a Ts.
+	IdempotencyKey string `json:"idempotency_key,omitempty"`
+}
+
+// The typed metric payloads are uploaded to the per kind endpoints. Like
+// MetricSamplesPayload, they take an optional Ts for when the metrics were
+// observed, which defaults to the time of the upload.
+
 type InternalMetricPayload struct {
-	ID    string `json:"id"`
-	Value int    `json:"value"`
+	ID    string     `json:"id"`
+	Value int        `json:"value"`
+	Ts    *time.Time `json:"ts,omitempty"`
+	// IdempotencyKey works as in MetricSamplesPayload.
+	IdempotencyKey string `json:"idempotency_key,omitempty"`
 }
 
 type YouTubeVideoMetricPayload struct {
-	ID          string `json:"id"`
-	SetViews    bool   `json:"set_views"`
-	Views       int    `json:"views"`
-	SetComments bool   `json:"set_comments"`
-	Comments    int    `json:"comments"`
-	SetLikes    bool   `json:"set_likes"`
-	Likes       int    `json:"likes"`
+	ID          string     `json:"id"`
+	SetViews    bool       `json:"set_views"`
+	Views       int        `json:"views"`
+	SetComments bool       `json:"set_comments"`
+	Comments    int        `json:"comments"`
+	SetLikes    bool       `json:"set_likes"`
+	Likes       int        `json:"likes"`
+	Ts          *time.Time `json:"ts,omitempty"`
 }
 
 type YouTubeChannelMetricPayload struct {
-	ID             string `json:"id"`
-	SetViews       bool   `json:"set_views"`
-	Views          int    `json:"views"`
-	SetSubscribers bool   `json:"set_subscribers"`
-	Subscribers    int    `json:"subscribers"`
-	SetVideos      bool   `json:"set_videos"`
-	Videos         int    `json:"videos"`
+	ID             string     `json:"id"`
+	SetViews       bool       `json:"set_views"`
+	Views          int        `json:"views"`
+	SetSubscribers bool       `json:"set_subscribers"`
+	Subscribers    int        `json:"subscribers"`
+	SetVideos      bool       `json:"set_videos"`
+	Videos         int        `json:"videos"`
+	Ts             *time.Time `json:"ts,omitempty"`
 }
 
 type KaggleNotebookMetricPayload struct {
-	ID           string `json:"id"`
-	SetViews     bool   `json:"set_views"`
-	Views        int    `json:"views"`
-	SetVotes     bool   `json:"set_votes"`
-	Votes        int    `json:"votes"`
-	SetDownloads bool   `json:"set_downloads"`
-	Downloads    int    `json:"downloads"`
+	ID           string     `json:"id"`
+	SetViews     bool       `json:"set_views"`
+	Views        int        `json:"views"`
+	SetVotes     bool       `json:"set_votes"`
+	Votes        int        `json:"votes"`
+	SetDownloads bool       `json:"set_downloads"`
+	Downloads    int        `json:"downloads"`
+	Ts           *time.Time `json:"ts,omitempty"`
 }
 
 type KaggleDatasetMetricPayload struct {
-	ID           string `json:"id"`
-	SetViews     bool   `json:"set_views"`
-	Views        int    `json:"views"`
-	SetVotes     bool   `json:"set_votes"`
-	Votes        int    `json:"votes"`
-	SetDownloads bool   `json:"set_downloads"`
-	Downloads    int    `json:"downloads"`
+	ID           string     `json:"id"`
+	SetViews     bool       `json:"set_views"`
+	Views        int        `json:"views"`
+	SetVotes     bool       `json:"set_votes"`
+	Votes        int        `json:"votes"`
+	SetDownloads bool       `json:"set_downloads"`
+	Downloads    int        `json:"downloads"`
+	Ts           *time.Time `json:"ts,omitempty"`
 }
 
 type RedditPostMetricPayload struct {
-	ID       string  `json:"id"`
-	SetScore bool    `json:"set_score"`
-	Score    int     `json:"score"`
-	SetRatio bool    `json:"set_ratio"`
-	Ratio    float32 `json:"ratio"`
+	ID       string     `json:"id"`
+	SetScore bool       `json:"set_score"`
+	Score    int        `json:"score"`
+	SetRatio bool       `json:"set_ratio"`
+	Ratio    float32    `json:"ratio"`
+	Ts       *time.Time `json:"ts,omitempty"`
 }
 
 type RedditCommentMetricPayload struct {
-	ID                  string  `json:"id"`
-	SetScore            bool    `json:"set_score"`
-	Score               int     `json:"score"`
-	SetControversiality bool    `json:"set_controversiality"`
-	Controversiality    float32 `json:"controversiality"`
+	ID                  string     `json:"id"`
+	SetScore            bool       `json:"set_score"`
+	Score               int        `json:"score"`
+	SetControversiality bool       `json:"set_controversiality"`
+	Controversiality    float32    `json:"controversiality"`
+	Ts                  *time.Time `json:"ts,omitempty"`
 }
 
 type RedditSubredditMetricPayload struct {
-	ID                 string `json:"id"`
-	SetSubscribers     bool   `json:"set_subscribers"`
-	Subscribers        int    `json:"subscribers"`
-	SetActiveUserCount bool   `json:"set_active_user_count"`
-	ActiveUserCount    int    `json:"active_user_count"`
+	ID                 string     `json:"id"`
+	SetSubscribers     bool       `json:"set_subscribers"`
+	Subscribers        int        `json:"subscribers"`
+	SetActiveUserCount bool       `json:"set_active_user_count"`
+	ActiveUserCount    int        `json:"active_user_count"`
+	Ts                 *time.Time `json:"ts,omitempty"`
 }
 
 type RedditUserMetricPayload struct {
-	ID              string `json:"id"`
-	SetAwardeeKarma bool   `json:"set_awardee_karma"`
-	AwardeeKarma    int    `json:"awardee_karma"`
-	SetAwarderKarma bool   `json:"set_awarder_karma"`
-	AwarderKarma    int    `json:"awarder_karma"`
-	SetCommentKarma bool   `json:"set_comment_karma"`
-	CommentKarma    int    `json:"comment_karma"`
-	SetLinkKarma    bool   `json:"set_like_karma"`
-	LinkKarma       int    `json:"like_karma"`
-	SetTotalKarma   bool   `json:"set_total_karma"`
-	TotalKarma      int    `json:"total_karma"`
+	ID              string     `json:"id"`
+	SetAwardeeKarma bool       `json:"set_awardee_karma"`
+	AwardeeKarma    int        `json:"awardee_karma"`
+	SetAwarderKarma bool       `json:"set_awarder_karma"`
+	AwarderKarma    int        `json:"awarder_karma"`
+	SetCommentKarma bool       `json:"set_comment_karma"`
+	CommentKarma    int        `json:"comment_karma"`
+	SetLinkKarma    bool       `json:"set_like_karma"`
+	LinkKarma       int        `json:"like_karma"`
+	SetTotalKarma   bool       `json:"set_total_karma"`
+	TotalKarma      int        `json:"total_karma"`
+	Ts              *time.Time `json:"ts,omitempty"`
 }
 
 type TwitchClipMetricPayload struct {
-	ID           string `json:"id"`
-	SetViewCount bool   `json:"set_view_count"`
-	ViewCount    int    `json:"view_count"`
+	ID           string     `json:"id"`
+	SetViewCount bool       `json:"set_view_count"`
+	ViewCount    int        `json:"view_count"`
+	Ts           *time.Time `json:"ts,omitempty"`
 }
 
 type TwitchVideoMetricPayload struct {
-	ID           string `json:"id"`
-	SetViewCount bool   `json:"set_view_count"`
-	ViewCount    int    `json:"view_count"`
+	ID           string     `json:"id"`
+	SetViewCount bool       `json:"set_view_count"`
+	ViewCount    int        `json:"view_count"`
+	Ts           *time.Time `json:"ts,omitempty"`
 }
 
 type TwitchStreamMetricPayload struct {
-	ID           string `json:"id"`
-	SetViewCount bool   `json:"set_view_count"`
-	ViewCount    int    `json:"view_count"`
+	ID           string     `json:"id"`
+	SetViewCount bool       `json:"set_view_count"`
+	ViewCount    int        `json:"view_count"`
+	Ts           *time.Time `json:"ts,omitempty"`
 }
 
 type TwitchUserPastDecMetricPayload struct {
-	ID              string  `json:"id"`
-	SetAvgViewCount bool    `json:"set_avg_view_count"`
-	AvgViewCount    float32 `json:"avg_view_count"`
-	SetMedViewCount bool    `json:"set_med_view_count"`
-	MedViewCount    float32 `json:"med_view_count"`
-	SetStdViewCount bool    `json:"set_std_view_count"`
-	StdViewCount    float32 `json:"std_view_count"`
-	SetAvgDuration  bool    `json:"set_avg_duration"`
-	AvgDuration     float32 `json:"avg_duration"`
-	SetMedDuration  bool    `json:"set_med_duration"`
-	MedDuration     float32 `json:"med_duration"`
-	SetStdDuration  bool    `json:"set_std_duration"`
-	StdDuration     float32 `json:"std_duration"`
+	ID              string     `json:"id"`
+	SetAvgViewCount bool       `json:"set_avg_view_count"`
+	AvgViewCount    float32    `json:"avg_view_count"`
+	SetMedViewCount bool       `json:"set_med_view_count"`
+	MedViewCount    float32    `json:"med_view_count"`
+	SetStdViewCount bool       `json:"set_std_view_count"`
+	StdViewCount    float32    `json:"std_view_count"`
+	SetAvgDuration  bool       `json:"set_avg_duration"`
+	AvgDuration     float32    `json:"avg_duration"`
+	SetMedDuration  bool       `json:"set_med_duration"`
+	MedDuration     float32    `json:"med_duration"`
+	SetStdDuration  bool       `json:"set_std_duration"`
+	StdDuration     float32    `json:"std_duration"`
+	Ts              *time.Time `json:"ts,omitempty"`
 }
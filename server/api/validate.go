@@ -0,0 +1,45 @@
+package api
+
+import (
+	"fmt"
+	"regexp"
+	"time"
+)
+
+// Metric names are short, lowercase, and dash separated (e.g., "link-karma").
+var metricNameRegex = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)
+
+// Bounds on the observation time of uploaded samples. Samples may be a little
+// ahead of the server's clock (the worker's clock may be), but not by much.
+// Historical imports are fine; anything before sampleTimeMin is presumably a
+// zero value or a unit mixup.
+const sampleMaxClockSkew = time.Minute
+
+var sampleTimeMin = time.Date(2005, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// ValidateMetricName returns an error if m isn't a valid metric name. This is
+// checked wherever samples are written, whether by the server or by a worker
+// writing to the database directly.
+func ValidateMetricName(m string) error {
+	if !metricNameRegex.MatchString(m) {
+		return fmt.Errorf("bad metric name: %q", m)
+	}
+	return nil
+}
+
+// SampleTime returns the observation time of an upload: ts if it's set, or now
+// if it isn't. A ts that's in the future (beyond a little clock skew) or
+// before sampleTimeMin is an error.
+func SampleTime(ts *time.Time) (time.Time, error) {
+	now := time.Now()
+	if ts == nil {
+		return now, nil
+	}
+	if ts.After(now.Add(sampleMaxClockSkew)) {
+		return time.Time{}, fmt.Errorf("ts is in the future: %s", ts.Format(time.RFC3339))
+	}
+	if ts.Before(sampleTimeMin) {
+		return time.Time{}, fmt.Errorf("ts is before %s: %s", sampleTimeMin.Format(time.DateOnly), ts.Format(time.RFC3339))
+	}
+	return *ts, nil
+}
@@ -1,12 +1,44 @@
 package server
 
 import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"net/http"
 	"os"
+	"slices"
+	"strings"
 
+	"github.com/brojonat/kaggo/server/db/dbgen"
 	"github.com/golang-jwt/jwt"
 )
 
+// Token roles. Admins can do anything; customers can only read the metrics
+// that have been granted to them (see users_metadata_through) and manage
+// their own alerts and webhooks.
+const (
+	roleAdmin    = "admin"
+	roleCustomer = "customer"
+)
+
+// API key scopes. Each route requires one of these (see requireScope).
+// Customer tokens only have the read scope, while admin tokens (and basic
+// auth) have all of them.
+const (
+	scopeIngest        = "ingest"
+	scopeRead          = "read"
+	scopeScheduleAdmin = "schedule-admin"
+	scopeUserAdmin     = "user-admin"
+)
+
+var apiKeyScopes = []string{scopeIngest, scopeRead, scopeScheduleAdmin, scopeUserAdmin}
+
+// API keys are easy to spot (and to tell apart from JWTs) by their prefix.
+const (
+	apiKeyPrefix = "kgo_"
+	APIKeyHeader = "X-API-Key"
+)
+
 func getSecretKey() string {
 	return os.Getenv("SERVER_SECRET_KEY")
 }
@@ -14,6 +46,10 @@ func getSecretKey() string {
 type authJWTClaims struct {
 	jwt.StandardClaims
 	Email string `json:"email"`
+	// Role is one of the role* constants. Tokens issued before roles existed
+	// don't have one; those could only be minted with the server secret, so
+	// they're treated as admin.
+	Role string `json:"role,omitempty"`
 }
 
 func generateAccessToken(claims authJWTClaims) (string, error) {
@@ -22,6 +58,117 @@ func generateAccessToken(claims authJWTClaims) (string, error) {
 	return t.SignedString([]byte(getSecretKey()))
 }
 
+// caller is the authenticated identity behind a request.
+type caller struct {
+	Email string
+	// Admin callers have every scope and can read every metric.
+	Admin  bool
+	Scopes []string
+}
+
+func (c caller) hasScope(scope string) bool {
+	return c.Admin || slices.Contains(c.Scopes, scope)
+}
+
+// getCaller returns the identity set on the request context by the
+// authorizers. Basic auth requires the server secret, so basic auth callers
+// are always admins. API keys with the user-admin scope can mint any other
+// key, so they're admins too.
+func getCaller(r *http.Request) (caller, bool) {
+	if claims, ok := r.Context().Value(ctxKeyJWT).(*authJWTClaims); ok {
+		if claims.Role == "" || claims.Role == roleAdmin {
+			return caller{Email: claims.Email, Admin: true}, true
+		}
+		return caller{Email: claims.Email, Scopes: []string{scopeRead}}, true
+	}
+	if k, ok := r.Context().Value(ctxKeyAPIKey).(*dbgen.ApiKey); ok {
+		return caller{
+			Email:  k.Email,
+			Admin:  slices.Contains(k.Scopes, scopeUserAdmin),
+			Scopes: k.Scopes,
+		}, true
+	}
+	if email, ok := r.Context().Value(ctxKeyEmail).(string); ok {
+		return caller{Email: email, Admin: true}, true
+	}
+	return caller{}, false
+}
+
+// hashAPIKey returns the hex encoded SHA-256 of the key; this is what's
+// stored. Keys are random, so there's no need for a slow hash.
+func hashAPIKey(key string) string {
+	h := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(h[:])
+}
+
+// checkEmailAccess reports whether the caller may act on behalf of email;
+// customers may only act on their own. If not, a forbidden response is
+// written.
+func checkEmailAccess(w http.ResponseWriter, r *http.Request, email string) bool {
+	c, _ := getCaller(r)
+	if c.Admin || (c.Email != "" && strings.EqualFold(c.Email, email)) {
+		return true
+	}
+	writeForbiddenError(w)
+	return false
+}
+
+// metricGrants is the set of (request_kind, id) a customer may read. A nil
+// metricGrants allows everything; that's what admins get. IDs are compared
+// case insensitively.
+type metricGrants map[string]map[string]bool
+
+// getCallerGrants returns the grants of the caller; see metricGrants.
+func getCallerGrants(ctx context.Context, q *dbgen.Queries, c caller) (metricGrants, error) {
+	if c.Admin {
+		return nil, nil
+	}
+	rows, err := q.GetUserGrants(ctx, c.Email)
+	if err != nil {
+		return nil, err
+	}
+	g := metricGrants{}
+	for _, row := range rows {
+		if g[row.RequestKind] == nil {
+			g[row.RequestKind] = map[string]bool{}
+		}
+		g[row.RequestKind][strings.ToLower(row.ID)] = true
+	}
+	return g, nil
+}
+
+func (g metricGrants) allows(rk, id string) bool {
+	if g == nil {
+		return true
+	}
+	return g[rk][strings.ToLower(id)]
+}
+
+// filterIDs returns the subset of ids under rk that are granted.
+func (g metricGrants) filterIDs(rk string, ids []string) []string {
+	if g == nil {
+		return ids
+	}
+	res := []string{}
+	for _, id := range ids {
+		if g.allows(rk, id) {
+			res = append(res, id)
+		}
+	}
+	return res
+}
+
+// grantedIDs filters ids under rk down to those the caller may read.
+// Unauthenticated requests get nothing.
+func grantedIDs(r *http.Request, q *dbgen.Queries, rk string, ids []string) ([]string, error) {
+	c, _ := getCaller(r)
+	g, err := getCallerGrants(r.Context(), q, c)
+	if err != nil {
+		return nil, err
+	}
+	return g.filterIDs(rk, ids), nil
+}
+
 // Return the default headers to use to make queries against the server.
 // This is a convenience function for worker clients that upload data.
 func GetDefaultServerHeaders(authToken string) http.Header {
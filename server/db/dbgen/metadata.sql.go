@@ -9,6 +9,7 @@ import (
 	"context"
 
 	jsonb "github.com/brojonat/kaggo/server/db/jsonb"
+	"github.com/jackc/pgx/v5/pgtype"
 )
 
 const getChildrenMetadataByID = `-- name: GetChildrenMetadataByID :many
@@ -24,14 +25,15 @@ LEFT JOIN (
 		id AS id,
         m2.request_kind AS request_kind,
 		m2."data" AS "data",
-		m2."data" ->> 'parent_user_name' AS parent_id
+		m2."data" ->> $1::TEXT AS parent_id
 	FROM metadata m2
-	WHERE m2.request_kind = $1
+	WHERE m2.request_kind = $2
 ) children ON m.id = children.parent_id
-WHERE LOWER(m.id) = LOWER($2) AND m.request_kind = $3
+WHERE LOWER(m.id) = LOWER($3) AND m.request_kind = $4
 `
 
 type GetChildrenMetadataByIDParams struct {
+	ParentField       string `json:"parent_field"`
 	ChildRequestKind  string `json:"child_request_kind"`
 	ID                string `json:"id"`
 	ParentRequestKind string `json:"parent_request_kind"`
@@ -46,7 +48,12 @@ type GetChildrenMetadataByIDRow struct {
 }
 
 func (q *Queries) GetChildrenMetadataByID(ctx context.Context, arg GetChildrenMetadataByIDParams) ([]GetChildrenMetadataByIDRow, error) {
-	rows, err := q.db.Query(ctx, getChildrenMetadataByID, arg.ChildRequestKind, arg.ID, arg.ParentRequestKind)
+	rows, err := q.db.Query(ctx, getChildrenMetadataByID,
+		arg.ParentField,
+		arg.ChildRequestKind,
+		arg.ID,
+		arg.ParentRequestKind,
+	)
 	if err != nil {
 		return nil, err
 	}
@@ -132,3 +139,35 @@ func (q *Queries) InsertMetadata(ctx context.Context, arg InsertMetadataParams)
 	_, err := q.db.Exec(ctx, insertMetadata, arg.ID, arg.RequestKind, arg.Data)
 	return err
 }
+
+const setMetadataStatus = `-- name: SetMetadataStatus :execrows
+UPDATE metadata
+SET data = data || jsonb_build_object(
+    'status', $1::VARCHAR,
+    'status_reason', $2::VARCHAR,
+    'ts_ended', $3::TIMESTAMPTZ)
+WHERE request_kind = $4 AND LOWER(id) = LOWER($5)
+`
+
+type SetMetadataStatusParams struct {
+	Status       string             `json:"status"`
+	StatusReason string             `json:"status_reason"`
+	TsEnded      pgtype.Timestamptz `json:"ts_ended"`
+	RequestKind  string             `json:"request_kind"`
+	ID           string             `json:"id"`
+}
+
+// Marks the entity as no longer tracked; see jsonb.MetadataJSON.Status.
+func (q *Queries) SetMetadataStatus(ctx context.Context, arg SetMetadataStatusParams) (int64, error) {
+	result, err := q.db.Exec(ctx, setMetadataStatus,
+		arg.Status,
+		arg.StatusReason,
+		arg.TsEnded,
+		arg.RequestKind,
+		arg.ID,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
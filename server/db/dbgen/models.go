@@ -9,6 +9,43 @@ import (
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
+type AlertHistory struct {
+	RuleID  int64              `json:"rule_id"`
+	Ts      pgtype.Timestamptz `json:"ts"`
+	Value   float64            `json:"value"`
+	Message string             `json:"message"`
+	Error   string             `json:"error"`
+}
+
+type AlertRule struct {
+	RuleID          int64                 `json:"rule_id"`
+	Email           string                `json:"email"`
+	RequestKind     string                `json:"request_kind"`
+	ID              string                `json:"id"`
+	Metric          string                `json:"metric"`
+	Condition       string                `json:"condition"`
+	Params          jsonb.AlertRuleParams `json:"params"`
+	Notifier        string                `json:"notifier"`
+	Target          string                `json:"target"`
+	CooldownSeconds int32                 `json:"cooldown_seconds"`
+	Enabled         bool                  `json:"enabled"`
+	TsCreated       pgtype.Timestamptz    `json:"ts_created"`
+	TsLastFired     pgtype.Timestamptz    `json:"ts_last_fired"`
+}
+
+type ApiKey struct {
+	KeyID      int64              `json:"key_id"`
+	Email      string             `json:"email"`
+	Name       string             `json:"name"`
+	KeyPrefix  string             `json:"key_prefix"`
+	KeyHash    string             `json:"key_hash"`
+	Scopes     []string           `json:"scopes"`
+	TsCreated  pgtype.Timestamptz `json:"ts_created"`
+	TsExpires  pgtype.Timestamptz `json:"ts_expires"`
+	TsLastUsed pgtype.Timestamptz `json:"ts_last_used"`
+	TsRevoked  pgtype.Timestamptz `json:"ts_revoked"`
+}
+
 type InternalRandom struct {
 	ID  string             `json:"id"`
 	Ts  pgtype.Timestamptz `json:"ts"`
@@ -45,6 +82,42 @@ type Metadatum struct {
 	Data        jsonb.MetadataJSON `json:"data"`
 }
 
+type MetricSample struct {
+	RequestKind    string             `json:"request_kind"`
+	ID             string             `json:"id"`
+	Metric         string             `json:"metric"`
+	Ts             pgtype.Timestamptz `json:"ts"`
+	Value          float64            `json:"value"`
+	IdempotencyKey pgtype.Text        `json:"idempotency_key"`
+}
+
+type OauthToken struct {
+	TokenKey    string             `json:"token_key"`
+	AccessToken string             `json:"access_token"`
+	TsExpires   pgtype.Timestamptz `json:"ts_expires"`
+	TsUpdated   pgtype.Timestamptz `json:"ts_updated"`
+}
+
+type QuotaLedger struct {
+	QuotaKey  string             `json:"quota_key"`
+	Period    pgtype.Date        `json:"period"`
+	Spent     int64              `json:"spent"`
+	TsUpdated pgtype.Timestamptz `json:"ts_updated"`
+}
+
+type RateLimitBucket struct {
+	BucketKey     string             `json:"bucket_key"`
+	Capacity      float64            `json:"capacity"`
+	Tokens        float64            `json:"tokens"`
+	RefillRate    float64            `json:"refill_rate"`
+	TsUpdated     pgtype.Timestamptz `json:"ts_updated"`
+	TsReset       pgtype.Timestamptz `json:"ts_reset"`
+	Delayed       int64              `json:"delayed"`
+	Skipped       int64              `json:"skipped"`
+	LastReason    string             `json:"last_reason"`
+	TsLastLimited pgtype.Timestamptz `json:"ts_last_limited"`
+}
+
 type RedditCommentControversiality struct {
 	ID               string             `json:"id"`
 	Ts               pgtype.Timestamptz `json:"ts"`
@@ -176,6 +249,25 @@ type UsersMetadataThrough struct {
 	RequestKind string `json:"request_kind"`
 }
 
+type Webhook struct {
+	WebhookID int64              `json:"webhook_id"`
+	Email     string             `json:"email"`
+	Url       string             `json:"url"`
+	Secret    string             `json:"secret"`
+	Events    []string           `json:"events"`
+	Enabled   bool               `json:"enabled"`
+	TsCreated pgtype.Timestamptz `json:"ts_created"`
+}
+
+type WebhookDeadLetter struct {
+	WebhookID int64              `json:"webhook_id"`
+	EventID   string             `json:"event_id"`
+	EventType string             `json:"event_type"`
+	Payload   string             `json:"payload"`
+	Error     string             `json:"error"`
+	Ts        pgtype.Timestamptz `json:"ts"`
+}
+
 type YoutubeChannelSubscriber struct {
 	ID          string             `json:"id"`
 	Ts          pgtype.Timestamptz `json:"ts"`
@@ -11,6 +11,24 @@ import (
 	jsonb "github.com/brojonat/kaggo/server/db/jsonb"
 )
 
+const countMetricGrants = `-- name: CountMetricGrants :one
+SELECT COUNT(*)
+FROM users_metadata_through
+WHERE request_kind = $1 AND LOWER(id) = ANY($2::VARCHAR[])
+`
+
+type CountMetricGrantsParams struct {
+	RequestKind string   `json:"request_kind"`
+	Ids         []string `json:"ids"`
+}
+
+func (q *Queries) CountMetricGrants(ctx context.Context, arg CountMetricGrantsParams) (int64, error) {
+	row := q.db.QueryRow(ctx, countMetricGrants, arg.RequestKind, arg.Ids)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
 const deleteUsers = `-- name: DeleteUsers :exec
 DELETE FROM users
 WHERE email = ANY($1::VARCHAR[])
@@ -21,6 +39,37 @@ func (q *Queries) DeleteUsers(ctx context.Context, emails []string) error {
 	return err
 }
 
+const getUserGrants = `-- name: GetUserGrants :many
+SELECT request_kind, id
+FROM users_metadata_through
+WHERE email = $1
+`
+
+type GetUserGrantsRow struct {
+	RequestKind string `json:"request_kind"`
+	ID          string `json:"id"`
+}
+
+func (q *Queries) GetUserGrants(ctx context.Context, email string) ([]GetUserGrantsRow, error) {
+	rows, err := q.db.Query(ctx, getUserGrants, email)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetUserGrantsRow
+	for rows.Next() {
+		var i GetUserGrantsRow
+		if err := rows.Scan(&i.RequestKind, &i.ID); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getUserMetrics = `-- name: GetUserMetrics :many
 SELECT u.email, u.data AS "user_metadata", m.id, m.request_kind, m.data AS "metric_metadata"
 FROM users u
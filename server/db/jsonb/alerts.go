@@ -0,0 +1,19 @@
+package jsonb
+
+// AlertRuleParams are the parameters of an alert rule's condition. Which
+// fields are relevant depends on the condition:
+//   - threshold: Op and Threshold
+//   - pct-change: Window and Pct
+//   - z-score: Window, ZScore, and MinSamples
+type AlertRuleParams struct {
+	// Op is "above" or "below".
+	Op        string  `json:"op,omitempty"`
+	Threshold float64 `json:"threshold,omitempty"`
+	// Window is the lookback (e.g., "24h") the new sample is compared against.
+	Window string `json:"window,omitempty"`
+	// Pct is the absolute percent change (e.g., 50 for ±50%).
+	Pct float64 `json:"pct,omitempty"`
+	// ZScore is the absolute z-score (e.g., 3).
+	ZScore     float64 `json:"z_score,omitempty"`
+	MinSamples int     `json:"min_samples,omitempty"`
+}
@@ -27,6 +27,13 @@ type MetadataJSON struct {
 	Duration           int       `json:"duration,omitempty"`
 	DisplayName        string    `json:"display_name,omitempty"`
 	Description        string    `json:"description,omitempty"`
+	// Status is empty while the entity is tracked and MetadataStatusEnded
+	// once tracking has stopped for good (e.g., the content was deleted).
+	Status       string    `json:"status,omitempty"`
+	StatusReason string    `json:"status_reason,omitempty"`
+	TSEnded      time.Time `json:"ts_ended,omitempty"`
 }
 
+const MetadataStatusEnded = "ended"
+
 type UserMetadataJSON struct{}
@@ -0,0 +1,227 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/brojonat/kaggo/server/alerts"
+	"github.com/brojonat/kaggo/server/api"
+	"github.com/brojonat/kaggo/server/db/dbgen"
+	kt "github.com/brojonat/kaggo/temporal/v19700101"
+	"github.com/brojonat/server-tools/stools"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+func handleGetAlertRules(l *slog.Logger, q *dbgen.Queries) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		email := r.URL.Query().Get("email")
+		if email == "" {
+			writeBadRequestError(w, fmt.Errorf("must supply email"))
+			return
+		}
+		if !checkEmailAccess(w, r, email) {
+			return
+		}
+		res, err := q.GetAlertRules(r.Context(), email)
+		if err != nil {
+			writeInternalError(l, w, err)
+			return
+		}
+		if len(res) == 0 {
+			writeEmptyResultError(w)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(res)
+	}
+}
+
+func handleCreateAlertRule(l *slog.Logger, q *dbgen.Queries, al *alerts.Alerter) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var body api.CreateAlertRulePayload
+		err := stools.DecodeJSONBody(r, &body)
+		if err != nil {
+			writeBadRequestError(w, err)
+			return
+		}
+		if body.Email == "" || body.ID == "" {
+			writeBadRequestError(w, fmt.Errorf("must supply email and id"))
+			return
+		}
+		if _, err = kt.GetRequestKindSpec(body.RequestKind); err != nil {
+			writeBadRequestError(w, err)
+			return
+		}
+		if !checkEmailAccess(w, r, body.Email) {
+			return
+		}
+		ids, err := grantedIDs(r, q, body.RequestKind, []string{body.ID})
+		if err != nil {
+			writeInternalError(l, w, err)
+			return
+		}
+		if len(ids) == 0 {
+			writeForbiddenError(w)
+			return
+		}
+		if err = api.ValidateMetricName(body.Metric); err != nil {
+			writeBadRequestError(w, err)
+			return
+		}
+		if err = alerts.ValidateRule(body.Condition, body.Params); err != nil {
+			writeBadRequestError(w, err)
+			return
+		}
+		n, ok := al.Notifier(body.Notifier)
+		if !ok {
+			writeBadRequestError(w, fmt.Errorf("unsupported notifier: %s", body.Notifier))
+			return
+		}
+		if err = n.ValidateTarget(body.Target); err != nil {
+			writeBadRequestError(w, err)
+			return
+		}
+		cooldown := body.CooldownSeconds
+		if cooldown == 0 {
+			cooldown = int(alerts.DefaultCooldown / time.Second)
+		}
+		if cooldown < 0 {
+			writeBadRequestError(w, fmt.Errorf("cooldown_seconds must not be negative"))
+			return
+		}
+
+		res, err := q.InsertAlertRule(r.Context(), dbgen.InsertAlertRuleParams{
+			Email:           body.Email,
+			RequestKind:     body.RequestKind,
+			ID:              body.ID,
+			Metric:          body.Metric,
+			Condition:       body.Condition,
+			Params:          body.Params,
+			Notifier:        body.Notifier,
+			Target:          body.Target,
+			CooldownSeconds: int32(cooldown),
+		})
+		if err != nil {
+			if stools.IsPGError(err, stools.PGErrorForeignKeyViolation) {
+				writeBadRequestError(w, fmt.Errorf("unable to create rule; be sure user (%s) exists", body.Email))
+				return
+			}
+			writeInternalError(l, w, err)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(res)
+	}
+}
+
+func handleDeleteAlertRules(l *slog.Logger, q *dbgen.Queries) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		email := r.URL.Query().Get("email")
+		if email == "" {
+			writeBadRequestError(w, fmt.Errorf("must supply email"))
+			return
+		}
+		if !checkEmailAccess(w, r, email) {
+			return
+		}
+		ids := []int64{}
+		for _, s := range r.URL.Query()["rule_id"] {
+			id, err := strconv.ParseInt(s, 10, 64)
+			if err != nil {
+				writeBadRequestError(w, fmt.Errorf("bad rule_id: %s", s))
+				return
+			}
+			ids = append(ids, id)
+		}
+		if len(ids) == 0 {
+			writeBadRequestError(w, fmt.Errorf("must supply rule_id(s)"))
+			return
+		}
+		err := q.DeleteAlertRules(r.Context(), dbgen.DeleteAlertRulesParams{Email: email, RuleIds: ids})
+		if err != nil {
+			writeInternalError(l, w, err)
+			return
+		}
+		writeOK(w)
+	}
+}
+
+// Returns the alerts fired for the user's rules, most recent first. The
+// history can be truncated with dur (e.g., 7d); it defaults to the past week.
+func handleGetAlertHistory(l *slog.Logger, q *dbgen.Queries) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		email := r.URL.Query().Get("email")
+		if email == "" {
+			writeBadRequestError(w, fmt.Errorf("must supply email"))
+			return
+		}
+		if !checkEmailAccess(w, r, email) {
+			return
+		}
+		dur := 7 * 24 * time.Hour
+		if s := r.URL.Query().Get("dur"); s != "" {
+			d, err := parseDuration(s)
+			if err != nil {
+				writeBadRequestError(w, fmt.Errorf("could not parse duration: %w", err))
+				return
+			}
+			dur = d
+		}
+		res, err := q.GetAlertHistory(r.Context(), dbgen.GetAlertHistoryParams{
+			Email:   email,
+			TsStart: pgtype.Timestamptz{Time: time.Now().Add(-dur), Valid: true},
+		})
+		if err != nil {
+			writeInternalError(l, w, err)
+			return
+		}
+		if len(res) == 0 {
+			writeEmptyResultError(w)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(res)
+	}
+}
+
+// Sends a synthetic alert through the supplied notifier. This is handy for
+// checking a notifier's configuration (e.g., against a local SMTP server).
+func handleTestNotifier(l *slog.Logger, al *alerts.Alerter) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var body api.TestNotifierPayload
+		err := stools.DecodeJSONBody(r, &body)
+		if err != nil {
+			writeBadRequestError(w, err)
+			return
+		}
+		n, ok := al.Notifier(body.Notifier)
+		if !ok {
+			writeBadRequestError(w, fmt.Errorf("unsupported notifier: %s", body.Notifier))
+			return
+		}
+		if err = n.ValidateTarget(body.Target); err != nil {
+			writeBadRequestError(w, err)
+			return
+		}
+		alert := alerts.Alert{
+			RequestKind: kt.RequestKindInternalRandom,
+			ID:          "test",
+			Metric:      "value",
+			Condition:   alerts.ConditionThreshold,
+			Ts:          time.Now(),
+			Message:     "This is a test alert from kaggo.",
+		}
+		ctx, cancel := context.WithTimeout(r.Context(), alerts.DeliveryTimeout)
+		defer cancel()
+		if err = n.Notify(ctx, body.Target, alert); err != nil {
+			writeBadRequestError(w, err)
+			return
+		}
+		writeOK(w)
+	}
+}
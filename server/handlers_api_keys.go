@@ -0,0 +1,126 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/brojonat/kaggo/server/api"
+	"github.com/brojonat/kaggo/server/db/dbgen"
+	"github.com/brojonat/server-tools/stools"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// Lists API keys (never the keys themselves, just their prefixes). Supplying
+// email restricts the list to the keys owned by that user.
+func handleGetAPIKeys(l *slog.Logger, q *dbgen.Queries) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		res, err := q.GetAPIKeys(r.Context(), r.URL.Query().Get("email"))
+		if err != nil {
+			writeInternalError(l, w, err)
+			return
+		}
+		if len(res) == 0 {
+			writeEmptyResultError(w)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(res)
+	}
+}
+
+// Creates an API key for the user. The response contains the plaintext key;
+// it can't be recovered later, only revoked and replaced.
+func handleCreateAPIKey(l *slog.Logger, q *dbgen.Queries) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var body api.CreateAPIKeyPayload
+		err := stools.DecodeJSONBody(r, &body)
+		if err != nil {
+			writeBadRequestError(w, err)
+			return
+		}
+		if body.Email == "" {
+			writeBadRequestError(w, fmt.Errorf("must supply email"))
+			return
+		}
+		if len(body.Scopes) == 0 {
+			writeBadRequestError(w, fmt.Errorf("must supply scope(s)"))
+			return
+		}
+		for _, s := range body.Scopes {
+			if !slices.Contains(apiKeyScopes, s) {
+				writeBadRequestError(w, fmt.Errorf("unsupported scope %q; must be one of %v", s, apiKeyScopes))
+				return
+			}
+		}
+		var expires pgtype.Timestamptz
+		if body.ExpiresIn != "" {
+			d, err := parseDuration(body.ExpiresIn)
+			if err != nil || d <= 0 {
+				writeBadRequestError(w, fmt.Errorf("bad expires_in: %s", body.ExpiresIn))
+				return
+			}
+			expires = pgtype.Timestamptz{Time: time.Now().Add(d), Valid: true}
+		}
+
+		secret, err := randomHex(32)
+		if err != nil {
+			writeInternalError(l, w, err)
+			return
+		}
+		key := apiKeyPrefix + secret
+		row, err := q.InsertAPIKey(r.Context(), dbgen.InsertAPIKeyParams{
+			Email:     body.Email,
+			Name:      body.Name,
+			KeyPrefix: key[:len(apiKeyPrefix)+8],
+			KeyHash:   hashAPIKey(key),
+			Scopes:    body.Scopes,
+			TsExpires: expires,
+		})
+		if err != nil {
+			if stools.IsPGError(err, stools.PGErrorForeignKeyViolation) {
+				writeBadRequestError(w, fmt.Errorf("unable to create key; be sure user (%s) exists", body.Email))
+				return
+			}
+			writeInternalError(l, w, err)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(api.CreateAPIKeyResponse{
+			KeyID:     row.KeyID,
+			Key:       key,
+			KeyPrefix: row.KeyPrefix,
+			Scopes:    row.Scopes,
+			ExpiresAt: row.TsExpires.Time,
+		})
+	}
+}
+
+// Revokes the supplied keys. Revoked keys are kept around (and listed) so
+// there's a record of them.
+func handleRevokeAPIKeys(l *slog.Logger, q *dbgen.Queries) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ids := []int64{}
+		for _, s := range r.URL.Query()["key_id"] {
+			id, err := strconv.ParseInt(s, 10, 64)
+			if err != nil {
+				writeBadRequestError(w, fmt.Errorf("bad key_id: %s", s))
+				return
+			}
+			ids = append(ids, id)
+		}
+		if len(ids) == 0 {
+			writeBadRequestError(w, fmt.Errorf("must supply key_id(s)"))
+			return
+		}
+		if err := q.RevokeAPIKeys(r.Context(), ids); err != nil {
+			writeInternalError(l, w, err)
+			return
+		}
+		writeOK(w)
+	}
+}
@@ -0,0 +1,63 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/brojonat/kaggo/server/api"
+	kt "github.com/brojonat/kaggo/temporal/v19700101"
+	"github.com/brojonat/server-tools/stools"
+	"go.temporal.io/sdk/client"
+)
+
+// Starts a workflow that replays archived responses through the current
+// extractors (see temporal/v19700101/backfill.go). The workflow runs in the
+// background; the response carries its ID.
+func handleRunBackfillWF(l *slog.Logger, tc client.Client) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var body api.BackfillPayload
+		err := stools.DecodeJSONBody(r, &body)
+		if err != nil {
+			writeBadRequestError(w, err)
+			return
+		}
+		if _, err = kt.GetRequestKindSpec(body.RequestKind); err != nil {
+			writeBadRequestError(w, err)
+			return
+		}
+		if body.End.IsZero() {
+			body.End = time.Now()
+		}
+		if !body.Start.Before(body.End) {
+			writeBadRequestError(w, fmt.Errorf("start must be before end"))
+			return
+		}
+
+		id := fmt.Sprintf("backfill %s %s", body.RequestKind, time.Now().UTC().Format(time.RFC3339))
+		_, err = tc.ExecuteWorkflow(
+			r.Context(),
+			client.StartWorkflowOptions{
+				ID:        id,
+				TaskQueue: os.Getenv("TEMPORAL_TASK_QUEUE"),
+			},
+			kt.BackfillWF,
+			kt.BackfillWFRequest{
+				RequestKind: body.RequestKind,
+				IDs:         body.IDs,
+				Metrics:     body.Metrics,
+				Start:       body.Start,
+				End:         body.End,
+			},
+		)
+		if err != nil {
+			writeInternalError(l, w, err)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(api.DefaultJSONResponse{Message: id})
+	}
+}
@@ -34,6 +34,12 @@ func writeBadRequestError(w http.ResponseWriter, err error) {
 	json.NewEncoder(w).Encode(resp)
 }
 
+func writeForbiddenError(w http.ResponseWriter) {
+	w.WriteHeader(http.StatusForbidden)
+	resp := api.DefaultJSONResponse{Error: "forbidden"}
+	json.NewEncoder(w).Encode(resp)
+}
+
 func writeEmptyResultError(w http.ResponseWriter) {
 	w.WriteHeader(http.StatusNotFound)
 	resp := api.DefaultJSONResponse{Error: "empty result set"}
@@ -52,7 +58,8 @@ func handlePing(l *slog.Logger, p *pgxpool.Pool) http.HandlerFunc {
 	}
 }
 
-// handleIssueToken returns a token
+// handleIssueToken returns a token for the basic auth email. The role query
+// parameter selects the token's role; it defaults to admin.
 func handleIssueToken(l *slog.Logger) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		email, ok := r.Context().Value(ctxKeyEmail).(string)
@@ -60,12 +67,21 @@ func handleIssueToken(l *slog.Logger) http.HandlerFunc {
 			writeInternalError(l, w, fmt.Errorf("missing context key for basic auth email"))
 			return
 		}
+		role := r.URL.Query().Get("role")
+		if role == "" {
+			role = roleAdmin
+		}
+		if role != roleAdmin && role != roleCustomer {
+			writeBadRequestError(w, fmt.Errorf("unsupported role: %s", role))
+			return
+		}
 		sc := jwt.StandardClaims{
 			ExpiresAt: time.Now().Add(2 * 7 * 24 * time.Hour).Unix(),
 		}
 		c := authJWTClaims{
 			StandardClaims: sc,
 			Email:          email,
+			Role:           role,
 		}
 		token, _ := generateAccessToken(c)
 		w.WriteHeader(http.StatusOK)
@@ -0,0 +1,170 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/brojonat/kaggo/server/api"
+	"github.com/brojonat/kaggo/server/db/dbgen"
+	kt "github.com/brojonat/kaggo/temporal/v19700101"
+	"github.com/brojonat/server-tools/stools"
+	"github.com/jackc/pgx/v5/pgtype"
+	"go.temporal.io/sdk/client"
+	"go.temporal.io/sdk/converter"
+	"go.temporal.io/sdk/temporal"
+)
+
+// Called by polling workflows to have the cadence of their schedule reviewed
+// (see temporal/v19700101/cadence.go). If the metric's recent rate of change
+// calls for a different interval, the schedule's spec is replaced with that
+// interval (keeping its start and end) and the reason is set in its note.
+func handleReviewScheduleCadence(l *slog.Logger, q *dbgen.Queries, tc client.Client) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var body api.ScheduleCadencePayload
+		err := stools.DecodeJSONBody(r, &body)
+		if err != nil {
+			writeBadRequestError(w, err)
+			return
+		}
+		h := tc.ScheduleClient().GetHandle(r.Context(), body.ScheduleID)
+		desc, err := h.Describe(r.Context())
+		if err != nil {
+			writeInternalError(l, w, err)
+			return
+		}
+		a := scheduleSearchAttributes(body.ScheduleID, desc.SearchAttributes)
+		rks, err := kt.GetRequestKindSpec(a.RequestKind)
+		if err != nil {
+			writeBadRequestError(w, err)
+			return
+		}
+		if rks.Cadence == nil {
+			writeBadRequestError(w, fmt.Errorf("%s does not have an adaptive cadence", a.RequestKind))
+			return
+		}
+		if skip := skipCadenceReview(desc); skip != "" {
+			writeOKMessage(w, skip)
+			return
+		}
+		current := scheduleInterval(desc.Schedule.Spec)
+
+		samples, err := getCadenceSamples(r.Context(), q, rks, a.EntityID, rks.Cadence.Window(current))
+		if err != nil {
+			writeInternalError(l, w, err)
+			return
+		}
+		d, reason, ok := rks.Cadence.Interval(samples)
+		if !ok {
+			writeOKMessage(w, fmt.Sprintf("not enough %s samples to adapt the cadence", rks.Cadence.Metric))
+			return
+		}
+		if d == current {
+			writeOKMessage(w, fmt.Sprintf("unchanged: %s", reason))
+			return
+		}
+		// extended schedules were slowed down on purpose (see lifetime.go)
+		if d < current && scheduleExtended(desc, rks) {
+			writeOKMessage(w, fmt.Sprintf("unchanged: schedule was extended, so it isn't sped up: %s", reason))
+			return
+		}
+
+		updated := false
+		err = h.Update(r.Context(), client.ScheduleUpdateOptions{
+			DoUpdate: func(in client.ScheduleUpdateInput) (*client.ScheduleUpdate, error) {
+				// the schedule may have been paused in the meantime
+				if skipCadenceReview(&in.Description) != "" {
+					return nil, temporal.ErrSkipScheduleUpdate
+				}
+				updated = true
+				s := in.Description.Schedule
+				s.Spec.Calendars = nil
+				s.Spec.CronExpressions = nil
+				s.Spec.Intervals = []client.ScheduleIntervalSpec{{Every: d}}
+				s.Spec.Jitter = d
+				s.State.Note = reason
+				return &client.ScheduleUpdate{Schedule: &s}, nil
+			},
+		})
+		if err != nil {
+			writeInternalError(l, w, err)
+			return
+		}
+		if !updated {
+			writeOKMessage(w, "schedule changed during review; skipped")
+			return
+		}
+		l.Info("adapted schedule cadence", "schedule_id", body.ScheduleID, "from", current, "to", d, "reason", reason)
+		writeOKMessage(w, reason)
+	}
+}
+
+// skipCadenceReview returns why the schedule's cadence shouldn't be adapted,
+// if it shouldn't be.
+func skipCadenceReview(desc *client.ScheduleDescription) string {
+	if desc.Schedule.State.Paused {
+		return "schedule is paused"
+	}
+	if fixedCadence(desc) {
+		return "schedule has a fixed cadence"
+	}
+	return ""
+}
+
+// fixedCadence reports whether the schedule was created with a fixed cadence.
+func fixedCadence(desc *client.ScheduleDescription) bool {
+	if desc.Memo == nil || desc.Memo.Fields[kt.MemoFixedCadence] == nil {
+		return false
+	}
+	var fixed bool
+	err := converter.GetDefaultDataConverter().FromPayload(desc.Memo.Fields[kt.MemoFixedCadence], &fixed)
+	return err == nil && fixed
+}
+
+// scheduleExtended reports whether the schedule's EndAt is past the kind's
+// default lifetime, i.e., it was extended by an end-of-life review.
+func scheduleExtended(desc *client.ScheduleDescription, rks kt.RequestKindSpec) bool {
+	if rks.DefaultLifetime == 0 || desc.Info.CreatedAt.IsZero() || desc.Schedule.Spec.EndAt.IsZero() {
+		return false
+	}
+	return desc.Schedule.Spec.EndAt.Sub(desc.Info.CreatedAt) > rks.DefaultLifetime+time.Hour
+}
+
+// getCadenceSamples returns the samples of the kind's cadence metric for the
+// entity over the last window, oldest first.
+func getCadenceSamples(ctx context.Context, q *dbgen.Queries, rks kt.RequestKindSpec, id string, window time.Duration) ([]kt.CadenceSample, error) {
+	now := time.Now()
+	rows, err := q.GetMetricSamplesByIDs(ctx, dbgen.GetMetricSamplesByIDsParams{
+		RequestKind: rks.Kind,
+		Ids:         []string{id},
+		TsStart:     pgtype.Timestamptz{Time: now.Add(-window), Valid: true},
+		TsEnd:       pgtype.Timestamptz{Time: now, Valid: true},
+	})
+	if err != nil {
+		return nil, err
+	}
+	samples := []kt.CadenceSample{}
+	for _, row := range rows {
+		if row.Metric == rks.Kind+"."+rks.Cadence.Metric {
+			samples = append(samples, kt.CadenceSample{Ts: row.Ts.Time, Value: row.Value})
+		}
+	}
+	return samples, nil
+}
+
+// scheduleInterval returns the interval of a spec with a single interval;
+// other specs (e.g., the calendars of the default schedules) return 0.
+func scheduleInterval(spec *client.ScheduleSpec) time.Duration {
+	if spec == nil || len(spec.Intervals) != 1 || len(spec.Calendars) > 0 || len(spec.CronExpressions) > 0 {
+		return 0
+	}
+	return spec.Intervals[0].Every
+}
+
+func writeOKMessage(w http.ResponseWriter, msg string) {
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(api.DefaultJSONResponse{Message: msg})
+}
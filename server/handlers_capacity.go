@@ -0,0 +1,121 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/brojonat/kaggo/server/api"
+	"github.com/brojonat/kaggo/server/schedspec"
+	"go.temporal.io/sdk/client"
+)
+
+const (
+	defaultCapacityHorizon = 24 * time.Hour
+	defaultCapacityBucket  = time.Hour
+	maxCapacityHorizon     = 30 * 24 * time.Hour
+	maxCapacityBuckets     = 1000
+)
+
+// Projects the polling load over the next horizon (default 24h) by evaluating
+// every schedule's spec; runs are counted per kind in bucket-wide (default 1h)
+// buckets. Use request_kind to only include one kind. This doesn't account for
+// jitter, so runs near the edge of a bucket may land in the next one.
+func handleGetScheduleCapacity(l *slog.Logger, tc client.Client) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		horizon, bucket := defaultCapacityHorizon, defaultCapacityBucket
+		var err error
+		if s := r.URL.Query().Get("horizon"); s != "" {
+			if horizon, err = parseDuration(s); err != nil {
+				writeBadRequestError(w, fmt.Errorf("bad horizon: %w", err))
+				return
+			}
+		}
+		if s := r.URL.Query().Get("bucket"); s != "" {
+			if bucket, err = parseDuration(s); err != nil {
+				writeBadRequestError(w, fmt.Errorf("bad bucket: %w", err))
+				return
+			}
+		}
+		if horizon <= 0 || horizon > maxCapacityHorizon {
+			writeBadRequestError(w, fmt.Errorf("horizon must be positive and at most %s", maxCapacityHorizon))
+			return
+		}
+		if bucket < time.Minute || horizon/bucket > maxCapacityBuckets {
+			writeBadRequestError(w, fmt.Errorf("bucket must be at least 1m and split the horizon into at most %d buckets", maxCapacityBuckets))
+			return
+		}
+		rk := r.URL.Query().Get("request_kind")
+
+		ss, err := tc.ScheduleClient().List(r.Context(), client.ScheduleListOptions{})
+		if err != nil {
+			writeInternalError(l, w, err)
+			return
+		}
+		start := time.Now().Truncate(bucket)
+		nbuckets := int((horizon + bucket - 1) / bucket)
+		kinds := map[string]*api.ScheduleCapacity{}
+		for ss.HasNext() {
+			s, err := ss.Next()
+			if err != nil {
+				writeInternalError(l, w, err)
+				return
+			}
+			k := scheduleSearchAttributes(s.ID, s.SearchAttributes).RequestKind
+			if rk != "" && k != rk {
+				continue
+			}
+			c, ok := kinds[k]
+			if !ok {
+				c = &api.ScheduleCapacity{RequestKind: k, Buckets: make([]int, nbuckets)}
+				kinds[k] = c
+			}
+			c.Schedules++
+			if s.Paused {
+				c.Paused++
+				continue
+			}
+			if s.Spec == nil {
+				l.Error("skipping schedule without a spec", "schedule_id", s.ID)
+				c.Unprojected++
+				continue
+			}
+			spec, err := schedspec.New(*s.Spec)
+			if err != nil {
+				l.Error("skipping schedule", "schedule_id", s.ID, "error", err)
+				c.Unprojected++
+				continue
+			}
+			for i, n := range spec.Histogram(start, bucket, nbuckets) {
+				c.Buckets[i] += n
+			}
+		}
+
+		res := api.ScheduleCapacityResponse{
+			Start:  start,
+			End:    start.Add(time.Duration(nbuckets) * bucket),
+			Bucket: bucket.String(),
+			Kinds:  []api.ScheduleCapacity{},
+		}
+		for _, c := range kinds {
+			for i, n := range c.Buckets {
+				c.Runs += n
+				if n > c.PeakRuns {
+					c.PeakRuns = n
+					c.PeakAt = start.Add(time.Duration(i) * bucket)
+				}
+			}
+			c.RunsPerHour = float64(c.Runs) / res.End.Sub(res.Start).Hours()
+			res.Kinds = append(res.Kinds, *c)
+		}
+		slices.SortFunc(res.Kinds, func(a, b api.ScheduleCapacity) int {
+			return strings.Compare(a.RequestKind, b.RequestKind)
+		})
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(res)
+	}
+}
@@ -0,0 +1,56 @@
+package server
+
+import (
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/brojonat/kaggo/server/api"
+	kt "github.com/brojonat/kaggo/temporal/v19700101"
+	"github.com/stretchr/testify/mock"
+	"go.temporal.io/sdk/client"
+	"go.temporal.io/sdk/mocks"
+)
+
+func TestScheduleCapacitySkipsSchedulesWithoutSpec(t *testing.T) {
+	l := slog.New(slog.NewTextHandler(io.Discard, nil))
+	entries := []*client.ScheduleListEntry{
+		{ID: "internal.random 1 s1"},
+		{ID: "internal.random 2 s2", Spec: &client.ScheduleSpec{
+			Intervals: []client.ScheduleIntervalSpec{{Every: time.Hour}},
+		}},
+	}
+	it := &mocks.ScheduleListIterator{}
+	for _, e := range entries {
+		it.On("HasNext").Return(true).Once()
+		it.On("Next").Return(e, nil).Once()
+	}
+	it.On("HasNext").Return(false)
+	sc := &mocks.ScheduleClient{}
+	sc.On("List", mock.Anything, mock.Anything).Return(it, nil)
+	tc := &mocks.Client{}
+	tc.On("ScheduleClient").Return(sc)
+
+	r := httptest.NewRequest(http.MethodGet, "/schedule/capacity?horizon=24h&bucket=1h", nil)
+	w := httptest.NewRecorder()
+	handleGetScheduleCapacity(l, tc)(w, r)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var res api.ScheduleCapacityResponse
+	if err := json.NewDecoder(w.Body).Decode(&res); err != nil {
+		t.Fatal(err)
+	}
+	if len(res.Kinds) != 1 || res.Kinds[0].RequestKind != kt.RequestKindInternalRandom {
+		t.Fatalf("expected a single %s entry, got %+v", kt.RequestKindInternalRandom, res.Kinds)
+	}
+	c := res.Kinds[0]
+	if c.Schedules != 2 || c.Unprojected != 1 || c.Runs != 24 {
+		t.Errorf("expected 2 schedules, 1 unprojected and 24 runs, got %+v", c)
+	}
+}
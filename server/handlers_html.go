@@ -8,12 +8,12 @@ import (
 	"net/http"
 	"net/url"
 	"os"
-	"strings"
 	"time"
 
 	grob "github.com/MetalBlueberry/go-plotly/generated/v2.31.1/graph_objects"
 	"github.com/MetalBlueberry/go-plotly/pkg/types"
 	"github.com/brojonat/kaggo/server/db/dbgen"
+	"github.com/brojonat/kaggo/server/schedspec"
 	kt "github.com/brojonat/kaggo/temporal/v19700101"
 	"github.com/jackc/pgx/v5/pgtype"
 	"go.temporal.io/sdk/client"
@@ -49,65 +49,44 @@ func handleGetPlotData(l *slog.Logger, q *dbgen.Queries, tc client.Client) http.
 				return
 			}
 
-			type schedule struct {
-				RequestKind string
-				StartTS     float64
-				EndTS       float64
+			// Construct the abscissa by starting at the beginning of today and
+			// adding nbins that are a day wide.
+			now := time.Now()
+			today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+			nbins := 30
+			abscissa := make([]time.Time, nbins)
+			for i := range nbins {
+				abscissa[i] = today.AddDate(0, 0, i)
 			}
-			data := []schedule{}
 
-			for {
-				if !ss.HasNext() {
-					break
-				}
+			// For each bin, count the number of schedules that will run at
+			// least once in that bin. This is partitioned by RequestKind.
+			// Paused schedules don't run.
+			counts := map[string][]float64{}
+			for ss.HasNext() {
 				s, err := ss.Next()
 				if err != nil {
 					break
 				}
-				rk := strings.Split(s.ID, " ")[0]
-				st := s.Spec.StartAt.Unix()
-				et := s.Spec.EndAt.Unix()
-				i := schedule{
-					RequestKind: rk,
-					StartTS:     float64(st),
-					EndTS:       float64(et),
+				if s.Paused {
+					continue
 				}
-				data = append(data, i)
-			}
-
-			truncateToDay := func(t time.Time) time.Time {
-				return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
-			}
-
-			// Construct the abscissa by starting at the beginning of today and
-			// adding nbins that are binsize wide rounded to the nearest minute.
-			binsize := 24 * 3600
-			nbins := 30
-			abscissa := make([]float64, nbins)
-			for i := 0; i < nbins; i++ {
-				offset := time.Duration(time.Duration(i*binsize) * time.Second)
-				abscissa[i] = float64(truncateToDay(time.Now()).Add(offset.Round(time.Minute)).Unix())
-			}
-
-			// Loop over the abscissa and for each bin, count the number of schedules that
-			// will be running in that bin. This is partitioned by RequestKind.
-			counts := map[string][]float64{}
-			for i, x := range abscissa {
-				binUpper := x + float64(binsize)
-				for _, s := range data {
-					// schedules with no start/end time defined will have the
-					// zero value of the time, so handle those cases as well as
-					// the naive check for bin containment
-					startsBefore := bool(s.StartTS < x)
-					doesNotEnd := time.Unix(int64(s.EndTS), 0).IsZero()
-					runsIn := bool(s.StartTS <= x && s.EndTS > x)
-					runsOut := bool(s.StartTS < binUpper && s.EndTS >= x)
-					enclosed := bool(s.StartTS >= x && s.EndTS <= binUpper)
-					if (startsBefore && doesNotEnd) || runsIn || runsOut || enclosed {
-						if _, ok := counts[s.RequestKind]; !ok {
-							counts[s.RequestKind] = make([]float64, nbins)
-						}
-						counts[s.RequestKind][i] += 1
+				if s.Spec == nil {
+					l.Error("skipping schedule without a spec", "schedule_id", s.ID)
+					continue
+				}
+				spec, err := schedspec.New(*s.Spec)
+				if err != nil {
+					l.Error("skipping schedule", "schedule_id", s.ID, "error", err)
+					continue
+				}
+				rk := scheduleSearchAttributes(s.ID, s.SearchAttributes).RequestKind
+				if _, ok := counts[rk]; !ok {
+					counts[rk] = make([]float64, nbins)
+				}
+				for i, x := range abscissa {
+					if n := spec.Next(x.Add(-time.Nanosecond)); !n.IsZero() && n.Before(x.AddDate(0, 0, 1)) {
+						counts[rk][i] += 1
 					}
 				}
 			}
@@ -115,7 +94,7 @@ func handleGetPlotData(l *slog.Logger, q *dbgen.Queries, tc client.Client) http.
 			// relabel x axis
 			tss := []string{}
 			for _, a := range abscissa {
-				tss = append(tss, time.Unix(int64(a), 0).Format(time.RFC3339))
+				tss = append(tss, a.Format(time.RFC3339))
 			}
 
 			// construct the traces
@@ -152,31 +131,37 @@ func handleGetPlotData(l *slog.Logger, q *dbgen.Queries, tc client.Client) http.
 			}
 
 			type schedule struct {
-				ID      string
-				Nexts   []time.Time
-				Jitter  time.Duration
-				Comment string
+				ID     string
+				Nexts  []time.Time
+				Jitter time.Duration
 			}
 			schedules := []schedule{}
 
-			for {
-				if !ss.HasNext() {
-					break
-				}
+			// show (up to) the next 10 runs over the next 3 days
+			now := time.Now()
+			for ss.HasNext() {
 				s, err := ss.Next()
 				if err != nil {
 					break
 				}
-				parts := strings.Split(s.ID, " ")
-				id := fmt.Sprintf("%s %s", parts[0], parts[1])
-				nexts := getNextActionTimesNoJitter(s.Spec, 3)
-				i := schedule{
-					ID:      id,
-					Nexts:   nexts,
-					Jitter:  s.Spec.Jitter,
-					Comment: s.Spec.Calendars[0].Comment,
+				if s.Paused {
+					continue
+				}
+				if s.Spec == nil {
+					l.Error("skipping schedule without a spec", "schedule_id", s.ID)
+					continue
 				}
-				schedules = append(schedules, i)
+				spec, err := schedspec.New(*s.Spec)
+				if err != nil {
+					l.Error("skipping schedule", "schedule_id", s.ID, "error", err)
+					continue
+				}
+				a := scheduleSearchAttributes(s.ID, s.SearchAttributes)
+				schedules = append(schedules, schedule{
+					ID:     fmt.Sprintf("%s %s", a.RequestKind, a.EntityID),
+					Nexts:  spec.Runs(now, now.AddDate(0, 0, 3), 10),
+					Jitter: spec.Jitter(),
+				})
 			}
 
 			// construct the traces
@@ -188,18 +173,11 @@ func handleGetPlotData(l *slog.Logger, q *dbgen.Queries, tc client.Client) http.
 				ys := []string{}
 
 				for _, n := range s.Nexts {
-					if n.Before(time.Now()) {
-						continue
-					}
 					xs = append(xs, n)
 					xls = append(xls, n.Add(s.Jitter))
 					ys = append(ys, s.ID)
 				}
 
-				xs = xs[0:10]
-				xls = xls[0:10]
-				ys = ys[0:10]
-
 				traces = append(traces, &grob.Scatter{
 					Marker:      &grob.ScatterMarker{Size: types.ArrayOKValue(types.N(20))},
 					X:           types.DataArray(xs),
@@ -242,12 +220,10 @@ func handleGetPlotData(l *slog.Logger, q *dbgen.Queries, tc client.Client) http.
 
 		case PlotKindUserPulse:
 			id := r.URL.Query().Get("id")
-			user_metrics, err := q.GetRedditUserMetricsByIDsBucket15Min(
-				r.Context(), dbgen.GetRedditUserMetricsByIDsBucket15MinParams{
-					Ids:     []string{id},
-					TsStart: pgtype.Timestamptz{Time: time.Time{}, Valid: true},
-					TsEnd:   pgtype.Timestamptz{Time: time.Now(), Valid: true},
-				},
+			user_metrics, err := getTimeSeriesBucketed(
+				r.Context(), l, q, kt.RequestKindRedditUser, []string{id},
+				pgtype.Interval{Microseconds: (15 * time.Minute).Microseconds(), Valid: true},
+				aggregateMax, time.Time{}, time.Now(),
 			)
 
 			if err != nil {
@@ -319,41 +295,3 @@ func handleGetPlots(l *slog.Logger, q *dbgen.Queries) http.HandlerFunc {
 
 	}
 }
-
-// This is a broken but useful helper function. This assumes that the supplied
-// ScheduleSpec has one calendar with one schedule range, no skips, and that
-// every day has the same schedule. This is sufficient for now but ultimately
-// will need to be improved to handle more complicated schedules. Ideally
-// temporal would expose this functionality but alas.
-func getNextActionTimesNoJitter(s *client.ScheduleSpec, ndays int) []time.Time {
-
-	seconds := []int{}
-	minutes := []int{}
-	hours := []int{}
-
-	for t := s.Calendars[0].Second[0].Start; t <= s.Calendars[0].Second[0].End; t += s.Calendars[0].Second[0].Step {
-		seconds = append(seconds, t)
-	}
-	for t := s.Calendars[0].Minute[0].Start; t <= s.Calendars[0].Minute[0].End; t += s.Calendars[0].Minute[0].Step {
-		minutes = append(minutes, t)
-	}
-	for t := s.Calendars[0].Hour[0].Start; t <= s.Calendars[0].Hour[0].End; t += s.Calendars[0].Hour[0].Step {
-		hours = append(hours, t)
-	}
-
-	// for the next 5 days, get all the scheduled runs
-	now := time.Now()
-	nexts := []time.Time{}
-	for nday := range ndays {
-		for _, h := range hours {
-			for _, m := range minutes {
-				for _, sec := range seconds {
-					date := now.AddDate(0, 0, nday)
-					itert := time.Date(date.Year(), date.Month(), date.Day(), h, m, sec, 0, date.Location())
-					nexts = append(nexts, itert)
-				}
-			}
-		}
-	}
-	return nexts
-}
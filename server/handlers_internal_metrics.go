@@ -6,10 +6,11 @@ import (
 	"log/slog"
 	"math/rand"
 	"net/http"
-	"time"
 
+	"github.com/brojonat/kaggo/server/alerts"
 	"github.com/brojonat/kaggo/server/api"
 	"github.com/brojonat/kaggo/server/db/dbgen"
+	kt "github.com/brojonat/kaggo/temporal/v19700101"
 	"github.com/prometheus/client_golang/prometheus"
 )
 
@@ -27,28 +28,7 @@ func handleInternalMetricsGenerate(l *slog.Logger) http.HandlerFunc {
 	}
 }
 
-func handleInternalMetricsGet(l *slog.Logger, q *dbgen.Queries) http.HandlerFunc {
-	return func(w http.ResponseWriter, r *http.Request) {
-		ids := r.URL.Query()["id"]
-		if len(ids) == 0 {
-			writeBadRequestError(w, fmt.Errorf("must supply timeseries id"))
-			return
-		}
-		res, err := getInternalRandomTimeSeries(r.Context(), l, q, ids, time.Time{}, time.Now())
-		if err != nil {
-			writeInternalError(l, w, err)
-			return
-		}
-		if res == nil {
-			writeEmptyResultError(w)
-			return
-		}
-		w.WriteHeader(http.StatusOK)
-		json.NewEncoder(w).Encode(res)
-	}
-}
-
-func handleInternalMetricsPost(l *slog.Logger, q *dbgen.Queries, pms map[string]prometheus.Collector) http.HandlerFunc {
+func handleInternalMetricsPost(l *slog.Logger, q *dbgen.Queries, al *alerts.Alerter, pms map[string]prometheus.Collector) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		// parse
 		var p api.InternalMetricPayload
@@ -62,6 +42,15 @@ func handleInternalMetricsPost(l *slog.Logger, q *dbgen.Queries, pms map[string]
 			writeBadRequestError(w, fmt.Errorf("must supply id"))
 			return
 		}
+		if p.IdempotencyKey != "" && p.Ts == nil {
+			writeBadRequestError(w, fmt.Errorf("must supply ts with idempotency_key"))
+			return
+		}
+		ts, err := api.SampleTime(p.Ts)
+		if err != nil {
+			writeBadRequestError(w, err)
+			return
+		}
 
 		// Set Prometheus metrics. This is a dummy example that simply records
 		// the value associated with the supplied ID.
@@ -79,12 +68,10 @@ func handleInternalMetricsPost(l *slog.Logger, q *dbgen.Queries, pms map[string]
 			l.Error("failed to locate PromMetricInternalRandom, skipping")
 		}
 
-		err = q.InsertInternalRandom(
-			r.Context(),
-			dbgen.InsertInternalRandomParams{
-				ID:    p.ID,
-				Value: int32(p.Value),
-			})
+		err = insertMetricSamples(
+			r.Context(), q, al, kt.RequestKindInternalRandom, p.ID,
+			map[string]float64{"value": float64(p.Value)}, ts, p.IdempotencyKey,
+		)
 		if err != nil {
 			writeInternalError(l, w, err)
 			return
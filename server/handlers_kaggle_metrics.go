@@ -5,34 +5,14 @@ import (
 	"fmt"
 	"log/slog"
 	"net/http"
-	"time"
 
+	"github.com/brojonat/kaggo/server/alerts"
 	"github.com/brojonat/kaggo/server/api"
 	"github.com/brojonat/kaggo/server/db/dbgen"
+	kt "github.com/brojonat/kaggo/temporal/v19700101"
 )
 
-func handleKaggleNotebookMetricsGet(l *slog.Logger, q *dbgen.Queries) http.HandlerFunc {
-	return func(w http.ResponseWriter, r *http.Request) {
-		ids := r.URL.Query()["id"]
-		if len(ids) == 0 {
-			writeBadRequestError(w, fmt.Errorf("must supply id(s)"))
-			return
-		}
-		res, err := getKaggleNotebookTimeSeries(r.Context(), l, q, ids, time.Time{}, time.Now())
-		if err != nil {
-			writeInternalError(l, w, err)
-			return
-		}
-		if res == nil {
-			writeEmptyResultError(w)
-			return
-		}
-		w.WriteHeader(http.StatusOK)
-		json.NewEncoder(w).Encode(res)
-	}
-}
-
-func handleKaggleNotebookPost(l *slog.Logger, q *dbgen.Queries) http.HandlerFunc {
+func handleKaggleNotebookPost(l *slog.Logger, q *dbgen.Queries, al *alerts.Alerter) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		// parse
 		var p api.KaggleNotebookMetricPayload
@@ -46,47 +26,30 @@ func handleKaggleNotebookPost(l *slog.Logger, q *dbgen.Queries) http.HandlerFunc
 			writeBadRequestError(w, fmt.Errorf("must supply id"))
 			return
 		}
-
-		if p.SetVotes {
-			err = q.InsertKaggleNotebookVotes(
-				r.Context(),
-				dbgen.InsertKaggleNotebookVotesParams{
-					ID:    p.ID,
-					Votes: int32(p.Votes),
-				})
-			if err != nil {
-				writeInternalError(l, w, err)
-				return
-			}
+		ts, err := api.SampleTime(p.Ts)
+		if err != nil {
+			writeBadRequestError(w, err)
+			return
 		}
 
-		writeOK(w)
-	}
-}
-
-func handleKaggleDatasetMetricsGet(l *slog.Logger, q *dbgen.Queries) http.HandlerFunc {
-	return func(w http.ResponseWriter, r *http.Request) {
-		ids := r.URL.Query()["id"]
-		if len(ids) == 0 {
-			writeBadRequestError(w, fmt.Errorf("must supply timeseries slug"))
-			return
+		// upload metrics
+		ms := map[string]float64{}
+		if p.SetVotes {
+			ms["votes"] = float64(p.Votes)
 		}
-		res, err := getKaggleDatasetTimeSeries(r.Context(), l, q, ids, time.Time{}, time.Now())
+		err = insertMetricSamples(r.Context(), q, al, kt.RequestKindKaggleNotebook, p.ID, ms, ts, "")
 		if err != nil {
 			writeInternalError(l, w, err)
 			return
 		}
-		if res == nil {
-			writeEmptyResultError(w)
-			return
-		}
-		w.WriteHeader(http.StatusOK)
-		json.NewEncoder(w).Encode(res)
+
+		writeOK(w)
 	}
 }
 
-func handleKaggleDatasetPost(l *slog.Logger, q *dbgen.Queries) http.HandlerFunc {
+func handleKaggleDatasetPost(l *slog.Logger, q *dbgen.Queries, al *alerts.Alerter) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
+		// parse
 		var p api.KaggleDatasetMetricPayload
 		defer r.Body.Close()
 		err := json.NewDecoder(r.Body).Decode(&p)
@@ -98,41 +61,27 @@ func handleKaggleDatasetPost(l *slog.Logger, q *dbgen.Queries) http.HandlerFunc
 			writeBadRequestError(w, fmt.Errorf("must supply slug"))
 			return
 		}
+		ts, err := api.SampleTime(p.Ts)
+		if err != nil {
+			writeBadRequestError(w, err)
+			return
+		}
 
+		// upload metrics
+		ms := map[string]float64{}
 		if p.SetVotes {
-			err = q.InsertKaggleDatasetVotes(
-				r.Context(),
-				dbgen.InsertKaggleDatasetVotesParams{
-					ID: p.ID, Votes: int32(p.Votes),
-				})
-			if err != nil {
-				writeInternalError(l, w, err)
-				return
-			}
+			ms["votes"] = float64(p.Votes)
 		}
-
 		if p.SetViews {
-			err = q.InsertKaggleDatasetViews(
-				r.Context(),
-				dbgen.InsertKaggleDatasetViewsParams{
-					ID: p.ID, Views: int32(p.Views),
-				})
-			if err != nil {
-				writeInternalError(l, w, err)
-				return
-			}
+			ms["views"] = float64(p.Views)
 		}
-
 		if p.SetDownloads {
-			err = q.InsertKaggleDatasetDownloads(
-				r.Context(),
-				dbgen.InsertKaggleDatasetDownloadsParams{
-					ID: p.ID, Downloads: int32(p.Downloads),
-				})
-			if err != nil {
-				writeInternalError(l, w, err)
-				return
-			}
+			ms["downloads"] = float64(p.Downloads)
+		}
+		err = insertMetricSamples(r.Context(), q, al, kt.RequestKindKaggleDataset, p.ID, ms, ts, "")
+		if err != nil {
+			writeInternalError(l, w, err)
+			return
 		}
 
 		writeOK(w)
@@ -0,0 +1,173 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/brojonat/kaggo/server/api"
+	"github.com/brojonat/kaggo/server/db/dbgen"
+	kt "github.com/brojonat/kaggo/temporal/v19700101"
+	"github.com/brojonat/server-tools/stools"
+	"go.temporal.io/api/serviceerror"
+	"go.temporal.io/sdk/client"
+	"go.temporal.io/sdk/temporal"
+)
+
+// Reviews are only done within kt.EndOfLifeReviewLead of a schedule's EndAt,
+// give or take this much for clock skew between the worker and the server.
+const eolReviewSlack = time.Hour
+
+// Called by WatchScheduleExpiryWF a day before a schedule's EndAt (see
+// temporal/v19700101/lifetime.go). If the entity is still growing, the
+// schedule is extended at a reduced cadence and the new EndAt is returned;
+// otherwise the current EndAt is returned and the schedule is left to expire.
+// Deleted schedules (and schedules whose EndAt was removed) are done. Schedules
+// whose EndAt isn't due for review yet (e.g., because it was extended through
+// PUT /schedule after the watcher went to sleep) are left alone; the watcher
+// waits for the new EndAt and comes back.
+func handleReviewScheduleEndOfLife(l *slog.Logger, q *dbgen.Queries, tc client.Client) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var body api.ScheduleExpiryPayload
+		err := stools.DecodeJSONBody(r, &body)
+		if err != nil {
+			writeBadRequestError(w, err)
+			return
+		}
+		h := tc.ScheduleClient().GetHandle(r.Context(), body.ScheduleID)
+		desc, err := h.Describe(r.Context())
+		if err != nil {
+			var nf *serviceerror.NotFound
+			if errors.As(err, &nf) {
+				w.WriteHeader(http.StatusOK)
+				json.NewEncoder(w).Encode(api.ScheduleExpiryResponse{Done: true})
+				return
+			}
+			writeInternalError(l, w, err)
+			return
+		}
+		endAt := desc.Schedule.Spec.EndAt
+		if endAt.IsZero() {
+			w.WriteHeader(http.StatusOK)
+			json.NewEncoder(w).Encode(api.ScheduleExpiryResponse{Done: true})
+			return
+		}
+		if time.Until(endAt) > kt.EndOfLifeReviewLead+eolReviewSlack {
+			w.WriteHeader(http.StatusOK)
+			json.NewEncoder(w).Encode(api.ScheduleExpiryResponse{EndAt: endAt})
+			return
+		}
+		a := scheduleSearchAttributes(body.ScheduleID, desc.SearchAttributes)
+		rks, err := kt.GetRequestKindSpec(a.RequestKind)
+		if err != nil {
+			writeBadRequestError(w, err)
+			return
+		}
+		// paused schedules (e.g., for deleted content) aren't growing
+		if desc.Schedule.State.Paused || rks.Cadence == nil {
+			w.WriteHeader(http.StatusOK)
+			json.NewEncoder(w).Encode(api.ScheduleExpiryResponse{EndAt: endAt})
+			return
+		}
+
+		samples, err := getCadenceSamples(r.Context(), q, rks, a.EntityID, kt.EndOfLifeWindow)
+		if err != nil {
+			writeInternalError(l, w, err)
+			return
+		}
+		current := scheduleInterval(desc.Schedule.Spec)
+		dec := rks.ReviewEndOfLife(samples, current, endAt.Sub(desc.Info.CreatedAt))
+		if !dec.Extend {
+			l.Info("schedule reached end of life", "schedule_id", body.ScheduleID, "reason", dec.Reason)
+			w.WriteHeader(http.StatusOK)
+			json.NewEncoder(w).Encode(api.ScheduleExpiryResponse{EndAt: endAt})
+			return
+		}
+
+		newEndAt := endAt.Add(dec.By)
+		updated := false
+		err = h.Update(r.Context(), client.ScheduleUpdateOptions{
+			DoUpdate: func(in client.ScheduleUpdateInput) (*client.ScheduleUpdate, error) {
+				// the schedule may have been paused or extended in the meantime
+				if in.Description.Schedule.State.Paused || !in.Description.Schedule.Spec.EndAt.Equal(endAt) {
+					newEndAt = in.Description.Schedule.Spec.EndAt
+					return nil, temporal.ErrSkipScheduleUpdate
+				}
+				updated = true
+				s := in.Description.Schedule
+				s.Spec.EndAt = newEndAt
+				if !fixedCadence(&in.Description) {
+					s.Spec.Calendars = nil
+					s.Spec.CronExpressions = nil
+					s.Spec.Intervals = []client.ScheduleIntervalSpec{{Every: dec.Every}}
+					s.Spec.Jitter = dec.Every
+				}
+				s.State.Note = dec.Reason
+				return &client.ScheduleUpdate{Schedule: &s}, nil
+			},
+		})
+		if err != nil {
+			writeInternalError(l, w, err)
+			return
+		}
+		if updated {
+			l.Info("extended schedule", "schedule_id", body.ScheduleID, "end_at", newEndAt, "reason", dec.Reason)
+		}
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(api.ScheduleExpiryResponse{EndAt: newEndAt, Done: newEndAt.IsZero()})
+	}
+}
+
+// Starts expiry watchers for existing schedules with an EndAt (e.g., schedules
+// created before the watchers existed, or whose watcher failed to start). It's
+// safe to run repeatedly; schedules that are already watched are left alone.
+func handleWatchScheduleExpiry(l *slog.Logger, tc client.Client, wh *webhooker) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var body api.ScheduleWatchExpiryPayload
+		if err := stools.DecodeJSONBody(r, &body); err != nil {
+			writeBadRequestError(w, err)
+			return
+		}
+		ss, err := tc.ScheduleClient().List(r.Context(), client.ScheduleListOptions{})
+		if err != nil {
+			writeInternalError(l, w, err)
+			return
+		}
+		res := api.ScheduleWatchExpiryResponse{Watched: []string{}, Failed: map[string]string{}}
+		for ss.HasNext() {
+			s, err := ss.Next()
+			if err != nil {
+				writeInternalError(l, w, err)
+				return
+			}
+			a := scheduleSearchAttributes(s.ID, s.SearchAttributes)
+			if body.RequestKind != "" && a.RequestKind != body.RequestKind {
+				continue
+			}
+			var endAt time.Time
+			if s.Spec != nil {
+				endAt = s.Spec.EndAt
+			} else {
+				desc, err := tc.ScheduleClient().GetHandle(r.Context(), s.ID).Describe(r.Context())
+				if err != nil {
+					res.Failed[s.ID] = err.Error()
+					continue
+				}
+				endAt = desc.Schedule.Spec.EndAt
+			}
+			if endAt.IsZero() {
+				continue
+			}
+			if err := wh.watchScheduleExpiry(r.Context(), s.ID, endAt); err != nil {
+				res.Failed[s.ID] = err.Error()
+				continue
+			}
+			res.Watched = append(res.Watched, s.ID)
+		}
+		l.Info("started schedule expiry watchers", "watched", len(res.Watched), "failed", len(res.Failed))
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(res)
+	}
+}
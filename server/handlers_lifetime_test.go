@@ -0,0 +1,93 @@
+package server
+
+import (
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/brojonat/kaggo/server/api"
+	kt "github.com/brojonat/kaggo/temporal/v19700101"
+	"github.com/stretchr/testify/mock"
+	"go.temporal.io/sdk/client"
+	"go.temporal.io/sdk/mocks"
+)
+
+// A watcher that wakes up for an EndAt that has since been extended mustn't
+// extend the schedule again.
+func TestReviewScheduleEndOfLifeSkipsEndAtNotDue(t *testing.T) {
+	l := slog.New(slog.NewTextHandler(io.Discard, nil))
+	endAt := time.Now().Add(kt.EndOfLifeReviewLead + 7*24*time.Hour).Truncate(time.Second)
+	h := &mocks.ScheduleHandle{}
+	h.On("Describe", mock.Anything).Return(&client.ScheduleDescription{
+		Schedule: client.Schedule{
+			Spec: &client.ScheduleSpec{
+				EndAt:     endAt,
+				Intervals: []client.ScheduleIntervalSpec{{Every: time.Hour}},
+			},
+		},
+	}, nil)
+	sc := &mocks.ScheduleClient{}
+	sc.On("GetHandle", mock.Anything, mock.Anything).Return(h)
+	tc := &mocks.Client{}
+	tc.On("ScheduleClient").Return(sc)
+
+	body := `{"schedule_id": "youtube.video abc s1"}`
+	r := httptest.NewRequest(http.MethodPost, "/schedule/end-of-life", strings.NewReader(body))
+	w := httptest.NewRecorder()
+	handleReviewScheduleEndOfLife(l, nil, tc)(w, r)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var res api.ScheduleExpiryResponse
+	if err := json.NewDecoder(w.Body).Decode(&res); err != nil {
+		t.Fatal(err)
+	}
+	if res.Done || !res.EndAt.Equal(endAt) {
+		t.Errorf("expected EndAt %s back unchanged, got %+v", endAt, res)
+	}
+	h.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+}
+
+func TestWatchScheduleExpiryStartsWatchersForSchedulesWithEndAt(t *testing.T) {
+	l := slog.New(slog.NewTextHandler(io.Discard, nil))
+	endAt := time.Now().Add(7 * 24 * time.Hour).Truncate(time.Second)
+	entries := []*client.ScheduleListEntry{
+		{ID: "youtube.video a s1", Spec: &client.ScheduleSpec{EndAt: endAt}},
+		{ID: "youtube.channel b s2", Spec: &client.ScheduleSpec{}},
+	}
+	it := &mocks.ScheduleListIterator{}
+	for _, e := range entries {
+		it.On("HasNext").Return(true).Once()
+		it.On("Next").Return(e, nil).Once()
+	}
+	it.On("HasNext").Return(false)
+	sc := &mocks.ScheduleClient{}
+	sc.On("List", mock.Anything, mock.Anything).Return(it, nil)
+	tc := &mocks.Client{}
+	tc.On("ScheduleClient").Return(sc)
+	tc.On("ExecuteWorkflow", mock.Anything, mock.Anything, mock.Anything, kt.WatchScheduleExpiryWFRequest{
+		ScheduleID: "youtube.video a s1", EndAt: endAt,
+	}).Return(&mocks.WorkflowRun{}, nil).Once()
+
+	r := httptest.NewRequest(http.MethodPost, "/schedule/watch-expiry", strings.NewReader(`{}`))
+	w := httptest.NewRecorder()
+	handleWatchScheduleExpiry(l, tc, newWebhooker(l, nil, tc))(w, r)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var res api.ScheduleWatchExpiryResponse
+	if err := json.NewDecoder(w.Body).Decode(&res); err != nil {
+		t.Fatal(err)
+	}
+	if len(res.Watched) != 1 || res.Watched[0] != "youtube.video a s1" || len(res.Failed) != 0 {
+		t.Errorf("expected only the schedule with an EndAt to be watched, got %+v", res)
+	}
+	tc.AssertExpectations(t)
+}
@@ -8,6 +8,7 @@ import (
 	"log/slog"
 	"net/http"
 	"os"
+	"slices"
 
 	"github.com/brojonat/kaggo/server/api"
 	"github.com/brojonat/kaggo/server/db/dbgen"
@@ -78,7 +79,14 @@ func handleGetMetricMetadata(l *slog.Logger, q *dbgen.Queries) http.HandlerFunc
 			writeInternalError(l, w, err)
 			return
 		}
-		if res == nil {
+		c, _ := getCaller(r)
+		g, err := getCallerGrants(r.Context(), q, c)
+		if err != nil {
+			writeInternalError(l, w, err)
+			return
+		}
+		res = slices.DeleteFunc(res, func(m dbgen.Metadatum) bool { return !g.allows(m.RequestKind, m.ID) })
+		if len(res) == 0 {
 			writeEmptyResultError(w)
 			return
 		}
@@ -98,30 +106,31 @@ func handleGetChildrenMetadata(l *slog.Logger, q *dbgen.Queries) http.HandlerFun
 			return
 		}
 
-		var childRK, ownerField string
-		switch rk {
-		case kt.RequestKindRedditUser:
-			childRK = kt.RequestKindRedditPost
-			ownerField = "parent_user_name"
-		case kt.RequestKindRedditSubreddit:
-			childRK = kt.RequestKindRedditPost
-			ownerField = "parent_subreddit"
-		case kt.RequestKindYouTubeChannel:
-			childRK = kt.RequestKindYouTubeVideo
-			ownerField = "parent_channel_id"
-		default:
+		rks, err := kt.GetRequestKindSpec(rk)
+		if err != nil || rks.Children == nil {
 			writeBadRequestError(w, fmt.Errorf("unsupported request_kind %s", rk))
 			return
 		}
-		// FIXME: ownerField must be injected somehow
-		l.Info("need to inject", "ownerField", ownerField)
+
+		// customers can see the children of the parents granted to them
+		c, _ := getCaller(r)
+		g, err := getCallerGrants(r.Context(), q, c)
+		if err != nil {
+			writeInternalError(l, w, err)
+			return
+		}
+		if !g.allows(rk, id) {
+			writeEmptyResultError(w)
+			return
+		}
 
 		res, err := q.GetChildrenMetadataByID(
 			r.Context(),
 			dbgen.GetChildrenMetadataByIDParams{
+				ParentField:       rks.Children.ParentField,
+				ChildRequestKind:  rks.Children.Kind,
 				ID:                id,
 				ParentRequestKind: rk,
-				ChildRequestKind:  childRK,
 			})
 		if err != nil {
 			writeInternalError(l, w, err)
@@ -136,7 +145,7 @@ func handleGetChildrenMetadata(l *slog.Logger, q *dbgen.Queries) http.HandlerFun
 	}
 }
 
-func handlePostMetricMetadata(l *slog.Logger, q *dbgen.Queries) http.HandlerFunc {
+func handlePostMetricMetadata(l *slog.Logger, q *dbgen.Queries, wh *webhooker) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		var data api.MetricMetadataPayload
 		err := stools.DecodeJSONBody(r, &data)
@@ -155,6 +164,11 @@ func handlePostMetricMetadata(l *slog.Logger, q *dbgen.Queries) http.HandlerFunc
 			writeInternalError(l, w, err)
 			return
 		}
+		wh.emit(r.Context(), api.WebhookEvent{
+			Type:        api.WebhookEventMetadataRefreshed,
+			RequestKind: data.RequestKind,
+			ID:          data.ID,
+		})
 		writeOK(w)
 	}
 }
@@ -1,14 +1,90 @@
 package server
 
 import (
+	"context"
+	"encoding/json"
+	"fmt"
 	"log/slog"
 	"net/http"
+	"time"
 
+	"github.com/brojonat/kaggo/server/alerts"
+	"github.com/brojonat/kaggo/server/api"
 	"github.com/brojonat/kaggo/server/db/dbgen"
+	kt "github.com/brojonat/kaggo/temporal/v19700101"
+	"github.com/jackc/pgx/v5/pgtype"
 )
 
-func handleMetricsPost(l *slog.Logger, q *dbgen.Queries) http.HandlerFunc {
-	return func(w http.ResponseWriter, r *http.Request) {
+// insertMetricSamples writes one sample per entry in ms for the supplied
+// (kind, id), observed at ts. This is the single write path for all metrics.
+// If key is set, samples already written with the same key are skipped. Once
+// the samples are written, any alert rules on the new ones are evaluated.
+func insertMetricSamples(ctx context.Context, q *dbgen.Queries, al *alerts.Alerter, rk, id string, ms map[string]float64, ts time.Time, key string) error {
+	inserted := map[string]float64{}
+	for m, v := range ms {
+		n, err := q.InsertMetricSample(ctx, dbgen.InsertMetricSampleParams{
+			RequestKind:    rk,
+			ID:             id,
+			Metric:         m,
+			Ts:             pgtype.Timestamptz{Time: ts, Valid: true},
+			Value:          v,
+			IdempotencyKey: pgtype.Text{String: key, Valid: key != ""},
+		})
+		if err != nil {
+			return fmt.Errorf("error inserting %s.%s for %s: %w", rk, m, id, err)
+		}
+		if n > 0 {
+			inserted[m] = v
+		}
+	}
+	if al != nil {
+		al.Evaluate(ctx, rk, id, inserted, ts)
+	}
+	return nil
+}
 
+// Generic metric upload handler. Any registered RequestKind can upload any
+// metric here without needing a dedicated endpoint or table.
+func handleMetricsPost(l *slog.Logger, q *dbgen.Queries, al *alerts.Alerter) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var p api.MetricSamplesPayload
+		defer r.Body.Close()
+		err := json.NewDecoder(r.Body).Decode(&p)
+		if err != nil {
+			writeBadRequestError(w, err)
+			return
+		}
+		if _, err = kt.GetRequestKindSpec(p.RequestKind); err != nil {
+			writeBadRequestError(w, err)
+			return
+		}
+		if p.ID == "" {
+			writeBadRequestError(w, fmt.Errorf("must supply id"))
+			return
+		}
+		if len(p.Metrics) == 0 {
+			writeBadRequestError(w, fmt.Errorf("must supply metrics"))
+			return
+		}
+		for m := range p.Metrics {
+			if err = api.ValidateMetricName(m); err != nil {
+				writeBadRequestError(w, err)
+				return
+			}
+		}
+		if p.IdempotencyKey != "" && p.Ts == nil {
+			writeBadRequestError(w, fmt.Errorf("must supply ts with idempotency_key"))
+			return
+		}
+		ts, err := api.SampleTime(p.Ts)
+		if err != nil {
+			writeBadRequestError(w, err)
+			return
+		}
+		if err = insertMetricSamples(r.Context(), q, al, p.RequestKind, p.ID, p.Metrics, ts, p.IdempotencyKey); err != nil {
+			writeInternalError(l, w, err)
+			return
+		}
+		writeOK(w)
 	}
 }
@@ -0,0 +1,180 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math/rand"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+	_ "time/tzdata"
+
+	"github.com/brojonat/kaggo/server/api"
+	"github.com/brojonat/kaggo/server/db/dbgen"
+	"github.com/brojonat/server-tools/stools"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// The quota ledger. Some providers (i.e., YouTube) don't rate limit so much as
+// give each API key a budget of units per day, with each call costing some
+// number of units. Workers charge every call to the ledger (quota_ledger),
+// which picks the key in the pool with the most budget left. When the spend
+// so far projects past the pool's budget by the end of the period, requests
+// for IDs that haven't been granted to any user are thinned out so that the
+// granted ones can keep going; once every key is spent, everything is
+// skipped until the reset.
+
+// quotaLocation is where quota periods start and end; YouTube resets its
+// quota at midnight Pacific time.
+var quotaLocation = func() *time.Location {
+	loc, err := time.LoadLocation("America/Los_Angeles")
+	if err != nil {
+		panic(err)
+	}
+	return loc
+}()
+
+// quotaMinElapsed keeps the spend rate from being extrapolated from the first
+// few requests of the period.
+const quotaMinElapsed = time.Hour
+
+// quotaPeriod returns the start and end of the quota period containing t.
+func quotaPeriod(t time.Time) (time.Time, time.Time) {
+	t = t.In(quotaLocation)
+	start := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, quotaLocation)
+	return start, start.AddDate(0, 0, 1)
+}
+
+// projectQuotaSpend extrapolates the spend so far over the rest of the period.
+func projectQuotaSpend(spent int64, now, start, end time.Time) float64 {
+	elapsed := max(now.Sub(start), quotaMinElapsed)
+	rate := float64(spent) / elapsed.Seconds()
+	return float64(spent) + rate*end.Sub(now).Seconds()
+}
+
+// Returns the spend per key over the past dur (e.g., 7d); defaults to the
+// current period.
+func handleGetQuotaLedger(l *slog.Logger, q *dbgen.Queries) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		start, _ := quotaPeriod(time.Now())
+		if s := r.URL.Query().Get("dur"); s != "" {
+			d, err := parseDuration(s)
+			if err != nil {
+				writeBadRequestError(w, fmt.Errorf("could not parse duration: %w", err))
+				return
+			}
+			start, _ = quotaPeriod(time.Now().Add(-d))
+		}
+		res, err := q.GetQuotaLedger(r.Context(), pgtype.Date{Time: start, Valid: true})
+		if err != nil {
+			writeInternalError(l, w, err)
+			return
+		}
+		if len(res) == 0 {
+			writeEmptyResultError(w)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(res)
+	}
+}
+
+// Called by workers before each call to a provider with a unit quota.
+func handleChargeQuota(l *slog.Logger, q *dbgen.Queries) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var body api.QuotaChargePayload
+		err := stools.DecodeJSONBody(r, &body)
+		if err != nil {
+			writeBadRequestError(w, err)
+			return
+		}
+		if len(body.Keys) == 0 {
+			writeBadRequestError(w, fmt.Errorf("must supply key(s)"))
+			return
+		}
+		if body.Cost <= 0 || body.Budget <= 0 || body.Cost > body.Budget {
+			writeBadRequestError(w, fmt.Errorf("cost and budget must be positive and cost must not exceed budget"))
+			return
+		}
+
+		now := time.Now()
+		start, end := quotaPeriod(now)
+		period := pgtype.Date{Time: start, Valid: true}
+		rows, err := q.GetQuotaSpend(r.Context(), dbgen.GetQuotaSpendParams{Period: period, QuotaKeys: body.Keys})
+		if err != nil {
+			writeInternalError(l, w, err)
+			return
+		}
+		res := api.QuotaChargeResponse{Remaining: map[string]int64{}}
+		var spent int64
+		for _, k := range body.Keys {
+			res.Remaining[k] = body.Budget
+		}
+		for _, row := range rows {
+			res.Remaining[row.QuotaKey] = body.Budget - row.Spent
+			spent += row.Spent
+		}
+
+		// thin out the low priority requests if we're on track to run out
+		budget := body.Budget * int64(len(body.Keys))
+		projected := projectQuotaSpend(spent, now, start, end)
+		if projected > float64(budget) && len(body.IDs) > 0 {
+			ids := []string{}
+			for _, id := range body.IDs {
+				ids = append(ids, strings.ToLower(id))
+			}
+			n, err := q.CountMetricGrants(r.Context(), dbgen.CountMetricGrantsParams{RequestKind: body.RequestKind, Ids: ids})
+			if err != nil {
+				writeInternalError(l, w, err)
+				return
+			}
+			keep := float64(budget-spent) / (projected - float64(spent))
+			if n == 0 && rand.Float64() >= keep {
+				res.Reason = fmt.Sprintf(
+					"projected spend of %.0f units exceeds the budget of %d; polling ungranted IDs at %.0f%%",
+					projected, budget, 100*max(0, keep))
+				w.WriteHeader(http.StatusOK)
+				json.NewEncoder(w).Encode(res)
+				return
+			}
+		}
+
+		// charge the key with the most left; if another worker beat us to it,
+		// move on to the next one
+		keys := slices.Clone(body.Keys)
+		slices.SortStableFunc(keys, func(a, b string) int {
+			return int(res.Remaining[b] - res.Remaining[a])
+		})
+		for _, k := range keys {
+			if res.Remaining[k] < body.Cost {
+				break
+			}
+			s, err := q.ChargeQuota(r.Context(), dbgen.ChargeQuotaParams{
+				QuotaKey: k,
+				Period:   period,
+				Cost:     body.Cost,
+				Budget:   body.Budget,
+			})
+			if err != nil {
+				if errors.Is(err, pgx.ErrNoRows) {
+					continue
+				}
+				writeInternalError(l, w, err)
+				return
+			}
+			res.Allowed = true
+			res.Key = k
+			res.Remaining[k] = body.Budget - s
+			break
+		}
+		if !res.Allowed {
+			res.Reason = fmt.Sprintf("quota exhausted for all %d key(s) until %s", len(body.Keys), end.Format(time.RFC3339))
+		}
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(res)
+	}
+}
@@ -0,0 +1,198 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math"
+	"net/http"
+	"time"
+
+	"github.com/brojonat/kaggo/server/api"
+	"github.com/brojonat/kaggo/server/db/dbgen"
+	"github.com/brojonat/server-tools/stools"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// The rate limit governor. Workers share provider credentials, so the
+// provider's rate limit is shared too. Each credential gets a token bucket
+// (rate_limit_buckets) that workers draw from before sending a request and
+// refill from the rate limit headers on the responses. When a bucket runs
+// low, requests are delayed until a token is expected to be available, or
+// skipped outright if that's too far off, rather than sent only to get a 429.
+// Buckets we haven't heard about yet don't limit anything.
+
+// rateLimitReserve is the fraction of each bucket that isn't handed out. The
+// headers lag behind the requests in flight, so this leaves some slack for
+// requests the provider has counted and we haven't.
+const rateLimitReserve = 0.05
+
+// bucketTokens returns the tokens available at now. Providers restore the
+// full budget at the reset, which we carry any debt past; in between (or
+// once we're past a reset we haven't heard about) the bucket refills at its
+// steady rate.
+func bucketTokens(b dbgen.RateLimitBucket, now time.Time) float64 {
+	if b.TsUpdated.Time.Before(b.TsReset.Time) && !now.Before(b.TsReset.Time) {
+		return b.Capacity + min(0, b.Tokens)
+	}
+	dt := max(0, now.Sub(b.TsUpdated.Time).Seconds())
+	return min(b.Capacity, b.Tokens+dt*b.RefillRate)
+}
+
+// acquireToken takes a token from the bucket. If none is available, it
+// returns how long until one will be; the token is only taken (i.e., the
+// bucket goes into debt) if that's within maxWait. The returned tokens are
+// what's left in the bucket.
+func acquireToken(b dbgen.RateLimitBucket, now time.Time, maxWait time.Duration) (tokens float64, wait time.Duration, ok bool) {
+	tokens = bucketTokens(b, now)
+	floor := 1 + rateLimitReserve*b.Capacity
+	if tokens >= floor {
+		return tokens - 1, 0, true
+	}
+
+	secs := math.Inf(1)
+	if b.RefillRate > 0 {
+		secs = (floor - tokens) / b.RefillRate
+	}
+	if b.TsUpdated.Time.Before(b.TsReset.Time) && now.Before(b.TsReset.Time) {
+		secs = min(secs, b.TsReset.Time.Sub(now).Seconds())
+	}
+	if math.IsInf(secs, 1) {
+		return tokens, time.Duration(math.MaxInt64), false
+	}
+	wait = time.Duration(secs * float64(time.Second)).Round(time.Millisecond)
+	if wait > maxWait {
+		return tokens, wait, false
+	}
+	return tokens - 1, wait, true
+}
+
+// Returns the state of every bucket, including how often each has delayed or
+// skipped requests and why.
+func handleGetRateLimitBuckets(l *slog.Logger, q *dbgen.Queries) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		res, err := q.GetRateLimitBuckets(r.Context())
+		if err != nil {
+			writeInternalError(l, w, err)
+			return
+		}
+		if len(res) == 0 {
+			writeEmptyResultError(w)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(res)
+	}
+}
+
+// Called by workers before sending a request to a rate limited provider.
+func handleAcquireRateLimitToken(l *slog.Logger, p *pgxpool.Pool, q *dbgen.Queries) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var body api.RateLimitAcquirePayload
+		err := stools.DecodeJSONBody(r, &body)
+		if err != nil {
+			writeBadRequestError(w, err)
+			return
+		}
+		if body.Key == "" {
+			writeBadRequestError(w, fmt.Errorf("must supply key"))
+			return
+		}
+		maxWait := time.Duration(body.MaxWaitSeconds * float64(time.Second))
+
+		tx, err := p.Begin(r.Context())
+		if err != nil {
+			writeInternalError(l, w, err)
+			return
+		}
+		defer tx.Rollback(r.Context())
+		qtx := q.WithTx(tx)
+
+		b, err := qtx.GetRateLimitBucketForUpdate(r.Context(), body.Key)
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				w.WriteHeader(http.StatusOK)
+				json.NewEncoder(w).Encode(api.RateLimitAcquireResponse{Allowed: true})
+				return
+			}
+			writeInternalError(l, w, err)
+			return
+		}
+
+		now := time.Now()
+		tokens, wait, ok := acquireToken(b, now, maxWait)
+		res := api.RateLimitAcquireResponse{Allowed: ok}
+		if ok {
+			err = qtx.SetRateLimitBucketTokens(r.Context(), dbgen.SetRateLimitBucketTokensParams{
+				Tokens:    tokens,
+				TsUpdated: pgtype.Timestamptz{Time: now, Valid: true},
+				BucketKey: body.Key,
+			})
+			if err != nil {
+				writeInternalError(l, w, err)
+				return
+			}
+		}
+		if wait > 0 {
+			params := dbgen.RecordRateLimitBucketLimitedParams{BucketKey: body.Key}
+			if ok {
+				res.WaitSeconds = wait.Seconds()
+				res.Reason = fmt.Sprintf("%s: delayed %s; %.0f of %.0f requests remaining", body.Key, wait, max(0, tokens+1), b.Capacity)
+				params.Delayed = 1
+			} else {
+				res.Reason = fmt.Sprintf("%s: skipped; %.0f of %.0f requests remaining, next available in %s", body.Key, max(0, tokens), b.Capacity, wait)
+				params.Skipped = 1
+			}
+			if body.RequestKind != "" {
+				res.Reason = fmt.Sprintf("%s (%s)", res.Reason, body.RequestKind)
+			}
+			params.LastReason = res.Reason
+			if err = qtx.RecordRateLimitBucketLimited(r.Context(), params); err != nil {
+				writeInternalError(l, w, err)
+				return
+			}
+		}
+		if err = tx.Commit(r.Context()); err != nil {
+			writeInternalError(l, w, err)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(res)
+	}
+}
+
+// Called by workers with the rate limit headers from each provider response.
+func handleObserveRateLimit(l *slog.Logger, q *dbgen.Queries) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var body api.RateLimitObservation
+		err := stools.DecodeJSONBody(r, &body)
+		if err != nil {
+			writeBadRequestError(w, err)
+			return
+		}
+		if body.Key == "" {
+			writeBadRequestError(w, fmt.Errorf("must supply key"))
+			return
+		}
+		if body.Limit <= 0 || body.WindowSeconds <= 0 {
+			writeBadRequestError(w, fmt.Errorf("limit and window_seconds must be positive"))
+			return
+		}
+		err = q.UpsertRateLimitBucket(r.Context(), dbgen.UpsertRateLimitBucketParams{
+			BucketKey:  body.Key,
+			Capacity:   body.Limit,
+			Tokens:     max(0, min(body.Limit, body.Remaining)),
+			RefillRate: body.Limit / body.WindowSeconds,
+			TsUpdated:  pgtype.Timestamptz{Time: time.Now(), Valid: true},
+			TsReset:    pgtype.Timestamptz{Time: body.Reset, Valid: true},
+		})
+		if err != nil {
+			writeInternalError(l, w, err)
+			return
+		}
+		writeOK(w)
+	}
+}
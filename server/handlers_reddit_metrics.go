@@ -5,93 +5,53 @@ import (
 	"fmt"
 	"log/slog"
 	"net/http"
-	"time"
 
+	"github.com/brojonat/kaggo/server/alerts"
 	"github.com/brojonat/kaggo/server/api"
 	"github.com/brojonat/kaggo/server/db/dbgen"
+	kt "github.com/brojonat/kaggo/temporal/v19700101"
 	"github.com/prometheus/client_golang/prometheus"
 )
 
-func handleRedditPostMetricsGet(l *slog.Logger, q *dbgen.Queries) http.HandlerFunc {
+func handleRedditPostMetricsPost(l *slog.Logger, q *dbgen.Queries, al *alerts.Alerter, pms map[string]prometheus.Collector) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		ids := r.URL.Query()["id"]
-		if len(ids) == 0 {
-			writeBadRequestError(w, fmt.Errorf("must supply id"))
-			return
-		}
-		res, err := getRedditPostTimeSeries(r.Context(), l, q, ids, time.Time{}, time.Now())
+		// parse
+		var p api.RedditPostMetricPayload
+		defer r.Body.Close()
+		err := json.NewDecoder(r.Body).Decode(&p)
 		if err != nil {
-			writeInternalError(l, w, err)
+			writeBadRequestError(w, err)
 			return
 		}
-		if res == nil {
-			writeEmptyResultError(w)
+		if p.ID == "" {
+			writeBadRequestError(w, fmt.Errorf("must supply id"))
 			return
 		}
-		w.WriteHeader(http.StatusOK)
-		json.NewEncoder(w).Encode(res)
-	}
-}
-
-func handleRedditPostMetricsPost(l *slog.Logger, q *dbgen.Queries, pms map[string]prometheus.Collector) http.HandlerFunc {
-	return func(w http.ResponseWriter, r *http.Request) {
-		// parse
-		var p api.RedditPostMetricPayload
-		defer r.Body.Close()
-		err := json.NewDecoder(r.Body).Decode(&p)
+		ts, err := api.SampleTime(p.Ts)
 		if err != nil {
 			writeBadRequestError(w, err)
 			return
 		}
 
 		// upload metrics
+		ms := map[string]float64{}
 		if p.SetScore {
-			err = q.InsertRedditPostScore(
-				r.Context(),
-				dbgen.InsertRedditPostScoreParams{
-					ID: p.ID, Score: int32(p.Score)})
-			if err != nil {
-				writeInternalError(l, w, err)
-				return
-			}
+			ms["score"] = float64(p.Score)
 		}
 		if p.SetRatio {
-			err = q.InsertRedditPostRatio(
-				r.Context(),
-				dbgen.InsertRedditPostRatioParams{
-					ID: p.ID, Ratio: p.Ratio})
-			if err != nil {
-				writeInternalError(l, w, err)
-				return
-			}
+			ms["ratio"] = float64(p.Ratio)
 		}
-
-		writeOK(w)
-	}
-}
-
-func handleRedditCommentMetricsGet(l *slog.Logger, q *dbgen.Queries) http.HandlerFunc {
-	return func(w http.ResponseWriter, r *http.Request) {
-		ids := r.URL.Query()["id"]
-		if len(ids) == 0 {
-			writeBadRequestError(w, fmt.Errorf("must supply id"))
-			return
-		}
-		res, err := getRedditCommentTimeSeries(r.Context(), l, q, ids, time.Time{}, time.Now())
+		err = insertMetricSamples(r.Context(), q, al, kt.RequestKindRedditPost, p.ID, ms, ts, "")
 		if err != nil {
 			writeInternalError(l, w, err)
 			return
 		}
-		if res == nil {
-			writeEmptyResultError(w)
-			return
-		}
-		w.WriteHeader(http.StatusOK)
-		json.NewEncoder(w).Encode(res)
+
+		writeOK(w)
 	}
 }
 
-func handleRedditCommentMetricsPost(l *slog.Logger, q *dbgen.Queries, pms map[string]prometheus.Collector) http.HandlerFunc {
+func handleRedditCommentMetricsPost(l *slog.Logger, q *dbgen.Queries, al *alerts.Alerter, pms map[string]prometheus.Collector) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		// parse
 		var p api.RedditCommentMetricPayload
@@ -101,55 +61,35 @@ func handleRedditCommentMetricsPost(l *slog.Logger, q *dbgen.Queries, pms map[st
 			writeBadRequestError(w, err)
 			return
 		}
+		if p.ID == "" {
+			writeBadRequestError(w, fmt.Errorf("must supply id"))
+			return
+		}
+		ts, err := api.SampleTime(p.Ts)
+		if err != nil {
+			writeBadRequestError(w, err)
+			return
+		}
 
 		// upload metrics
+		ms := map[string]float64{}
 		if p.SetScore {
-			err = q.InsertRedditCommentScore(
-				r.Context(),
-				dbgen.InsertRedditCommentScoreParams{
-					ID: p.ID, Score: int32(p.Score)})
-			if err != nil {
-				writeInternalError(l, w, err)
-				return
-			}
+			ms["score"] = float64(p.Score)
 		}
 		if p.SetControversiality {
-			err = q.InsertRedditCommentControversiality(
-				r.Context(),
-				dbgen.InsertRedditCommentControversialityParams{
-					ID: p.ID, Controversiality: p.Controversiality})
-			if err != nil {
-				writeInternalError(l, w, err)
-				return
-			}
-		}
-
-		writeOK(w)
-	}
-}
-
-func handleRedditSubredditMetricsGet(l *slog.Logger, q *dbgen.Queries) http.HandlerFunc {
-	return func(w http.ResponseWriter, r *http.Request) {
-		ids := r.URL.Query()["id"]
-		if len(ids) == 0 {
-			writeBadRequestError(w, fmt.Errorf("must supply id"))
-			return
+			ms["controversiality"] = float64(p.Controversiality)
 		}
-		res, err := getRedditSubredditTimeSeries(r.Context(), l, q, ids, time.Time{}, time.Now())
+		err = insertMetricSamples(r.Context(), q, al, kt.RequestKindRedditComment, p.ID, ms, ts, "")
 		if err != nil {
 			writeInternalError(l, w, err)
 			return
 		}
-		if res == nil {
-			writeEmptyResultError(w)
-			return
-		}
-		w.WriteHeader(http.StatusOK)
-		json.NewEncoder(w).Encode(res)
+
+		writeOK(w)
 	}
 }
 
-func handleRedditSubredditMetricsPost(l *slog.Logger, q *dbgen.Queries, pms map[string]prometheus.Collector) http.HandlerFunc {
+func handleRedditSubredditMetricsPost(l *slog.Logger, q *dbgen.Queries, al *alerts.Alerter, pms map[string]prometheus.Collector) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		// parse
 		var p api.RedditSubredditMetricPayload
@@ -159,55 +99,35 @@ func handleRedditSubredditMetricsPost(l *slog.Logger, q *dbgen.Queries, pms map[
 			writeBadRequestError(w, err)
 			return
 		}
+		if p.ID == "" {
+			writeBadRequestError(w, fmt.Errorf("must supply id"))
+			return
+		}
+		ts, err := api.SampleTime(p.Ts)
+		if err != nil {
+			writeBadRequestError(w, err)
+			return
+		}
 
 		// upload metrics
+		ms := map[string]float64{}
 		if p.SetSubscribers {
-			err = q.InsertRedditSubredditSubscribers(
-				r.Context(),
-				dbgen.InsertRedditSubredditSubscribersParams{
-					ID: p.ID, Subscribers: int32(p.Subscribers)})
-			if err != nil {
-				writeInternalError(l, w, err)
-				return
-			}
+			ms["subscribers"] = float64(p.Subscribers)
 		}
 		if p.SetActiveUserCount {
-			err = q.InsertRedditSubredditActiveUserCount(
-				r.Context(),
-				dbgen.InsertRedditSubredditActiveUserCountParams{
-					ID: p.ID, ActiveUserCount: int32(p.ActiveUserCount)})
-			if err != nil {
-				writeInternalError(l, w, err)
-				return
-			}
+			ms["active-user-count"] = float64(p.ActiveUserCount)
 		}
-
-		writeOK(w)
-	}
-}
-
-func handleRedditUserMetricsGet(l *slog.Logger, q *dbgen.Queries) http.HandlerFunc {
-	return func(w http.ResponseWriter, r *http.Request) {
-		ids := r.URL.Query()["id"]
-		if len(ids) == 0 {
-			writeBadRequestError(w, fmt.Errorf("must supply id"))
-			return
-		}
-		res, err := getRedditUserTimeSeries(r.Context(), l, q, ids, time.Time{}, time.Now())
+		err = insertMetricSamples(r.Context(), q, al, kt.RequestKindRedditSubreddit, p.ID, ms, ts, "")
 		if err != nil {
 			writeInternalError(l, w, err)
 			return
 		}
-		if res == nil {
-			writeEmptyResultError(w)
-			return
-		}
-		w.WriteHeader(http.StatusOK)
-		json.NewEncoder(w).Encode(res)
+
+		writeOK(w)
 	}
 }
 
-func handleRedditUserMetricsPost(l *slog.Logger, q *dbgen.Queries, pms map[string]prometheus.Collector) http.HandlerFunc {
+func handleRedditUserMetricsPost(l *slog.Logger, q *dbgen.Queries, al *alerts.Alerter, pms map[string]prometheus.Collector) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		// parse
 		var p api.RedditUserMetricPayload
@@ -217,57 +137,37 @@ func handleRedditUserMetricsPost(l *slog.Logger, q *dbgen.Queries, pms map[strin
 			writeBadRequestError(w, err)
 			return
 		}
+		if p.ID == "" {
+			writeBadRequestError(w, fmt.Errorf("must supply id"))
+			return
+		}
+		ts, err := api.SampleTime(p.Ts)
+		if err != nil {
+			writeBadRequestError(w, err)
+			return
+		}
 
 		// upload metrics
+		ms := map[string]float64{}
 		if p.SetAwardeeKarma {
-			err = q.InsertRedditUserAwardeeKarma(
-				r.Context(),
-				dbgen.InsertRedditUserAwardeeKarmaParams{
-					ID: p.ID, Karma: int32(p.AwardeeKarma)})
-			if err != nil {
-				writeInternalError(l, w, err)
-				return
-			}
+			ms["awardee-karma"] = float64(p.AwardeeKarma)
 		}
 		if p.SetAwarderKarma {
-			err = q.InsertRedditUserAwarderKarma(
-				r.Context(),
-				dbgen.InsertRedditUserAwarderKarmaParams{
-					ID: p.ID, Karma: int32(p.AwarderKarma)})
-			if err != nil {
-				writeInternalError(l, w, err)
-				return
-			}
+			ms["awarder-karma"] = float64(p.AwarderKarma)
 		}
 		if p.SetCommentKarma {
-			err = q.InsertRedditUserCommentKarma(
-				r.Context(),
-				dbgen.InsertRedditUserCommentKarmaParams{
-					ID: p.ID, Karma: int32(p.CommentKarma)})
-			if err != nil {
-				writeInternalError(l, w, err)
-				return
-			}
+			ms["comment-karma"] = float64(p.CommentKarma)
 		}
 		if p.SetLinkKarma {
-			err = q.InsertRedditUserLinkKarma(
-				r.Context(),
-				dbgen.InsertRedditUserLinkKarmaParams{
-					ID: p.ID, Karma: int32(p.LinkKarma)})
-			if err != nil {
-				writeInternalError(l, w, err)
-				return
-			}
+			ms["link-karma"] = float64(p.LinkKarma)
 		}
 		if p.SetTotalKarma {
-			err = q.InsertRedditUserTotalKarma(
-				r.Context(),
-				dbgen.InsertRedditUserTotalKarmaParams{
-					ID: p.ID, Karma: int32(p.TotalKarma)})
-			if err != nil {
-				writeInternalError(l, w, err)
-				return
-			}
+			ms["total-karma"] = float64(p.TotalKarma)
+		}
+		err = insertMetricSamples(r.Context(), q, al, kt.RequestKindRedditUser, p.ID, ms, ts, "")
+		if err != nil {
+			writeInternalError(l, w, err)
+			return
 		}
 
 		writeOK(w)
@@ -1,6 +1,7 @@
 package server
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -8,51 +9,100 @@ import (
 	"log/slog"
 	"net/http"
 	"os"
+	"slices"
 	"strconv"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/brojonat/kaggo/server/api"
 	"github.com/brojonat/kaggo/server/db/dbgen"
+	"github.com/brojonat/kaggo/server/db/jsonb"
+	"github.com/brojonat/kaggo/server/schedspec"
 	kt "github.com/brojonat/kaggo/temporal/v19700101"
 	"github.com/brojonat/server-tools/stools"
+	"github.com/jackc/pgx/v5/pgtype"
+	commonpb "go.temporal.io/api/common/v1"
 	"go.temporal.io/api/enums/v1"
+	"go.temporal.io/api/serviceerror"
+	"go.temporal.io/api/workflowservice/v1"
 	"go.temporal.io/sdk/client"
 	"go.temporal.io/sdk/temporal"
 )
 
+// Lists schedules a page at a time (see parsePage). Filter with request_kind,
+// id, owner_email and tier. The Temporal API this is built against can't query
+// schedules by search attribute, so the filters are applied as schedules are
+// listed. To still return full pages, this keeps listing until the page is
+// full or there are no more schedules, asking Temporal for no more schedules
+// than there's room left for so none are skipped; the next page token picks up
+// after the last schedule listed. Sparse filters can take several round trips.
 func handleGetSchedule(l *slog.Logger, tc client.Client) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		rk := r.URL.Query().Get("request_kind")
-		ss, err := tc.ScheduleClient().List(r.Context(), client.ScheduleListOptions{})
+		f := searchAttributesFilter(r)
+		size, token, err := parsePage(r)
 		if err != nil {
 			writeBadRequestError(w, err)
 			return
 		}
-		res := []*client.ScheduleListEntry{}
+		res := api.ScheduleListResponse{Schedules: []api.ScheduleListEntry{}}
 		for {
-			if !ss.HasNext() {
-				break
-			}
-			s, err := ss.Next()
+			ls, err := tc.WorkflowService().ListSchedules(r.Context(), &workflowservice.ListSchedulesRequest{
+				Namespace:       client.DefaultNamespace,
+				MaximumPageSize: size - int32(len(res.Schedules)),
+				NextPageToken:   token,
+			})
 			if err != nil {
-				break
+				writeBadRequestError(w, err)
+				return
+			}
+			for _, e := range ls.Schedules {
+				a := scheduleSearchAttributes(e.ScheduleId, e.SearchAttributes)
+				if !a.Matches(f) {
+					continue
+				}
+				spec := schedspec.FromProto(e.Info.GetSpec())
+				res.Schedules = append(res.Schedules, api.ScheduleListEntry{
+					ID:          e.ScheduleId,
+					RequestKind: a.RequestKind,
+					EntityID:    a.EntityID,
+					OwnerEmail:  a.OwnerEmail,
+					Tier:        a.Tier,
+					Spec:        &spec,
+					Paused:      e.Info.GetPaused(),
+					Note:        e.Info.GetNotes(),
+				})
 			}
-			if rk == "" || strings.HasPrefix(s.ID, rk) {
-				res = append(res, s)
+			token = ls.NextPageToken
+			if len(token) == 0 || len(res.Schedules) >= int(size) {
+				break
 			}
 		}
+		res.NextPageToken = encodePageToken(token)
 		w.WriteHeader(http.StatusOK)
 		json.NewEncoder(w).Encode(res)
 	}
 }
 
+// scheduleSearchAttributes returns the schedule's search attributes. Schedules
+// created before they were added don't have any; for those, the kind and
+// entity ID are recovered from the schedule ID ("<request_kind> <id> <hash>").
+func scheduleSearchAttributes(sid string, sa *commonpb.SearchAttributes) kt.SearchAttributes {
+	a := kt.DecodeSearchAttributes(sa)
+	if a.RequestKind == "" {
+		if parts := strings.SplitN(sid, " ", 3); len(parts) == 3 {
+			a.RequestKind, a.EntityID = parts[0], parts[1]
+		}
+	}
+	return a
+}
+
 // create a schedule to query an external api based on the user submitted data
-func handleCreateSchedule(l *slog.Logger, q *dbgen.Queries, tc client.Client) http.HandlerFunc {
+func handleCreateSchedule(l *slog.Logger, q *dbgen.Queries, tc client.Client, wh *webhooker) http.HandlerFunc {
 	seen := sync.Map{}
 	return func(w http.ResponseWriter, r *http.Request) {
 
-		claims, ok := r.Context().Value(ctxKeyJWT).(*authJWTClaims)
+		c, ok := getCaller(r)
 		if !ok {
 			writeInternalError(l, w, fmt.Errorf("could not extract user email"))
 			return
@@ -72,6 +122,22 @@ func handleCreateSchedule(l *slog.Logger, q *dbgen.Queries, tc client.Client) ht
 			return
 		}
 
+		// resolve the preset or validate the supplied spec
+		sched := body.Schedule
+		if body.Preset != "" {
+			if !isEmptyScheduleSpec(sched) {
+				writeBadRequestError(w, fmt.Errorf("must supply only one of schedule_spec or preset"))
+				return
+			}
+			sched, err = kt.GetSchedulePreset(body.RequestKind, body.Preset)
+		} else {
+			err = kt.ValidateScheduleSpec(body.RequestKind, sched)
+		}
+		if err != nil {
+			writeBadRequestError(w, err)
+			return
+		}
+
 		// We have a local cache to deal with duplicate schedule creation requests. This
 		// doesn't have to be perfect, but it'll get us 90% of the way there.
 		// The service will get restarted frequently enough that this shouldn't
@@ -120,10 +186,21 @@ func handleCreateSchedule(l *slog.Logger, q *dbgen.Queries, tc client.Client) ht
 			writeInternalError(l, w, err)
 			return
 		}
+		tier := body.Preset
+		if tier == "" {
+			tier = kt.ScheduleTierCustom
+		}
+		sa := kt.SearchAttributes{
+			RequestKind: body.RequestKind,
+			EntityID:    body.ID,
+			OwnerEmail:  c.Email,
+			Tier:        tier,
+		}.Map()
 		workflowOptions := client.StartWorkflowOptions{
-			ID:          id,
-			TaskQueue:   os.Getenv("TEMPORAL_TASK_QUEUE"),
-			RetryPolicy: &temporal.RetryPolicy{MaximumAttempts: 3},
+			ID:               id,
+			TaskQueue:        os.Getenv("TEMPORAL_TASK_QUEUE"),
+			RetryPolicy:      &temporal.RetryPolicy{MaximumAttempts: 3},
+			SearchAttributes: sa,
 		}
 
 		// Optionally skip the metadata query. Some clients don't need to run
@@ -154,11 +231,6 @@ func handleCreateSchedule(l *slog.Logger, q *dbgen.Queries, tc client.Client) ht
 			}
 		}
 
-		// probably should validate this...but we're the only ones authed for
-		// this API and at present we're only using the same fixed schedule, so
-		// implement validation later if it's actually needed.
-		sched := body.Schedule
-
 		// prepare the request to pass to the polling workflow
 		_, serialReq, id, err = makeExternalRequest(q, body.RequestKind, body.ID, false)
 		if err != nil {
@@ -175,15 +247,18 @@ func handleCreateSchedule(l *slog.Logger, q *dbgen.Queries, tc client.Client) ht
 		_, err = tc.ScheduleClient().Create(
 			r.Context(),
 			client.ScheduleOptions{
-				ID:   id,
-				Spec: sched,
+				ID:               id,
+				Spec:             sched,
+				Memo:             map[string]interface{}{kt.MemoFixedCadence: body.FixedCadence},
+				SearchAttributes: sa,
 				Action: &client.ScheduleWorkflowAction{
 					ID:        id,
 					TaskQueue: os.Getenv("TEMPORAL_TASK_QUEUE"),
 					Workflow:  kt.DoPollingRequestWF,
 					Args: []interface{}{kt.DoPollingRequestWFRequest{
 						RequestKind: body.RequestKind, Serial: serialReq}},
-					RetryPolicy: &temporal.RetryPolicy{MaximumAttempts: 1},
+					RetryPolicy:      &temporal.RetryPolicy{MaximumAttempts: 1},
+					SearchAttributes: sa,
 				},
 			})
 		if err != nil {
@@ -207,7 +282,7 @@ func handleCreateSchedule(l *slog.Logger, q *dbgen.Queries, tc client.Client) ht
 			l.Error(
 				"unable to fetch metadata needed to grant metric",
 				"error", err.Error(),
-				"email", claims.Email,
+				"email", c.Email,
 				"request_kind", body.RequestKind,
 				"id", body.ID,
 			)
@@ -215,7 +290,7 @@ func handleCreateSchedule(l *slog.Logger, q *dbgen.Queries, tc client.Client) ht
 			// add the metric to the user; it's possible the user already has this
 			// metric granted, so check for that error
 			p := dbgen.GrantMetricToUserParams{
-				Email:       claims.Email,
+				Email:       c.Email,
 				RequestKind: body.RequestKind,
 				ID:          m.ID, // uses the "true" ID
 			}
@@ -224,28 +299,72 @@ func handleCreateSchedule(l *slog.Logger, q *dbgen.Queries, tc client.Client) ht
 				l.Error(
 					"unable to grant metric to user",
 					"error", err.Error(),
-					"email", claims.Email,
+					"email", c.Email,
 					"request_kind", body.RequestKind,
 					"id", body.ID,
 				)
 			}
 		}
+		// Let anyone watching know about the new schedule. This is done after
+		// granting the metric so the requesting user is notified too.
+		if body.ParentRequestKind != "" {
+			wh.emit(r.Context(), api.WebhookEvent{
+				Type:              api.WebhookEventContentDiscovered,
+				RequestKind:       body.RequestKind,
+				ID:                body.ID,
+				ParentRequestKind: body.ParentRequestKind,
+				ParentID:          body.ParentID,
+			})
+		}
+		wh.emit(r.Context(), api.WebhookEvent{
+			Type:              api.WebhookEventScheduleCreated,
+			RequestKind:       body.RequestKind,
+			ID:                body.ID,
+			ParentRequestKind: body.ParentRequestKind,
+			ParentID:          body.ParentID,
+			ScheduleID:        id,
+		})
+		wh.watchScheduleExpiry(r.Context(), id, sched.EndAt)
+
 		w.WriteHeader(http.StatusOK)
 		json.NewEncoder(w).Encode(api.DefaultJSONResponse{Message: "ok"})
 	}
 }
 
-func handleUpdateSchedule(l *slog.Logger, tc client.Client) http.HandlerFunc {
+// Applies an api.ScheduleUpdatePayload to a single schedule. The old query
+// parameter form (?action=cancel&schedule_id=...&note=...) is still accepted;
+// "cancel" pauses the schedule.
+func handleUpdateSchedule(l *slog.Logger, tc client.Client, wh *webhooker) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		action := r.URL.Query().Get("action")
-		if action != "cancel" {
-			writeBadRequestError(w, fmt.Errorf("unsupported action: %s", action))
+		var body api.ScheduleUpdatePayload
+		if action := r.URL.Query().Get("action"); action != "" {
+			if action == "cancel" {
+				action = api.ScheduleActionPause
+			}
+			body = api.ScheduleUpdatePayload{
+				Action:     action,
+				ScheduleID: r.URL.Query().Get("schedule_id"),
+				Note:       r.URL.Query().Get("note"),
+			}
+		} else if err := stools.DecodeJSONBody(r, &body); err != nil {
+			writeBadRequestError(w, err)
 			return
 		}
-		sid := r.URL.Query().Get("schedule_id")
-		note := r.URL.Query().Get("note")
-		err := tc.ScheduleClient().GetHandle(r.Context(), sid).Pause(r.Context(), client.SchedulePauseOptions{Note: note})
+		if body.ScheduleID == "" {
+			writeBadRequestError(w, fmt.Errorf("must supply schedule_id"))
+			return
+		}
+		if err := validateScheduleUpdate(body); err != nil {
+			writeBadRequestError(w, err)
+			return
+		}
+		err := applyScheduleUpdate(r.Context(), tc, wh, body.ScheduleID, body)
 		if err != nil {
+			var nf *serviceerror.NotFound
+			if errors.As(err, &nf) {
+				writeEmptyResultError(w)
+				return
+			}
 			writeBadRequestError(w, err)
 			return
 		}
@@ -253,6 +372,201 @@ func handleUpdateSchedule(l *slog.Logger, tc client.Client) http.HandlerFunc {
 	}
 }
 
+// Applies an api.ScheduleUpdatePayload to every schedule listed in
+// ScheduleIDs, or to every schedule of RequestKind (optionally only those for
+// IDs). Failures don't stop the rest of the schedules from being updated;
+// they're reported in the response.
+func handleBulkUpdateSchedule(l *slog.Logger, tc client.Client, wh *webhooker) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var body api.ScheduleUpdatePayload
+		if err := stools.DecodeJSONBody(r, &body); err != nil {
+			writeBadRequestError(w, err)
+			return
+		}
+		if len(body.ScheduleIDs) == 0 && body.RequestKind == "" {
+			writeBadRequestError(w, fmt.Errorf("must supply schedule_ids or request_kind"))
+			return
+		}
+		if err := validateScheduleUpdate(body); err != nil {
+			writeBadRequestError(w, err)
+			return
+		}
+
+		sids := body.ScheduleIDs
+		if len(sids) == 0 {
+			ss, err := tc.ScheduleClient().List(r.Context(), client.ScheduleListOptions{})
+			if err != nil {
+				writeInternalError(l, w, err)
+				return
+			}
+			for ss.HasNext() {
+				s, err := ss.Next()
+				if err != nil {
+					writeInternalError(l, w, err)
+					return
+				}
+				a := scheduleSearchAttributes(s.ID, s.SearchAttributes)
+				if a.RequestKind != body.RequestKind {
+					continue
+				}
+				if len(body.IDs) > 0 && !slices.ContainsFunc(body.IDs, func(id string) bool { return strings.EqualFold(id, a.EntityID) }) {
+					continue
+				}
+				sids = append(sids, s.ID)
+			}
+		}
+
+		res := api.ScheduleBulkUpdateResponse{Updated: []string{}, Failed: map[string]string{}}
+		for _, sid := range sids {
+			if err := applyScheduleUpdate(r.Context(), tc, wh, sid, body); err != nil {
+				res.Failed[sid] = err.Error()
+				continue
+			}
+			res.Updated = append(res.Updated, sid)
+		}
+		l.Info("bulk updated schedules", "action", body.Action, "updated", len(res.Updated), "failed", len(res.Failed))
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(res)
+	}
+}
+
+// validateScheduleUpdate checks everything about the update that doesn't
+// depend on the schedule it's applied to.
+func validateScheduleUpdate(p api.ScheduleUpdatePayload) error {
+	switch p.Action {
+	case api.ScheduleActionPause, api.ScheduleActionUnpause, api.ScheduleActionSetNote:
+	case api.ScheduleActionUpdateSpec:
+		if (p.Schedule == nil) == (p.Preset == "") {
+			return fmt.Errorf("must supply exactly one of schedule_spec or preset")
+		}
+	case api.ScheduleActionExtend:
+		if (p.EndAt == nil) == (p.ExtendBy == "") {
+			return fmt.Errorf("must supply exactly one of end_at or extend_by")
+		}
+		if p.EndAt != nil && p.EndAt.Before(time.Now()) {
+			return fmt.Errorf("end_at must be in the future")
+		}
+		if p.ExtendBy != "" {
+			d, err := parseDuration(p.ExtendBy)
+			if err != nil {
+				return fmt.Errorf("bad extend_by: %w", err)
+			}
+			if d <= 0 {
+				return fmt.Errorf("extend_by must be positive")
+			}
+		}
+	default:
+		return fmt.Errorf("unsupported action: %s", p.Action)
+	}
+	return nil
+}
+
+// applyScheduleUpdate applies a validated update to the schedule. Updates that
+// leave the schedule with an EndAt make sure its expiry is being watched.
+func applyScheduleUpdate(ctx context.Context, tc client.Client, wh *webhooker, sid string, p api.ScheduleUpdatePayload) error {
+	h := tc.ScheduleClient().GetHandle(ctx, sid)
+	switch p.Action {
+	case api.ScheduleActionPause:
+		return h.Pause(ctx, client.SchedulePauseOptions{Note: p.Note})
+	case api.ScheduleActionUnpause:
+		return h.Unpause(ctx, client.ScheduleUnpauseOptions{Note: p.Note})
+	}
+
+	var endAt time.Time
+	err := h.Update(ctx, client.ScheduleUpdateOptions{
+		DoUpdate: func(in client.ScheduleUpdateInput) (*client.ScheduleUpdate, error) {
+			s := in.Description.Schedule
+			switch p.Action {
+			case api.ScheduleActionUpdateSpec:
+				rk := scheduleSearchAttributes(sid, in.Description.SearchAttributes).RequestKind
+				var spec client.ScheduleSpec
+				if p.Preset != "" {
+					var err error
+					if spec, err = kt.GetSchedulePreset(rk, p.Preset); err != nil {
+						return nil, err
+					}
+					spec.EndAt = time.Time{}
+				} else {
+					spec = *p.Schedule
+				}
+				if spec.StartAt.IsZero() {
+					spec.StartAt = s.Spec.StartAt
+				}
+				if spec.EndAt.IsZero() {
+					spec.EndAt = s.Spec.EndAt
+				}
+				if err := kt.ValidateScheduleSpec(rk, spec); err != nil {
+					return nil, err
+				}
+				s.Spec = &spec
+			case api.ScheduleActionExtend:
+				if p.EndAt != nil {
+					s.Spec.EndAt = *p.EndAt
+					break
+				}
+				if s.Spec.EndAt.IsZero() {
+					return nil, fmt.Errorf("schedule has no end_at to extend")
+				}
+				d, _ := parseDuration(p.ExtendBy)
+				s.Spec.EndAt = s.Spec.EndAt.Add(d)
+			}
+			if p.Note != "" || p.Action == api.ScheduleActionSetNote {
+				s.State.Note = p.Note
+			}
+			endAt = s.Spec.EndAt
+			return &client.ScheduleUpdate{Schedule: &s}, nil
+		},
+	})
+	if err != nil {
+		return err
+	}
+	wh.watchScheduleExpiry(ctx, sid, endAt)
+	return nil
+}
+
+// isEmptyScheduleSpec reports whether the spec was left out of the request.
+func isEmptyScheduleSpec(s client.ScheduleSpec) bool {
+	return len(s.Calendars) == 0 && len(s.Intervals) == 0 && len(s.CronExpressions) == 0 &&
+		len(s.Skip) == 0 && s.StartAt.IsZero() && s.EndAt.IsZero() && s.Jitter == 0
+}
+
+// Describes a single schedule, including its recent and upcoming actions.
+func handleDescribeSchedule(l *slog.Logger, tc client.Client) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		sid := r.PathValue("id")
+		desc, err := tc.ScheduleClient().GetHandle(r.Context(), sid).Describe(r.Context())
+		if err != nil {
+			var nf *serviceerror.NotFound
+			if errors.As(err, &nf) {
+				writeEmptyResultError(w)
+				return
+			}
+			writeInternalError(l, w, err)
+			return
+		}
+		a := scheduleSearchAttributes(sid, desc.SearchAttributes)
+		res := api.ScheduleDescription{
+			ID:               sid,
+			RequestKind:      a.RequestKind,
+			EntityID:         a.EntityID,
+			OwnerEmail:       a.OwnerEmail,
+			Tier:             a.Tier,
+			Spec:             desc.Schedule.Spec,
+			Paused:           desc.Schedule.State.Paused,
+			Note:             desc.Schedule.State.Note,
+			FixedCadence:     fixedCadence(desc),
+			NumActions:       desc.Info.NumActions,
+			RecentActions:    desc.Info.RecentActions,
+			RunningWorkflows: desc.Info.RunningWorkflows,
+			NextActionTimes:  desc.Info.NextActionTimes,
+			CreatedAt:        desc.Info.CreatedAt,
+			LastUpdateAt:     desc.Info.LastUpdateAt,
+		}
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(res)
+	}
+}
+
 func handleCancelSchedule(l *slog.Logger, tc client.Client) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		sid := r.URL.Query().Get("schedule_id")
@@ -278,3 +592,52 @@ func handleTriggerSchedule(l *slog.Logger, tc client.Client) http.HandlerFunc {
 		writeOK(w)
 	}
 }
+
+// Called by a polling workflow whose schedule keeps failing permanently (e.g.,
+// the content was deleted). The schedule is paused with a note saying why
+// (rather than deleted, so it can be unpaused if the content comes back) and
+// the entity is marked as ended.
+func handlePauseFailingSchedule(l *slog.Logger, q *dbgen.Queries, tc client.Client) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var body api.SchedulePauseFailingPayload
+		err := stools.DecodeJSONBody(r, &body)
+		if err != nil {
+			writeBadRequestError(w, err)
+			return
+		}
+		reason := fmt.Sprintf("%d consecutive permanent failures (%s)", body.Failures, body.Reason)
+		note := fmt.Sprintf("paused after %s", reason)
+		// a deleted schedule still has its entity marked as ended
+		h := tc.ScheduleClient().GetHandle(r.Context(), body.ScheduleID)
+		a := scheduleSearchAttributes(body.ScheduleID, nil)
+		desc, err := h.Describe(r.Context())
+		if err == nil {
+			a = scheduleSearchAttributes(body.ScheduleID, desc.SearchAttributes)
+			err = h.Pause(r.Context(), client.SchedulePauseOptions{Note: note})
+		}
+		if err != nil {
+			var nf *serviceerror.NotFound
+			if !errors.As(err, &nf) {
+				writeInternalError(l, w, err)
+				return
+			}
+		}
+		if a.RequestKind == "" {
+			writeBadRequestError(w, fmt.Errorf("unexpected schedule id: %s", body.ScheduleID))
+			return
+		}
+		_, err = q.SetMetadataStatus(r.Context(), dbgen.SetMetadataStatusParams{
+			Status:       jsonb.MetadataStatusEnded,
+			StatusReason: reason,
+			TsEnded:      pgtype.Timestamptz{Time: time.Now(), Valid: true},
+			RequestKind:  a.RequestKind,
+			ID:           a.EntityID,
+		})
+		if err != nil {
+			writeInternalError(l, w, err)
+			return
+		}
+		l.Info("paused failing schedule", "schedule_id", body.ScheduleID, "reason", reason)
+		writeOK(w)
+	}
+}
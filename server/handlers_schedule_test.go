@@ -0,0 +1,176 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/brojonat/kaggo/server/api"
+	"github.com/brojonat/kaggo/server/db/dbgen"
+	kt "github.com/brojonat/kaggo/temporal/v19700101"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/mock"
+	schedulepb "go.temporal.io/api/schedule/v1"
+	"go.temporal.io/api/serviceerror"
+	"go.temporal.io/api/workflowservice/v1"
+	"go.temporal.io/sdk/client"
+	"go.temporal.io/sdk/mocks"
+	"google.golang.org/grpc"
+)
+
+// fakeDB is a dbgen.DBTX that records Exec calls. Rows returned by QueryRow
+// scan their columns from row; Query always fails.
+type fakeDB struct {
+	row   []string
+	execs [][]interface{}
+}
+
+func (db *fakeDB) Exec(ctx context.Context, sql string, args ...interface{}) (pgconn.CommandTag, error) {
+	db.execs = append(db.execs, append([]interface{}{sql}, args...))
+	return pgconn.CommandTag{}, nil
+}
+
+func (db *fakeDB) Query(ctx context.Context, sql string, args ...interface{}) (pgx.Rows, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (db *fakeDB) QueryRow(ctx context.Context, sql string, args ...interface{}) pgx.Row {
+	return fakeRow(db.row)
+}
+
+func (db *fakeDB) CopyFrom(ctx context.Context, tableName pgx.Identifier, columnNames []string, rowSrc pgx.CopyFromSource) (int64, error) {
+	return 0, errors.New("not implemented")
+}
+
+type fakeRow []string
+
+func (r fakeRow) Scan(dest ...interface{}) error {
+	if len(r) == 0 {
+		return pgx.ErrNoRows
+	}
+	for i, d := range dest {
+		if s, ok := d.(*string); ok && i < len(r) {
+			*s = r[i]
+		}
+	}
+	return nil
+}
+
+func TestCreateScheduleWithAPIKey(t *testing.T) {
+	l := slog.New(slog.NewTextHandler(io.Discard, nil))
+	db := &fakeDB{row: []string{"1234", kt.RequestKindInternalRandom}}
+	q := dbgen.New(db)
+
+	sc := &mocks.ScheduleClient{}
+	h := &mocks.ScheduleHandle{}
+	h.On("Describe", mock.Anything).Return(nil, serviceerror.NewNotFound("not found"))
+	sc.On("GetHandle", mock.Anything, mock.Anything).Return(h)
+	var opts client.ScheduleOptions
+	sc.On("Create", mock.Anything, mock.Anything).
+		Run(func(args mock.Arguments) { opts = args.Get(1).(client.ScheduleOptions) }).
+		Return(h, nil)
+	tc := &mocks.Client{}
+	tc.On("ScheduleClient").Return(sc)
+
+	handler := handleCreateSchedule(l, q, tc, newWebhooker(l, q, tc))
+	body := `{"request_kind": "internal.random", "id": "1234", "preset": "default"}`
+	r := httptest.NewRequest(http.MethodPost, "/schedule?skip-metadata=true", strings.NewReader(body))
+	key := &dbgen.ApiKey{Email: "owner@example.com", Scopes: []string{scopeScheduleAdmin}}
+	r = r.WithContext(context.WithValue(r.Context(), ctxKeyAPIKey, key))
+	w := httptest.NewRecorder()
+	handler(w, r)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	if got := opts.SearchAttributes[kt.SearchAttributeOwnerEmail]; got != key.Email {
+		t.Errorf("expected schedule owner %s, got %v", key.Email, got)
+	}
+	action, ok := opts.Action.(*client.ScheduleWorkflowAction)
+	if !ok || action.SearchAttributes[kt.SearchAttributeOwnerEmail] != key.Email {
+		t.Errorf("expected the polling workflow to be owned by %s", key.Email)
+	}
+	if len(db.execs) != 1 || db.execs[0][1] != key.Email {
+		t.Errorf("expected the metric to be granted to %s, got %v", key.Email, db.execs)
+	}
+}
+
+// fakeScheduleLister serves ListSchedules from ids, honoring the page size; the
+// page token is the index of the next schedule.
+type fakeScheduleLister struct {
+	workflowservice.WorkflowServiceClient
+	ids   []string
+	calls int
+}
+
+func (f *fakeScheduleLister) ListSchedules(ctx context.Context, in *workflowservice.ListSchedulesRequest, opts ...grpc.CallOption) (*workflowservice.ListSchedulesResponse, error) {
+	f.calls++
+	start := 0
+	if len(in.NextPageToken) > 0 {
+		start, _ = strconv.Atoi(string(in.NextPageToken))
+	}
+	end := min(start+int(in.MaximumPageSize), len(f.ids))
+	res := &workflowservice.ListSchedulesResponse{}
+	for _, id := range f.ids[start:end] {
+		res.Schedules = append(res.Schedules, &schedulepb.ScheduleListEntry{ScheduleId: id})
+	}
+	if end < len(f.ids) {
+		res.NextPageToken = []byte(strconv.Itoa(end))
+	}
+	return res, nil
+}
+
+// Filtered pages are filled from later pages, and the next page token picks up
+// after the last schedule that was looked at.
+func TestGetScheduleFillsFilteredPages(t *testing.T) {
+	l := slog.New(slog.NewTextHandler(io.Discard, nil))
+	ws := &fakeScheduleLister{ids: []string{
+		"internal.random 1 s1",
+		"youtube.video a s2",
+		"youtube.video b s3",
+		"internal.random 2 s4",
+		"youtube.video c s5",
+		"internal.random 3 s6",
+		"youtube.video d s7",
+	}}
+	tc := &mocks.Client{}
+	tc.On("WorkflowService").Return(ws)
+
+	var ids []string
+	token := ""
+	for page := 0; page < 3; page++ {
+		r := httptest.NewRequest(http.MethodGet, "/schedule?request_kind=internal.random&page_size=2&page_token="+token, nil)
+		w := httptest.NewRecorder()
+		handleGetSchedule(l, tc)(w, r)
+		if w.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+		}
+		var res api.ScheduleListResponse
+		if err := json.NewDecoder(w.Body).Decode(&res); err != nil {
+			t.Fatal(err)
+		}
+		for _, s := range res.Schedules {
+			ids = append(ids, s.ID)
+		}
+		if res.NextPageToken == "" {
+			break
+		}
+		if len(res.Schedules) != 2 {
+			t.Errorf("page %d: expected a full page with a next page token, got %d schedules", page, len(res.Schedules))
+		}
+		token = res.NextPageToken
+	}
+	want := []string{"internal.random 1 s1", "internal.random 2 s4", "internal.random 3 s6"}
+	if !slices.Equal(ids, want) {
+		t.Errorf("expected %v, got %v", want, ids)
+	}
+}
@@ -6,6 +6,9 @@ import (
 	"fmt"
 	"log/slog"
 	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/brojonat/kaggo/server/db/dbgen"
@@ -13,53 +16,267 @@ import (
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
-// Main entry point for handling bucketed timeseries. This handler effectively
-// dispatches requests by switching over supported metrics and passing the
-// request to the appropriate metric handler.
-func handleGetTimeSeriesByIDsBucketed(l *slog.Logger, q *dbgen.Queries) http.HandlerFunc {
+// Supported aggregates for bucketed timeseries. The delta is the change in
+// the last value from the previous bucket.
+const (
+	aggregateLast  = "last"
+	aggregateMax   = "max"
+	aggregateMin   = "min"
+	aggregateAvg   = "avg"
+	aggregateDelta = "delta"
+)
+
+var bucketAggregates = map[string]bool{
+	aggregateLast:  true,
+	aggregateMax:   true,
+	aggregateMin:   true,
+	aggregateAvg:   true,
+	aggregateDelta: true,
+}
+
+// The smallest bucket we'll compute; anything finer than this is better
+// served by the raw timeseries.
+const minBucketWidth = time.Second
+
+// parseDuration is time.ParseDuration extended with day ("d") and week ("w")
+// units, e.g., "7d" or "-2w". These can't be combined with other units.
+func parseDuration(s string) (time.Duration, error) {
+	for suffix, unit := range map[string]time.Duration{"d": 24 * time.Hour, "w": 7 * 24 * time.Hour} {
+		if !strings.HasSuffix(s, suffix) {
+			continue
+		}
+		n, err := strconv.ParseFloat(strings.TrimSuffix(s, suffix), 64)
+		if err != nil {
+			return 0, fmt.Errorf("invalid duration %q", s)
+		}
+		return time.Duration(n * float64(unit)), nil
+	}
+	return time.ParseDuration(s)
+}
+
+// parseBucketWidth parses the bucket_size parameter into the interval passed
+// to time_bucket. In addition to the units accepted by parseDuration, whole
+// months may be specified (e.g., "1mo").
+func parseBucketWidth(s string) (pgtype.Interval, error) {
+	if n, ok := strings.CutSuffix(s, "mo"); ok {
+		months, err := strconv.Atoi(n)
+		if err != nil || months < 1 {
+			return pgtype.Interval{}, fmt.Errorf("invalid bucket_size %q", s)
+		}
+		return pgtype.Interval{Months: int32(months), Valid: true}, nil
+	}
+	d, err := parseDuration(s)
+	if err != nil {
+		return pgtype.Interval{}, fmt.Errorf("invalid bucket_size: %w", err)
+	}
+	if d < minBucketWidth {
+		return pgtype.Interval{}, fmt.Errorf("bucket_size must be at least %s", minBucketWidth)
+	}
+	return pgtype.Interval{Microseconds: d.Microseconds(), Valid: true}, nil
+}
+
+// parseTimeParam parses a start/end parameter. The value may be an RFC3339
+// timestamp, "now", or an offset from now (e.g., "-24h", "now-7d"). An empty
+// value returns def.
+func parseTimeParam(s string, now, def time.Time) (time.Time, error) {
+	if s == "" {
+		return def, nil
+	}
+	if ts, err := time.Parse(time.RFC3339, s); err == nil {
+		return ts, nil
+	}
+	rel := strings.TrimPrefix(s, "now")
+	if rel == "" {
+		return now, nil
+	}
+	if !strings.HasPrefix(rel, "-") && !strings.HasPrefix(rel, "+") {
+		return time.Time{}, fmt.Errorf("invalid time %q: must be RFC3339 or relative to now (e.g., -24h)", s)
+	}
+	d, err := parseDuration(rel)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid time %q: %w", s, err)
+	}
+	return now.Add(d), nil
+}
+
+// parseTimeRange parses the time range query parameters shared by all the
+// timeseries endpoints. The range defaults to all time; "dur" truncates it to
+// the trailing duration (e.g., 24h, 7d) and "start"/"end" (see parseTimeParam)
+// set it explicitly. If both dur and start are supplied, start wins.
+func parseTimeRange(v url.Values) (time.Time, time.Time, error) {
+	now := time.Now()
+	def := time.Time{}
+	if dur := v.Get("dur"); dur != "" {
+		d, err := parseDuration(dur)
+		if err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("could not parse duration: %w", err)
+		}
+		if d <= 0 {
+			return time.Time{}, time.Time{}, fmt.Errorf("dur must be positive")
+		}
+		def = now.Add(-d)
+	}
+	ts_start, err := parseTimeParam(v.Get("start"), now, def)
+	if err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("bad start: %w", err)
+	}
+	ts_end, err := parseTimeParam(v.Get("end"), now, now)
+	if err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("bad end: %w", err)
+	}
+	if !ts_start.Before(ts_end) {
+		return time.Time{}, time.Time{}, fmt.Errorf("start must be before end")
+	}
+	return ts_start, ts_end, nil
+}
+
+// getTimeSeries returns the raw metric samples under the supplied (kind, ids)
+// between ts_start and ts_end. This works for every RequestKind since all
+// metrics are stored in the same table.
+func getTimeSeries(
+	ctx context.Context,
+	l *slog.Logger,
+	q *dbgen.Queries,
+	rk string,
+	ids []string,
+	ts_start time.Time,
+	ts_end time.Time,
+) ([]dbgen.GetMetricSamplesByIDsRow, error) {
+	return q.GetMetricSamplesByIDs(ctx, dbgen.GetMetricSamplesByIDsParams{
+		RequestKind: rk,
+		Ids:         ids,
+		TsStart:     pgtype.Timestamptz{Time: ts_start, Valid: true},
+		TsEnd:       pgtype.Timestamptz{Time: ts_end, Valid: true},
+	})
+}
+
+// getTimeSeriesBucketed returns the metric samples under the supplied (kind,
+// ids) between ts_start and ts_end aggregated into buckets of width bw using
+// the supplied aggregate.
+func getTimeSeriesBucketed(
+	ctx context.Context,
+	l *slog.Logger,
+	q *dbgen.Queries,
+	rk string,
+	ids []string,
+	bw pgtype.Interval,
+	agg string,
+	ts_start time.Time,
+	ts_end time.Time,
+) ([]dbgen.GetMetricSamplesByIDsBucketedRow, error) {
+	return q.GetMetricSamplesByIDsBucketed(ctx, dbgen.GetMetricSamplesByIDsBucketedParams{
+		BucketWidth: bw,
+		Aggregate:   agg,
+		RequestKind: rk,
+		Ids:         ids,
+		TsStart:     pgtype.Timestamptz{Time: ts_start, Valid: true},
+		TsEnd:       pgtype.Timestamptz{Time: ts_end, Valid: true},
+	})
+}
+
+// handleGetTimeSeriesByKind serves the raw timeseries for a single
+// RequestKind; this backs the per-kind GET endpoints (e.g., GET /reddit/post).
+// The time range may be limited with dur/start/end (see parseTimeRange).
+func handleGetTimeSeriesByKind(l *slog.Logger, q *dbgen.Queries, rk string) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		rk := r.URL.Query().Get("request_kind")
-		switch rk {
-		case kt.RequestKindYouTubeVideo:
-			handleGetYouTubeVideoTimeSeriesByIDsBucketed(l, q)(w, r)
+		ids := r.URL.Query()["id"]
+		if len(ids) == 0 {
+			writeBadRequestError(w, fmt.Errorf("must supply id(s)"))
 			return
-		case kt.RequestKindKaggleNotebook:
-			handleGetKaggleNotebookTimeSeriesByIDsBucketed(l, q)(w, r)
+		}
+		ts_start, ts_end, err := parseTimeRange(r.URL.Query())
+		if err != nil {
+			writeBadRequestError(w, err)
 			return
-		case kt.RequestKindKaggleDataset:
-			handleGetKaggleDatasetTimeSeriesByIDsBucketed(l, q)(w, r)
+		}
+		ids, err = grantedIDs(r, q, rk, ids)
+		if err != nil {
+			writeInternalError(l, w, err)
 			return
-		case kt.RequestKindYouTubeChannel:
-			handleGetYouTubeChannelTimeSeriesByIDsBucketed(l, q)(w, r)
+		}
+		if len(ids) == 0 {
+			writeEmptyResultError(w)
+			return
+		}
+		res, err := getTimeSeries(r.Context(), l, q, rk, ids, ts_start, ts_end)
+		if err != nil {
+			writeInternalError(l, w, err)
+			return
+		}
+		if res == nil {
+			writeEmptyResultError(w)
 			return
-		case kt.RequestKindRedditPost:
-			handleGetRedditPostTimeSeriesByIDsBucketed(l, q)(w, r)
+		}
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(res)
+	}
+}
+
+// Main entry point for handling bucketed timeseries. All RequestKinds share
+// the same storage, so the only thing that varies is the request_kind filter.
+// Query parameters:
+//   - bucket_size: any bucket width (e.g., 15m, 1h, 1d, 2w, 1mo); default 60m
+//   - aggregate: last, max, min, avg, or delta; default max
+//   - dur, start, end: the time range (see parseTimeRange); default all time
+func handleGetTimeSeriesByIDsBucketed(l *slog.Logger, q *dbgen.Queries) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		rk := r.URL.Query().Get("request_kind")
+		if _, err := kt.GetRequestKindSpec(rk); err != nil {
+			writeBadRequestError(w, err)
 			return
-		case kt.RequestKindRedditComment:
-			handleGetRedditCommentTimeSeriesByIDsBucketed(l, q)(w, r)
+		}
+		bs := r.URL.Query().Get("bucket_size")
+		if bs == "" {
+			bs = "60m"
+		}
+		bw, err := parseBucketWidth(bs)
+		if err != nil {
+			writeBadRequestError(w, err)
 			return
-		case kt.RequestKindRedditSubreddit:
-			handleGetRedditSubredditTimeSeriesByIDsBucketed(l, q)(w, r)
+		}
+		agg := r.URL.Query().Get("aggregate")
+		if agg == "" {
+			agg = aggregateMax
+		}
+		if !bucketAggregates[agg] {
+			writeBadRequestError(w, fmt.Errorf("unsupported aggregate: %s", agg))
 			return
-		case kt.RequestKindRedditUser:
-			handleGetRedditUserTimeSeriesByIDsBucketed(l, q)(w, r)
+		}
+		ts_start, ts_end, err := parseTimeRange(r.URL.Query())
+		if err != nil {
+			writeBadRequestError(w, err)
 			return
-		case kt.RequestKindTwitchClip:
-			handleGetTwitchClipTimeSeriesByIDsBucketed(l, q)(w, r)
+		}
+		ids := r.URL.Query()["id"]
+		if len(ids) == 0 {
+			idstr := r.URL.Query().Get("ids")
+			ids = strings.Split(idstr, ",")
+		}
+		if len(ids) == 0 {
+			writeBadRequestError(w, fmt.Errorf("must supply id(s)"))
 			return
-		case kt.RequestKindTwitchVideo:
-			handleGetTwitchVideoTimeSeriesByIDsBucketed(l, q)(w, r)
+		}
+		ids, err = grantedIDs(r, q, rk, ids)
+		if err != nil {
+			writeInternalError(l, w, err)
 			return
-		case kt.RequestKindTwitchStream:
-			handleGetTwitchStreamTimeSeriesByIDsBucketed(l, q)(w, r)
+		}
+		if len(ids) == 0 {
+			writeEmptyResultError(w)
 			return
-		case kt.RequestKindTwitchUserPastDec:
-			handleGetTwitchUserPastDecTimeSeriesByIDsBucketed(l, q)(w, r)
+		}
+
+		res, err := getTimeSeriesBucketed(r.Context(), l, q, rk, ids, bw, agg, ts_start, ts_end)
+		if err != nil {
+			writeInternalError(l, w, err)
 			return
-		default:
-			writeBadRequestError(w, fmt.Errorf("unsupported request kind: %s", rk))
+		}
+		if res == nil {
+			writeEmptyResultError(w)
 			return
 		}
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(res)
 	}
 }
 
@@ -72,77 +289,34 @@ func handleGetTimeSeriesByIDs(l *slog.Logger, q *dbgen.Queries) http.HandlerFunc
 	return func(w http.ResponseWriter, r *http.Request) {
 		rk := r.URL.Query().Get("request_kind")
 		ids := r.URL.Query()["id"]
-		dur := r.URL.Query().Get("dur")
 		if rk == "" || len(ids) == 0 {
 			writeBadRequestError(w, fmt.Errorf("must supply request_kind and id(s)"))
 			return
 		}
+		if _, err := kt.GetRequestKindSpec(rk); err != nil {
+			writeBadRequestError(w, err)
+			return
+		}
 
 		// optionally truncate by timestamp
-		var ts_start time.Time
-		if dur != "" {
-			tdur, err := time.ParseDuration(dur)
-			if err != nil {
-				writeBadRequestError(w, fmt.Errorf("could not parse duration: %w", err))
-				return
-			}
-			ts_start = time.Now().Add(-tdur)
+		ts_start, ts_end, err := parseTimeRange(r.URL.Query())
+		if err != nil {
+			writeBadRequestError(w, err)
+			return
+		}
+		ids, err = grantedIDs(r, q, rk, ids)
+		if err != nil {
+			writeInternalError(l, w, err)
+			return
+		}
+		if len(ids) == 0 {
+			writeEmptyResultError(w)
+			return
 		}
 
-		var err error
-		var rows interface{}
-
-		switch rk {
-		case kt.RequestKindInternalRandom:
-			rows, err = getInternalRandomTimeSeries(r.Context(), l, q, ids, ts_start, time.Now())
-			if err != nil {
-				writeInternalError(l, w, err)
-				return
-			}
-		case kt.RequestKindYouTubeVideo:
-			rows, err = getYouTubeVideoTimeSeries(r.Context(), l, q, ids, ts_start, time.Now())
-			if err != nil {
-				writeInternalError(l, w, err)
-				return
-			}
-		case kt.RequestKindKaggleNotebook:
-			rows, err = getKaggleNotebookTimeSeries(r.Context(), l, q, ids, ts_start, time.Now())
-			if err != nil {
-				writeInternalError(l, w, err)
-				return
-			}
-		case kt.RequestKindKaggleDataset:
-			rows, err = getKaggleDatasetTimeSeries(r.Context(), l, q, ids, ts_start, time.Now())
-			if err != nil {
-				writeInternalError(l, w, err)
-				return
-			}
-		case kt.RequestKindRedditPost:
-			rows, err = getRedditPostTimeSeries(r.Context(), l, q, ids, ts_start, time.Now())
-			if err != nil {
-				writeInternalError(l, w, err)
-				return
-			}
-		case kt.RequestKindRedditComment:
-			rows, err = getRedditCommentTimeSeries(r.Context(), l, q, ids, ts_start, time.Now())
-			if err != nil {
-				writeInternalError(l, w, err)
-				return
-			}
-		case kt.RequestKindRedditSubreddit:
-			rows, err = getRedditSubredditTimeSeries(r.Context(), l, q, ids, ts_start, time.Now())
-			if err != nil {
-				writeInternalError(l, w, err)
-				return
-			}
-		case kt.RequestKindRedditUser:
-			rows, err = getRedditUserTimeSeries(r.Context(), l, q, ids, ts_start, time.Now())
-			if err != nil {
-				writeInternalError(l, w, err)
-				return
-			}
-		default:
-			writeBadRequestError(w, fmt.Errorf("unexpected RequestKind %s", rk))
+		rows, err := getTimeSeries(r.Context(), l, q, rk, ids, ts_start, ts_end)
+		if err != nil {
+			writeInternalError(l, w, err)
 			return
 		}
 		w.WriteHeader(http.StatusOK)
@@ -150,139 +324,3 @@ func handleGetTimeSeriesByIDs(l *slog.Logger, q *dbgen.Queries) http.HandlerFunc
 
 	}
 }
-
-// the following are semi deprecated routes
-func getInternalRandomTimeSeries(
-	ctx context.Context,
-	l *slog.Logger,
-	q *dbgen.Queries,
-	ids []string,
-	ts_start time.Time,
-	ts_end time.Time,
-) (interface{}, error) {
-	return q.GetInternalMetricsByIDs(ctx, dbgen.GetInternalMetricsByIDsParams{
-		Ids:     ids,
-		TsStart: pgtype.Timestamptz{Time: ts_start, Valid: true},
-		TsEnd:   pgtype.Timestamptz{Time: ts_end, Valid: true},
-	})
-}
-
-func getYouTubeVideoTimeSeries(
-	ctx context.Context,
-	l *slog.Logger,
-	q *dbgen.Queries,
-	ids []string,
-	ts_start time.Time,
-	ts_end time.Time,
-) (interface{}, error) {
-	return q.GetYouTubeVideoMetricsByIDs(ctx, dbgen.GetYouTubeVideoMetricsByIDsParams{
-		Ids:     ids,
-		TsStart: pgtype.Timestamptz{Time: ts_start, Valid: true},
-		TsEnd:   pgtype.Timestamptz{Time: ts_end, Valid: true},
-	})
-}
-
-func getYouTubeChannelTimeSeries(
-	ctx context.Context,
-	l *slog.Logger,
-	q *dbgen.Queries,
-	ids []string,
-	ts_start time.Time,
-	ts_end time.Time,
-) (interface{}, error) {
-	return q.GetYouTubeChannelMetricsByIDs(ctx, dbgen.GetYouTubeChannelMetricsByIDsParams{
-		Ids:     ids,
-		TsStart: pgtype.Timestamptz{Time: ts_start, Valid: true},
-		TsEnd:   pgtype.Timestamptz{Time: ts_end, Valid: true},
-	})
-}
-
-func getKaggleNotebookTimeSeries(
-	ctx context.Context,
-	l *slog.Logger,
-	q *dbgen.Queries,
-	ids []string,
-	ts_start time.Time,
-	ts_end time.Time,
-) (interface{}, error) {
-	return q.GetKaggleNotebookMetrics(ctx, dbgen.GetKaggleNotebookMetricsParams{
-		Ids:     ids,
-		TsStart: pgtype.Timestamptz{Time: ts_start, Valid: true},
-		TsEnd:   pgtype.Timestamptz{Time: ts_end, Valid: true},
-	})
-}
-
-func getKaggleDatasetTimeSeries(
-	ctx context.Context,
-	l *slog.Logger,
-	q *dbgen.Queries,
-	ids []string,
-	ts_start time.Time,
-	ts_end time.Time,
-) (interface{}, error) {
-	return q.GetKaggleDatasetMetrics(ctx, dbgen.GetKaggleDatasetMetricsParams{
-		Ids:     ids,
-		TsStart: pgtype.Timestamptz{Time: ts_start, Valid: true},
-		TsEnd:   pgtype.Timestamptz{Time: ts_end, Valid: true},
-	})
-}
-
-func getRedditPostTimeSeries(
-	ctx context.Context,
-	l *slog.Logger,
-	q *dbgen.Queries,
-	ids []string,
-	ts_start time.Time,
-	ts_end time.Time,
-) (interface{}, error) {
-	return q.GetRedditPostMetricsByIDs(ctx, dbgen.GetRedditPostMetricsByIDsParams{
-		Ids:     ids,
-		TsStart: pgtype.Timestamptz{Time: ts_start, Valid: true},
-		TsEnd:   pgtype.Timestamptz{Time: ts_end, Valid: true},
-	})
-}
-
-func getRedditCommentTimeSeries(
-	ctx context.Context,
-	l *slog.Logger,
-	q *dbgen.Queries,
-	ids []string,
-	ts_start time.Time,
-	ts_end time.Time,
-) (interface{}, error) {
-	return q.GetRedditCommentMetricsByIDs(ctx, dbgen.GetRedditCommentMetricsByIDsParams{
-		Ids:     ids,
-		TsStart: pgtype.Timestamptz{Time: ts_start, Valid: true},
-		TsEnd:   pgtype.Timestamptz{Time: ts_end, Valid: true},
-	})
-}
-
-func getRedditSubredditTimeSeries(
-	ctx context.Context,
-	l *slog.Logger,
-	q *dbgen.Queries,
-	ids []string,
-	ts_start time.Time,
-	ts_end time.Time,
-) (interface{}, error) {
-	return q.GetRedditSubredditMetricsByIDs(ctx, dbgen.GetRedditSubredditMetricsByIDsParams{
-		Ids:     ids,
-		TsStart: pgtype.Timestamptz{Time: ts_start, Valid: true},
-		TsEnd:   pgtype.Timestamptz{Time: ts_end, Valid: true},
-	})
-}
-
-func getRedditUserTimeSeries(
-	ctx context.Context,
-	l *slog.Logger,
-	q *dbgen.Queries,
-	ids []string,
-	ts_start time.Time,
-	ts_end time.Time,
-) (interface{}, error) {
-	return q.GetRedditUserMetricsByIDs(ctx, dbgen.GetRedditUserMetricsByIDsParams{
-		Ids:     ids,
-		TsStart: pgtype.Timestamptz{Time: ts_start, Valid: true},
-		TsEnd:   pgtype.Timestamptz{Time: ts_end, Valid: true},
-	})
-}
@@ -2,15 +2,18 @@ package server
 
 import (
 	"encoding/json"
+	"fmt"
 	"log/slog"
 	"net/http"
 
+	"github.com/brojonat/kaggo/server/alerts"
 	"github.com/brojonat/kaggo/server/api"
 	"github.com/brojonat/kaggo/server/db/dbgen"
+	kt "github.com/brojonat/kaggo/temporal/v19700101"
 	"github.com/prometheus/client_golang/prometheus"
 )
 
-func handleTwitchClipMetricsPost(l *slog.Logger, q *dbgen.Queries, pms map[string]prometheus.Collector) http.HandlerFunc {
+func handleTwitchClipMetricsPost(l *slog.Logger, q *dbgen.Queries, al *alerts.Alerter, pms map[string]prometheus.Collector) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		// parse
 		var p api.TwitchClipMetricPayload
@@ -20,75 +23,102 @@ func handleTwitchClipMetricsPost(l *slog.Logger, q *dbgen.Queries, pms map[strin
 			writeBadRequestError(w, err)
 			return
 		}
+		if p.ID == "" {
+			writeBadRequestError(w, fmt.Errorf("must supply id"))
+			return
+		}
+		ts, err := api.SampleTime(p.Ts)
+		if err != nil {
+			writeBadRequestError(w, err)
+			return
+		}
 
 		// upload metrics
+		ms := map[string]float64{}
 		if p.SetViewCount {
-			err = q.InsertTwitchClipViews(
-				r.Context(),
-				dbgen.InsertTwitchClipViewsParams{
-					ID: p.ID, Views: int64(p.ViewCount)})
-			if err != nil {
-				writeInternalError(l, w, err)
-				return
-			}
+			ms["views"] = float64(p.ViewCount)
 		}
+		err = insertMetricSamples(r.Context(), q, al, kt.RequestKindTwitchClip, p.ID, ms, ts, "")
+		if err != nil {
+			writeInternalError(l, w, err)
+			return
+		}
+
 		writeOK(w)
 	}
 }
 
-func handleTwitchVideoMetricsPost(l *slog.Logger, q *dbgen.Queries, pms map[string]prometheus.Collector) http.HandlerFunc {
+func handleTwitchVideoMetricsPost(l *slog.Logger, q *dbgen.Queries, al *alerts.Alerter, pms map[string]prometheus.Collector) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		// parse
-		var p api.TwitchClipMetricPayload
+		var p api.TwitchVideoMetricPayload
 		defer r.Body.Close()
 		err := json.NewDecoder(r.Body).Decode(&p)
 		if err != nil {
 			writeBadRequestError(w, err)
 			return
 		}
+		if p.ID == "" {
+			writeBadRequestError(w, fmt.Errorf("must supply id"))
+			return
+		}
+		ts, err := api.SampleTime(p.Ts)
+		if err != nil {
+			writeBadRequestError(w, err)
+			return
+		}
 
 		// upload metrics
+		ms := map[string]float64{}
 		if p.SetViewCount {
-			err = q.InsertTwitchVideoViews(
-				r.Context(),
-				dbgen.InsertTwitchVideoViewsParams{
-					ID: p.ID, Views: int64(p.ViewCount)})
-			if err != nil {
-				writeInternalError(l, w, err)
-				return
-			}
+			ms["views"] = float64(p.ViewCount)
 		}
+		err = insertMetricSamples(r.Context(), q, al, kt.RequestKindTwitchVideo, p.ID, ms, ts, "")
+		if err != nil {
+			writeInternalError(l, w, err)
+			return
+		}
+
 		writeOK(w)
 	}
 }
 
-func handleTwitchStreamMetricsPost(l *slog.Logger, q *dbgen.Queries, pms map[string]prometheus.Collector) http.HandlerFunc {
+func handleTwitchStreamMetricsPost(l *slog.Logger, q *dbgen.Queries, al *alerts.Alerter, pms map[string]prometheus.Collector) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		// parse
-		var p api.TwitchClipMetricPayload
+		var p api.TwitchStreamMetricPayload
 		defer r.Body.Close()
 		err := json.NewDecoder(r.Body).Decode(&p)
 		if err != nil {
 			writeBadRequestError(w, err)
 			return
 		}
+		if p.ID == "" {
+			writeBadRequestError(w, fmt.Errorf("must supply id"))
+			return
+		}
+		ts, err := api.SampleTime(p.Ts)
+		if err != nil {
+			writeBadRequestError(w, err)
+			return
+		}
 
 		// upload metrics
+		ms := map[string]float64{}
 		if p.SetViewCount {
-			err = q.InsertTwitchStreamViews(
-				r.Context(),
-				dbgen.InsertTwitchStreamViewsParams{
-					ID: p.ID, Views: int64(p.ViewCount)})
-			if err != nil {
-				writeInternalError(l, w, err)
-				return
-			}
+			ms["views"] = float64(p.ViewCount)
 		}
+		err = insertMetricSamples(r.Context(), q, al, kt.RequestKindTwitchStream, p.ID, ms, ts, "")
+		if err != nil {
+			writeInternalError(l, w, err)
+			return
+		}
+
 		writeOK(w)
 	}
 }
 
-func handleTwitchUserPastDecMetricsPost(l *slog.Logger, q *dbgen.Queries, pms map[string]prometheus.Collector) http.HandlerFunc {
+func handleTwitchUserPastDecMetricsPost(l *slog.Logger, q *dbgen.Queries, al *alerts.Alerter, pms map[string]prometheus.Collector) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		// parse
 		var p api.TwitchUserPastDecMetricPayload
@@ -98,68 +128,42 @@ func handleTwitchUserPastDecMetricsPost(l *slog.Logger, q *dbgen.Queries, pms ma
 			writeBadRequestError(w, err)
 			return
 		}
+		if p.ID == "" {
+			writeBadRequestError(w, fmt.Errorf("must supply id"))
+			return
+		}
+		ts, err := api.SampleTime(p.Ts)
+		if err != nil {
+			writeBadRequestError(w, err)
+			return
+		}
 
 		// upload metrics
+		ms := map[string]float64{}
 		if p.SetAvgViewCount {
-			err = q.InsertTwitchUserPastDecAvgViews(
-				r.Context(),
-				dbgen.InsertTwitchUserPastDecAvgViewsParams{
-					ID: p.ID, AvgViews: p.AvgViewCount})
-			if err != nil {
-				writeInternalError(l, w, err)
-				return
-			}
+			ms["avg-views"] = float64(p.AvgViewCount)
 		}
 		if p.SetMedViewCount {
-			err = q.InsertTwitchUserPastDecMedViews(
-				r.Context(),
-				dbgen.InsertTwitchUserPastDecMedViewsParams{
-					ID: p.ID, MedViews: p.MedViewCount})
-			if err != nil {
-				writeInternalError(l, w, err)
-				return
-			}
+			ms["med-views"] = float64(p.MedViewCount)
 		}
 		if p.SetStdViewCount {
-			err = q.InsertTwitchUserPastDecStdViews(
-				r.Context(),
-				dbgen.InsertTwitchUserPastDecStdViewsParams{
-					ID: p.ID, StdViews: p.StdViewCount})
-			if err != nil {
-				writeInternalError(l, w, err)
-				return
-			}
+			ms["std-views"] = float64(p.StdViewCount)
 		}
 		if p.SetAvgDuration {
-			err = q.InsertTwitchUserPastDecAvgDuration(
-				r.Context(),
-				dbgen.InsertTwitchUserPastDecAvgDurationParams{
-					ID: p.ID, AvgDuration: p.AvgDuration})
-			if err != nil {
-				writeInternalError(l, w, err)
-				return
-			}
+			ms["avg-duration"] = float64(p.AvgDuration)
 		}
 		if p.SetMedDuration {
-			err = q.InsertTwitchUserPastDecMedDuration(
-				r.Context(),
-				dbgen.InsertTwitchUserPastDecMedDurationParams{
-					ID: p.ID, MedDuration: p.MedDuration})
-			if err != nil {
-				writeInternalError(l, w, err)
-				return
-			}
+			ms["med-duration"] = float64(p.MedDuration)
 		}
 		if p.SetStdDuration {
-			err = q.InsertTwitchUserPastDecStdDuration(
-				r.Context(),
-				dbgen.InsertTwitchUserPastDecStdDurationParams{
-					ID: p.ID, StdDuration: p.StdDuration})
-			if err != nil {
-				writeInternalError(l, w, err)
-				return
-			}
+			ms["std-duration"] = float64(p.StdDuration)
 		}
+		err = insertMetricSamples(r.Context(), q, al, kt.RequestKindTwitchUserPastDec, p.ID, ms, ts, "")
+		if err != nil {
+			writeInternalError(l, w, err)
+			return
+		}
+
 		writeOK(w)
 	}
 }
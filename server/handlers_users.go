@@ -39,6 +39,9 @@ func handleGetUserMetrics(l *slog.Logger, q *dbgen.Queries) http.HandlerFunc {
 			writeBadRequestError(w, fmt.Errorf("must supply email(s)"))
 			return
 		}
+		if !checkEmailAccess(w, r, email) {
+			return
+		}
 		res, err := q.GetUserMetrics(r.Context(), email)
 		if err != nil {
 			writeInternalError(l, w, err)
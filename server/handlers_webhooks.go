@@ -0,0 +1,416 @@
+package server
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/brojonat/kaggo/server/alerts"
+	"github.com/brojonat/kaggo/server/api"
+	"github.com/brojonat/kaggo/server/db/dbgen"
+	"github.com/brojonat/kaggo/server/db/jsonb"
+	kt "github.com/brojonat/kaggo/temporal/v19700101"
+	"github.com/brojonat/server-tools/stools"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"go.temporal.io/api/enums/v1"
+	"go.temporal.io/api/serviceerror"
+	"go.temporal.io/sdk/client"
+	"go.temporal.io/sdk/temporal"
+)
+
+var webhookEventTypes = []string{
+	api.WebhookEventContentDiscovered,
+	api.WebhookEventScheduleCreated,
+	api.WebhookEventScheduleExpired,
+	api.WebhookEventMetadataRefreshed,
+}
+
+// webhooker fans platform events out to the webhooks of the users tracking the
+// event's entity. Each delivery runs as its own DeliverWebhookWF.
+type webhooker struct {
+	l  *slog.Logger
+	q  *dbgen.Queries
+	tc client.Client
+}
+
+func newWebhooker(l *slog.Logger, q *dbgen.Queries, tc client.Client) *webhooker {
+	return &webhooker{l: l, q: q, tc: tc}
+}
+
+// emit starts a delivery workflow for every webhook subscribed to the event.
+// Errors are logged rather than returned; a broken webhook shouldn't fail the
+// request that produced the event.
+func (wh *webhooker) emit(ctx context.Context, ev api.WebhookEvent) {
+	if ev.EventID == "" {
+		id, err := randomHex(16)
+		if err != nil {
+			wh.l.Error("error generating webhook event id", "error", err.Error())
+			return
+		}
+		ev.EventID = id
+	}
+	if ev.Ts.IsZero() {
+		ev.Ts = time.Now()
+	}
+	hooks, err := wh.q.GetWebhooksForEvent(ctx, dbgen.GetWebhooksForEventParams{
+		EventType:         ev.Type,
+		RequestKind:       ev.RequestKind,
+		ID:                ev.ID,
+		ParentRequestKind: ev.ParentRequestKind,
+		ParentID:          ev.ParentID,
+	})
+	if err != nil {
+		wh.l.Error("error getting webhooks", "type", ev.Type, "request_kind", ev.RequestKind, "id", ev.ID, "error", err.Error())
+		return
+	}
+	if len(hooks) == 0 {
+		return
+	}
+	b, err := json.Marshal(ev)
+	if err != nil {
+		wh.l.Error("error serializing webhook event", "error", err.Error())
+		return
+	}
+	for _, h := range hooks {
+		wfr := kt.DeliverWebhookWFRequest{
+			WebhookID: h.WebhookID,
+			URL:       h.Url,
+			EventID:   ev.EventID,
+			EventType: ev.Type,
+			Body:      b,
+		}
+		wopts := client.StartWorkflowOptions{
+			ID:                    fmt.Sprintf("webhook %d %s", h.WebhookID, ev.EventID),
+			TaskQueue:             os.Getenv("TEMPORAL_TASK_QUEUE"),
+			RetryPolicy:           &temporal.RetryPolicy{MaximumAttempts: 1},
+			WorkflowIDReusePolicy: enums.WORKFLOW_ID_REUSE_POLICY_REJECT_DUPLICATE,
+		}
+		_, err = wh.tc.ExecuteWorkflow(ctx, wopts, kt.DeliverWebhookWF, wfr)
+		if err != nil && !temporal.IsWorkflowExecutionAlreadyStartedError(err) {
+			wh.l.Error("error starting webhook delivery", "webhook_id", h.WebhookID, "event_id", ev.EventID, "error", err.Error())
+		}
+	}
+}
+
+// watchScheduleExpiry starts the workflow that emits schedule.expired once the
+// schedule reaches its EndAt. Schedules without an EndAt never expire. If the
+// schedule is already watched, this is a no-op.
+func (wh *webhooker) watchScheduleExpiry(ctx context.Context, sid string, endAt time.Time) error {
+	if endAt.IsZero() {
+		return nil
+	}
+	wopts := client.StartWorkflowOptions{
+		ID:          fmt.Sprintf("schedule-expiry %s", sid),
+		TaskQueue:   os.Getenv("TEMPORAL_TASK_QUEUE"),
+		RetryPolicy: &temporal.RetryPolicy{MaximumAttempts: 1},
+	}
+	wfr := kt.WatchScheduleExpiryWFRequest{ScheduleID: sid, EndAt: endAt}
+	if _, err := wh.tc.ExecuteWorkflow(ctx, wopts, kt.WatchScheduleExpiryWF, wfr); err != nil {
+		wh.l.Error("error starting schedule expiry watcher", "schedule_id", sid, "error", err.Error())
+		return err
+	}
+	return nil
+}
+
+// signWebhook returns the hex encoded HMAC-SHA256 of "<ts>.<body>".
+func signWebhook(secret string, ts int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	fmt.Fprintf(mac, "%d.", ts)
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func randomHex(n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+func handleGetWebhooks(l *slog.Logger, q *dbgen.Queries) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		email := r.URL.Query().Get("email")
+		if email == "" {
+			writeBadRequestError(w, fmt.Errorf("must supply email"))
+			return
+		}
+		if !checkEmailAccess(w, r, email) {
+			return
+		}
+		res, err := q.GetWebhooks(r.Context(), email)
+		if err != nil {
+			writeInternalError(l, w, err)
+			return
+		}
+		if len(res) == 0 {
+			writeEmptyResultError(w)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(res)
+	}
+}
+
+// Creates a webhook. The response includes the signing secret; this is the
+// only time it's returned, so the caller needs to hang on to it.
+func handleCreateWebhook(l *slog.Logger, q *dbgen.Queries) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var body api.CreateWebhookPayload
+		err := stools.DecodeJSONBody(r, &body)
+		if err != nil {
+			writeBadRequestError(w, err)
+			return
+		}
+		if body.Email == "" {
+			writeBadRequestError(w, fmt.Errorf("must supply email"))
+			return
+		}
+		if !checkEmailAccess(w, r, body.Email) {
+			return
+		}
+		if err = (&alerts.WebhookNotifier{}).ValidateTarget(body.URL); err != nil {
+			writeBadRequestError(w, err)
+			return
+		}
+		events := []string{}
+		for _, e := range body.Events {
+			if !slices.Contains(webhookEventTypes, e) {
+				writeBadRequestError(w, fmt.Errorf("unsupported event type: %s", e))
+				return
+			}
+			events = append(events, e)
+		}
+		secret, err := randomHex(32)
+		if err != nil {
+			writeInternalError(l, w, err)
+			return
+		}
+		res, err := q.InsertWebhook(r.Context(), dbgen.InsertWebhookParams{
+			Email:  body.Email,
+			Url:    body.URL,
+			Secret: secret,
+			Events: events,
+		})
+		if err != nil {
+			if stools.IsPGError(err, stools.PGErrorForeignKeyViolation) {
+				writeBadRequestError(w, fmt.Errorf("unable to create webhook; be sure user (%s) exists", body.Email))
+				return
+			}
+			writeInternalError(l, w, err)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(res)
+	}
+}
+
+func handleDeleteWebhooks(l *slog.Logger, q *dbgen.Queries) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		email := r.URL.Query().Get("email")
+		if email == "" {
+			writeBadRequestError(w, fmt.Errorf("must supply email"))
+			return
+		}
+		if !checkEmailAccess(w, r, email) {
+			return
+		}
+		ids := []int64{}
+		for _, s := range r.URL.Query()["webhook_id"] {
+			id, err := strconv.ParseInt(s, 10, 64)
+			if err != nil {
+				writeBadRequestError(w, fmt.Errorf("bad webhook_id: %s", s))
+				return
+			}
+			ids = append(ids, id)
+		}
+		if len(ids) == 0 {
+			writeBadRequestError(w, fmt.Errorf("must supply webhook_id(s)"))
+			return
+		}
+		err := q.DeleteWebhooks(r.Context(), dbgen.DeleteWebhooksParams{Email: email, WebhookIds: ids})
+		if err != nil {
+			writeInternalError(l, w, err)
+			return
+		}
+		writeOK(w)
+	}
+}
+
+// Returns the events that couldn't be delivered to the user's webhooks, most
+// recent first. The history can be truncated with dur (e.g., 7d); it defaults
+// to the past week.
+func handleGetWebhookDeadLetters(l *slog.Logger, q *dbgen.Queries) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		email := r.URL.Query().Get("email")
+		if email == "" {
+			writeBadRequestError(w, fmt.Errorf("must supply email"))
+			return
+		}
+		if !checkEmailAccess(w, r, email) {
+			return
+		}
+		dur := 7 * 24 * time.Hour
+		if s := r.URL.Query().Get("dur"); s != "" {
+			d, err := parseDuration(s)
+			if err != nil {
+				writeBadRequestError(w, fmt.Errorf("could not parse duration: %w", err))
+				return
+			}
+			dur = d
+		}
+		res, err := q.GetWebhookDeadLetters(r.Context(), dbgen.GetWebhookDeadLettersParams{
+			Email:   email,
+			TsStart: pgtype.Timestamptz{Time: time.Now().Add(-dur), Valid: true},
+		})
+		if err != nil {
+			writeInternalError(l, w, err)
+			return
+		}
+		if len(res) == 0 {
+			writeEmptyResultError(w)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(res)
+	}
+}
+
+// Records an event that DeliverWebhookWF gave up on.
+// Called by the DeliverWebhook activity before each delivery attempt. The body
+// is signed with the current time, so receivers can reject stale timestamps
+// without rejecting retries, and the secret never leaves the server.
+func handleSignWebhook(l *slog.Logger, q *dbgen.Queries) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var body api.WebhookSignPayload
+		err := stools.DecodeJSONBody(r, &body)
+		if err != nil {
+			writeBadRequestError(w, err)
+			return
+		}
+		secret, err := q.GetWebhookSecret(r.Context(), body.WebhookID)
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				w.WriteHeader(http.StatusOK)
+				json.NewEncoder(w).Encode(api.WebhookSignResponse{Gone: true})
+				return
+			}
+			writeInternalError(l, w, err)
+			return
+		}
+		ts := time.Now().Unix()
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(api.WebhookSignResponse{
+			Timestamp: ts,
+			Signature: signWebhook(secret, ts, body.Body),
+		})
+	}
+}
+
+func handlePostWebhookDeadLetter(l *slog.Logger, q *dbgen.Queries) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var body api.WebhookDeadLetterPayload
+		err := stools.DecodeJSONBody(r, &body)
+		if err != nil {
+			writeBadRequestError(w, err)
+			return
+		}
+		err = q.InsertWebhookDeadLetter(r.Context(), dbgen.InsertWebhookDeadLetterParams{
+			WebhookID: body.WebhookID,
+			EventID:   body.EventID,
+			EventType: body.EventType,
+			Payload:   body.Payload,
+			Error:     body.Error,
+		})
+		if err != nil {
+			// the webhook may have been deleted while we were retrying
+			if stools.IsPGError(err, stools.PGErrorForeignKeyViolation) {
+				writeOK(w)
+				return
+			}
+			writeInternalError(l, w, err)
+			return
+		}
+		writeOK(w)
+	}
+}
+
+// Called by WatchScheduleExpiryWF once a schedule should have reached its
+// EndAt. If the schedule was extended, the new EndAt is returned so the
+// watcher can keep waiting; otherwise the schedule.expired event is emitted.
+// Deleted schedules (and schedules whose EndAt was removed) never expire.
+// Expired schedules have their entity marked as ended.
+func handleScheduleExpired(l *slog.Logger, q *dbgen.Queries, tc client.Client, wh *webhooker) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var body api.ScheduleExpiryPayload
+		err := stools.DecodeJSONBody(r, &body)
+		if err != nil {
+			writeBadRequestError(w, err)
+			return
+		}
+		desc, err := tc.ScheduleClient().GetHandle(r.Context(), body.ScheduleID).Describe(r.Context())
+		if err != nil {
+			var nf *serviceerror.NotFound
+			if errors.As(err, &nf) {
+				w.WriteHeader(http.StatusOK)
+				json.NewEncoder(w).Encode(api.ScheduleExpiryResponse{Done: true})
+				return
+			}
+			writeInternalError(l, w, err)
+			return
+		}
+		endAt := desc.Schedule.Spec.EndAt
+		if endAt.IsZero() {
+			w.WriteHeader(http.StatusOK)
+			json.NewEncoder(w).Encode(api.ScheduleExpiryResponse{Done: true})
+			return
+		}
+		if endAt.After(time.Now()) {
+			w.WriteHeader(http.StatusOK)
+			json.NewEncoder(w).Encode(api.ScheduleExpiryResponse{EndAt: endAt})
+			return
+		}
+
+		a := scheduleSearchAttributes(body.ScheduleID, desc.SearchAttributes)
+		if a.RequestKind == "" {
+			writeBadRequestError(w, fmt.Errorf("unexpected schedule id: %s", body.ScheduleID))
+			return
+		}
+		_, err = q.SetMetadataStatus(r.Context(), dbgen.SetMetadataStatusParams{
+			Status:       jsonb.MetadataStatusEnded,
+			StatusReason: "expired",
+			TsEnded:      pgtype.Timestamptz{Time: endAt, Valid: true},
+			RequestKind:  a.RequestKind,
+			ID:           a.EntityID,
+		})
+		if err != nil {
+			writeInternalError(l, w, err)
+			return
+		}
+		// The event ID is derived from the schedule so a retried check doesn't
+		// deliver the event twice.
+		eid := sha256.Sum256([]byte(fmt.Sprintf("%s %d", body.ScheduleID, endAt.Unix())))
+		wh.emit(r.Context(), api.WebhookEvent{
+			EventID:     hex.EncodeToString(eid[:16]),
+			Type:        api.WebhookEventScheduleExpired,
+			Ts:          endAt,
+			RequestKind: a.RequestKind,
+			ID:          a.EntityID,
+			ScheduleID:  body.ScheduleID,
+		})
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(api.ScheduleExpiryResponse{Done: true})
+	}
+}
@@ -0,0 +1,120 @@
+package server
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"slices"
+	"strconv"
+
+	"github.com/brojonat/kaggo/server/api"
+	kt "github.com/brojonat/kaggo/temporal/v19700101"
+	"go.temporal.io/api/workflowservice/v1"
+	"go.temporal.io/sdk/client"
+	"go.temporal.io/sdk/converter"
+)
+
+const (
+	defaultPageSize = 100
+	maxPageSize     = 1000
+)
+
+// the ExecutionStatus values accepted by GET /workflow
+var workflowStatuses = []string{
+	"Running", "Completed", "Failed", "Canceled", "Terminated", "ContinuedAsNew", "TimedOut",
+}
+
+// parsePage parses the page_size (default 100, at most 1000) and page_token
+// (the next_page_token of the previous page) parameters of list endpoints.
+func parsePage(r *http.Request) (int32, []byte, error) {
+	size := defaultPageSize
+	if s := r.URL.Query().Get("page_size"); s != "" {
+		var err error
+		if size, err = strconv.Atoi(s); err != nil || size < 1 || size > maxPageSize {
+			return 0, nil, fmt.Errorf("page_size must be between 1 and %d", maxPageSize)
+		}
+	}
+	token, err := base64.RawURLEncoding.DecodeString(r.URL.Query().Get("page_token"))
+	if err != nil {
+		return 0, nil, fmt.Errorf("bad page_token: %w", err)
+	}
+	return int32(size), token, nil
+}
+
+func encodePageToken(token []byte) string {
+	return base64.RawURLEncoding.EncodeToString(token)
+}
+
+// searchAttributesFilter reads the search attribute filters of list endpoints.
+func searchAttributesFilter(r *http.Request) kt.SearchAttributes {
+	return kt.SearchAttributes{
+		RequestKind: r.URL.Query().Get("request_kind"),
+		EntityID:    r.URL.Query().Get("id"),
+		OwnerEmail:  r.URL.Query().Get("owner_email"),
+		Tier:        r.URL.Query().Get("tier"),
+	}
+}
+
+// Lists workflow executions a page at a time (see parsePage), most recent
+// first. Filter with request_kind, id, owner_email, tier and status (e.g.,
+// Running); the filters are sent to Temporal as a visibility query.
+func handleListWorkflows(l *slog.Logger, tc client.Client) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		size, token, err := parsePage(r)
+		if err != nil {
+			writeBadRequestError(w, err)
+			return
+		}
+		query := searchAttributesFilter(r).Query()
+		if status := r.URL.Query().Get("status"); status != "" {
+			if !slices.Contains(workflowStatuses, status) {
+				writeBadRequestError(w, fmt.Errorf("unsupported status %s", status))
+				return
+			}
+			if query != "" {
+				query += " AND "
+			}
+			query += fmt.Sprintf("ExecutionStatus = '%s'", status)
+		}
+
+		ls, err := tc.ListWorkflow(r.Context(), &workflowservice.ListWorkflowExecutionsRequest{
+			Namespace:     client.DefaultNamespace,
+			PageSize:      size,
+			NextPageToken: token,
+			Query:         query,
+		})
+		if err != nil {
+			writeBadRequestError(w, err)
+			return
+		}
+		res := api.WorkflowListResponse{
+			Workflows:     []api.WorkflowListEntry{},
+			NextPageToken: encodePageToken(ls.NextPageToken),
+		}
+		for _, e := range ls.Executions {
+			a := kt.DecodeSearchAttributes(e.SearchAttributes)
+			wf := api.WorkflowListEntry{
+				WorkflowID:   e.GetExecution().GetWorkflowId(),
+				RunID:        e.GetExecution().GetRunId(),
+				WorkflowType: e.GetType().GetName(),
+				Status:       e.Status.String(),
+				CloseTime:    e.CloseTime,
+				RequestKind:  a.RequestKind,
+				EntityID:     a.EntityID,
+				OwnerEmail:   a.OwnerEmail,
+				Tier:         a.Tier,
+			}
+			if e.StartTime != nil {
+				wf.StartTime = *e.StartTime
+			}
+			if p := e.GetSearchAttributes().GetIndexedFields()["TemporalScheduledById"]; p != nil {
+				converter.GetDefaultDataConverter().FromPayload(p, &wf.ScheduleID)
+			}
+			res.Workflows = append(res.Workflows, wf)
+		}
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(res)
+	}
+}
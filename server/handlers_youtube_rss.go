@@ -98,6 +98,12 @@ func handleYouTubeVideoWebSubNotification(l *slog.Logger, q *dbgen.Queries) http
 			return
 		}
 		vid := matches[vidIdx]
+		// the channel is only used to notify its watchers, so it's optional
+		var cid string
+		cre := regexp.MustCompile(`<yt:channelId>(?P<cid>.+)</yt:channelId>`)
+		if cm := cre.FindStringSubmatch(xmlstr); len(cm) > 0 {
+			cid = cm[cre.SubexpIndex("cid")]
+		}
 
 		// We have a local cache to deal with duplicate notifications. This
 		// doesn't have to be perfect, but it'll get us 90% of the way there.
@@ -111,12 +117,14 @@ func handleYouTubeVideoWebSubNotification(l *slog.Logger, q *dbgen.Queries) http
 
 		l.Info("got new youtube video to monitor", "id", vid)
 		// we want to follow this post for some nominal amount of time
-		rk := "youtube.video"
-		sched := kt.GetDefaultScheduleSpec(rk, vid)
 		payload := api.GenericScheduleRequestPayload{
-			RequestKind: rk,
+			RequestKind: kt.RequestKindYouTubeVideo,
 			ID:          vid,
-			Schedule:    sched,
+			Preset:      kt.SchedulePresetDefault,
+		}
+		if cid != "" {
+			payload.ParentRequestKind = kt.RequestKindYouTubeChannel
+			payload.ParentID = cid
 		}
 		b, err = json.Marshal(payload)
 		if err != nil {
@@ -3,15 +3,18 @@ package server
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log/slog"
 	"net/http"
 	"strings"
 
 	"github.com/brojonat/kaggo/server/api"
+	"github.com/brojonat/kaggo/server/db/dbgen"
 	"github.com/brojonat/server-tools/stools"
 	"github.com/golang-jwt/jwt"
 	"github.com/gorilla/handlers"
+	"github.com/jackc/pgx/v5"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/urfave/negroni"
 )
@@ -22,6 +25,7 @@ type contextKey int
 
 var ctxKeyJWT contextKey = 1
 var ctxKeyEmail contextKey = 2
+var ctxKeyAPIKey contextKey = 3
 
 // Convenience middleware that applies commonly used middleware to the wrapped
 // handler. This will make the handler gracefully handle panics, sets the
@@ -121,6 +125,34 @@ func bearerAuthorizerCtxSetToken(gsk func() string) func(http.ResponseWriter, *h
 	}
 }
 
+// Authorizes requests that present an active API key, either as the bearer
+// token or in the X-API-Key header. Successful requests bump the key's last
+// used timestamp.
+func apiKeyAuthorizerCtxSetKey(l *slog.Logger, q *dbgen.Queries) func(http.ResponseWriter, *http.Request) bool {
+	return func(w http.ResponseWriter, r *http.Request) bool {
+		key := r.Header.Get(APIKeyHeader)
+		if key == "" {
+			key = strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+		}
+		if !strings.HasPrefix(key, apiKeyPrefix) {
+			return false
+		}
+		k, err := q.GetActiveAPIKeyByHash(r.Context(), hashAPIKey(key))
+		if err != nil {
+			if !errors.Is(err, pgx.ErrNoRows) {
+				l.Error("error looking up api key", "error", err.Error())
+			}
+			return false
+		}
+		if err = q.SetAPIKeyLastUsed(r.Context(), k.KeyID); err != nil {
+			l.Error("error setting api key last used", "key_id", k.KeyID, "error", err.Error())
+		}
+		ctx := context.WithValue(r.Context(), ctxKeyAPIKey, &k)
+		*r = *r.WithContext(ctx)
+		return true
+	}
+}
+
 // Iterates over the supplied authorizers and if at least one passes, then the
 // next handler is called, otherwise an unauthorized response is written.
 func atLeastOneAuth(authorizers ...func(http.ResponseWriter, *http.Request) bool) stools.HandlerAdapter {
@@ -139,6 +171,27 @@ func atLeastOneAuth(authorizers ...func(http.ResponseWriter, *http.Request) bool
 	}
 }
 
+// Rejects callers that don't have the scope (see getCaller). This needs the
+// caller identity, so it must be listed before (i.e., be wrapped by)
+// atLeastOneAuth.
+func requireScope(scope string) stools.HandlerAdapter {
+	return func(next http.HandlerFunc) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			c, ok := getCaller(r)
+			if !ok {
+				w.WriteHeader(http.StatusUnauthorized)
+				json.NewEncoder(w).Encode(api.DefaultJSONResponse{Error: "unauthorized"})
+				return
+			}
+			if !c.hasScope(scope) {
+				writeForbiddenError(w)
+				return
+			}
+			next(w, r)
+		}
+	}
+}
+
 // Increment a prometheus counter for each request
 func withPromCounter(pm *prometheus.CounterVec) stools.HandlerAdapter {
 	return func(hf http.HandlerFunc) http.HandlerFunc {
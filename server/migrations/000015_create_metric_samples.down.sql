@@ -0,0 +1,4 @@
+BEGIN;
+-- NOTE: samples written after the up migration only exist in metric_samples
+DROP TABLE IF EXISTS metric_samples;
+COMMIT;
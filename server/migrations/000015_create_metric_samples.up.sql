@@ -0,0 +1,106 @@
+BEGIN;
+
+-- A single narrow table for every metric sample. The metric column holds the
+-- short metric name (e.g., "score"); the fully qualified name reported to
+-- clients is request_kind || '.' || metric (e.g., "reddit.post.score").
+CREATE TABLE IF NOT EXISTS metric_samples (
+    request_kind VARCHAR(255) NOT NULL,
+    id VARCHAR(255) NOT NULL,
+    metric VARCHAR(255) NOT NULL,
+    ts TIMESTAMPTZ NOT NULL,
+    value DOUBLE PRECISION NOT NULL
+);
+SELECT create_hypertable('metric_samples', 'ts', if_not_exists => TRUE);
+CREATE INDEX IF NOT EXISTS metric_samples_rk_id_ts ON metric_samples (request_kind, id, ts);
+CREATE INDEX IF NOT EXISTS metric_samples_rk_id_metric_ts ON metric_samples (request_kind, id, metric, ts);
+
+-- backfill from the per-metric tables; these are left in place
+INSERT INTO metric_samples (request_kind, id, metric, ts, value)
+SELECT 'internal.random', id, 'value', ts, val::DOUBLE PRECISION FROM internal_random;
+-- Migration 000001 creates the Kaggle tables with a val column, but the app
+-- has always written votes/views/downloads columns, so deployed databases may
+-- have either; read whichever one the table has.
+DO $$
+DECLARE
+    src RECORD;
+    col TEXT;
+BEGIN
+    FOR src IN SELECT * FROM (VALUES
+        ('kaggle_notebook_votes', 'kaggle.notebook', 'votes'),
+        ('kaggle_dataset_votes', 'kaggle.dataset', 'votes'),
+        ('kaggle_dataset_views', 'kaggle.dataset', 'views'),
+        ('kaggle_dataset_downloads', 'kaggle.dataset', 'downloads')
+    ) AS t (tbl, request_kind, metric)
+    LOOP
+        SELECT column_name INTO col
+        FROM information_schema.columns
+        WHERE table_schema = current_schema()
+            AND table_name = src.tbl
+            AND column_name IN ('val', src.metric)
+        ORDER BY column_name = 'val' DESC
+        LIMIT 1;
+        IF col IS NULL THEN
+            RAISE EXCEPTION 'table % has neither a val nor a % column', src.tbl, src.metric;
+        END IF;
+        EXECUTE format(
+            'INSERT INTO metric_samples (request_kind, id, metric, ts, value)
+            SELECT %L, id, %L, ts, %I::DOUBLE PRECISION FROM %I',
+            src.request_kind, src.metric, col, src.tbl
+        );
+    END LOOP;
+END
+$$;
+INSERT INTO metric_samples (request_kind, id, metric, ts, value)
+SELECT 'youtube.video', id, 'views', ts, views::DOUBLE PRECISION FROM youtube_video_views;
+INSERT INTO metric_samples (request_kind, id, metric, ts, value)
+SELECT 'youtube.video', id, 'likes', ts, likes::DOUBLE PRECISION FROM youtube_video_likes;
+INSERT INTO metric_samples (request_kind, id, metric, ts, value)
+SELECT 'youtube.video', id, 'comments', ts, comments::DOUBLE PRECISION FROM youtube_video_comments;
+INSERT INTO metric_samples (request_kind, id, metric, ts, value)
+SELECT 'youtube.channel', id, 'views', ts, views::DOUBLE PRECISION FROM youtube_channel_views;
+INSERT INTO metric_samples (request_kind, id, metric, ts, value)
+SELECT 'youtube.channel', id, 'subscribers', ts, subscribers::DOUBLE PRECISION FROM youtube_channel_subscribers;
+INSERT INTO metric_samples (request_kind, id, metric, ts, value)
+SELECT 'youtube.channel', id, 'videos', ts, videos::DOUBLE PRECISION FROM youtube_channel_videos;
+INSERT INTO metric_samples (request_kind, id, metric, ts, value)
+SELECT 'reddit.post', id, 'score', ts, score::DOUBLE PRECISION FROM reddit_post_score;
+INSERT INTO metric_samples (request_kind, id, metric, ts, value)
+SELECT 'reddit.post', id, 'ratio', ts, ratio::DOUBLE PRECISION FROM reddit_post_ratio;
+INSERT INTO metric_samples (request_kind, id, metric, ts, value)
+SELECT 'reddit.comment', id, 'score', ts, score::DOUBLE PRECISION FROM reddit_comment_score;
+INSERT INTO metric_samples (request_kind, id, metric, ts, value)
+SELECT 'reddit.comment', id, 'controversiality', ts, controversiality::DOUBLE PRECISION FROM reddit_comment_controversiality;
+INSERT INTO metric_samples (request_kind, id, metric, ts, value)
+SELECT 'reddit.subreddit', id, 'subscribers', ts, subscribers::DOUBLE PRECISION FROM reddit_subreddit_subscribers;
+INSERT INTO metric_samples (request_kind, id, metric, ts, value)
+SELECT 'reddit.subreddit', id, 'active-user-count', ts, active_user_count::DOUBLE PRECISION FROM reddit_subreddit_active_user_count;
+INSERT INTO metric_samples (request_kind, id, metric, ts, value)
+SELECT 'reddit.user', id, 'awardee-karma', ts, karma::DOUBLE PRECISION FROM reddit_user_awardee_karma;
+INSERT INTO metric_samples (request_kind, id, metric, ts, value)
+SELECT 'reddit.user', id, 'awarder-karma', ts, karma::DOUBLE PRECISION FROM reddit_user_awarder_karma;
+INSERT INTO metric_samples (request_kind, id, metric, ts, value)
+SELECT 'reddit.user', id, 'comment-karma', ts, karma::DOUBLE PRECISION FROM reddit_user_comment_karma;
+INSERT INTO metric_samples (request_kind, id, metric, ts, value)
+SELECT 'reddit.user', id, 'link-karma', ts, karma::DOUBLE PRECISION FROM reddit_user_link_karma;
+INSERT INTO metric_samples (request_kind, id, metric, ts, value)
+SELECT 'reddit.user', id, 'total-karma', ts, karma::DOUBLE PRECISION FROM reddit_user_total_karma;
+INSERT INTO metric_samples (request_kind, id, metric, ts, value)
+SELECT 'twitch.clip', id, 'views', ts, views::DOUBLE PRECISION FROM twitch_clip_views;
+INSERT INTO metric_samples (request_kind, id, metric, ts, value)
+SELECT 'twitch.video', id, 'views', ts, views::DOUBLE PRECISION FROM twitch_video_views;
+INSERT INTO metric_samples (request_kind, id, metric, ts, value)
+SELECT 'twitch.stream', id, 'views', ts, views::DOUBLE PRECISION FROM twitch_stream_views;
+INSERT INTO metric_samples (request_kind, id, metric, ts, value)
+SELECT 'twitch.user-past-dec', id, 'avg-views', ts, avg_views::DOUBLE PRECISION FROM twitch_user_past_dec_avg_views;
+INSERT INTO metric_samples (request_kind, id, metric, ts, value)
+SELECT 'twitch.user-past-dec', id, 'med-views', ts, med_views::DOUBLE PRECISION FROM twitch_user_past_dec_med_views;
+INSERT INTO metric_samples (request_kind, id, metric, ts, value)
+SELECT 'twitch.user-past-dec', id, 'std-views', ts, std_views::DOUBLE PRECISION FROM twitch_user_past_dec_std_views;
+INSERT INTO metric_samples (request_kind, id, metric, ts, value)
+SELECT 'twitch.user-past-dec', id, 'avg-duration', ts, avg_duration::DOUBLE PRECISION FROM twitch_user_past_dec_avg_duration;
+INSERT INTO metric_samples (request_kind, id, metric, ts, value)
+SELECT 'twitch.user-past-dec', id, 'med-duration', ts, med_duration::DOUBLE PRECISION FROM twitch_user_past_dec_med_duration;
+INSERT INTO metric_samples (request_kind, id, metric, ts, value)
+SELECT 'twitch.user-past-dec', id, 'std-duration', ts, std_duration::DOUBLE PRECISION FROM twitch_user_past_dec_std_duration;
+
+COMMIT;
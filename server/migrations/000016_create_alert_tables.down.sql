@@ -0,0 +1,4 @@
+BEGIN;
+DROP TABLE IF EXISTS alert_history;
+DROP TABLE IF EXISTS alert_rules;
+COMMIT;
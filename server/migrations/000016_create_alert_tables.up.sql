@@ -0,0 +1,39 @@
+BEGIN;
+
+-- Per user alert rules on a single (request_kind, id, metric). The condition
+-- is one of threshold, pct-change, or z-score and its parameters live in
+-- params (see jsonb.AlertRuleParams).
+CREATE TABLE IF NOT EXISTS alert_rules (
+    rule_id BIGSERIAL PRIMARY KEY,
+    email VARCHAR(255) NOT NULL,
+    request_kind VARCHAR(255) NOT NULL,
+    id VARCHAR(255) NOT NULL,
+    metric VARCHAR(255) NOT NULL,
+    condition VARCHAR(255) NOT NULL,
+    params JSONB NOT NULL DEFAULT '{}'::JSONB,
+    notifier VARCHAR(255) NOT NULL,
+    target VARCHAR(1024) NOT NULL,
+    cooldown_seconds INTEGER NOT NULL DEFAULT 3600,
+    enabled BOOLEAN NOT NULL DEFAULT TRUE,
+    ts_created TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    ts_last_fired TIMESTAMPTZ
+);
+ALTER TABLE alert_rules
+ADD FOREIGN KEY (email) REFERENCES users ON DELETE CASCADE;
+CREATE INDEX IF NOT EXISTS alert_rules_rk_id_metric ON alert_rules (request_kind, LOWER(id), metric);
+CREATE INDEX IF NOT EXISTS alert_rules_email ON alert_rules (email);
+
+-- Every time a rule fires it's recorded here along with the delivery error
+-- (if any).
+CREATE TABLE IF NOT EXISTS alert_history (
+    rule_id BIGINT NOT NULL,
+    ts TIMESTAMPTZ NOT NULL,
+    value DOUBLE PRECISION NOT NULL,
+    message TEXT NOT NULL,
+    error TEXT NOT NULL DEFAULT ''
+);
+ALTER TABLE alert_history
+ADD FOREIGN KEY (rule_id) REFERENCES alert_rules ON DELETE CASCADE;
+CREATE INDEX IF NOT EXISTS alert_history_rule_id_ts ON alert_history (rule_id, ts);
+
+COMMIT;
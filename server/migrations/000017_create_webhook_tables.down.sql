@@ -0,0 +1,4 @@
+BEGIN;
+DROP TABLE IF EXISTS webhook_dead_letters;
+DROP TABLE IF EXISTS webhooks;
+COMMIT;
@@ -0,0 +1,31 @@
+BEGIN;
+
+-- Per user webhook endpoints. Events are signed with secret; an empty events
+-- array subscribes the endpoint to every event type.
+CREATE TABLE IF NOT EXISTS webhooks (
+    webhook_id BIGSERIAL PRIMARY KEY,
+    email VARCHAR(255) NOT NULL,
+    url VARCHAR(1024) NOT NULL,
+    secret VARCHAR(255) NOT NULL,
+    events VARCHAR(255)[] NOT NULL DEFAULT '{}',
+    enabled BOOLEAN NOT NULL DEFAULT TRUE,
+    ts_created TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+ALTER TABLE webhooks
+ADD FOREIGN KEY (email) REFERENCES users ON DELETE CASCADE;
+CREATE INDEX IF NOT EXISTS webhooks_email ON webhooks (email);
+
+-- Events that couldn't be delivered after exhausting all retries.
+CREATE TABLE IF NOT EXISTS webhook_dead_letters (
+    webhook_id BIGINT NOT NULL,
+    event_id VARCHAR(255) NOT NULL,
+    event_type VARCHAR(255) NOT NULL,
+    payload TEXT NOT NULL,
+    error TEXT NOT NULL,
+    ts TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+ALTER TABLE webhook_dead_letters
+ADD FOREIGN KEY (webhook_id) REFERENCES webhooks ON DELETE CASCADE;
+CREATE INDEX IF NOT EXISTS webhook_dead_letters_webhook_id_ts ON webhook_dead_letters (webhook_id, ts);
+
+COMMIT;
@@ -0,0 +1,22 @@
+BEGIN;
+
+-- API keys are stored as the hex SHA-256 of the key; the key itself is only
+-- shown once when it's created. The prefix is kept so keys can be told apart
+-- in listings.
+CREATE TABLE IF NOT EXISTS api_keys (
+    key_id BIGSERIAL PRIMARY KEY,
+    email VARCHAR(255) NOT NULL,
+    name VARCHAR(255) NOT NULL DEFAULT '',
+    key_prefix VARCHAR(16) NOT NULL,
+    key_hash VARCHAR(64) NOT NULL UNIQUE,
+    scopes VARCHAR(255)[] NOT NULL DEFAULT '{}',
+    ts_created TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    ts_expires TIMESTAMPTZ,
+    ts_last_used TIMESTAMPTZ,
+    ts_revoked TIMESTAMPTZ
+);
+ALTER TABLE api_keys
+ADD FOREIGN KEY (email) REFERENCES users ON DELETE CASCADE;
+CREATE INDEX IF NOT EXISTS api_keys_email ON api_keys (email);
+
+COMMIT;
@@ -0,0 +1,19 @@
+BEGIN;
+
+-- One token bucket per provider credential, shared by every worker. The
+-- bucket is fed by the rate limit headers on provider responses and drawn
+-- down before each request (see server/ratelimit.go).
+CREATE TABLE IF NOT EXISTS rate_limit_buckets (
+    bucket_key VARCHAR(255) PRIMARY KEY,
+    capacity DOUBLE PRECISION NOT NULL,
+    tokens DOUBLE PRECISION NOT NULL,
+    refill_rate DOUBLE PRECISION NOT NULL,
+    ts_updated TIMESTAMPTZ NOT NULL,
+    ts_reset TIMESTAMPTZ NOT NULL,
+    delayed BIGINT NOT NULL DEFAULT 0,
+    skipped BIGINT NOT NULL DEFAULT 0,
+    last_reason TEXT NOT NULL DEFAULT '',
+    ts_last_limited TIMESTAMPTZ
+);
+
+COMMIT;
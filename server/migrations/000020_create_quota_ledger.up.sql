@@ -0,0 +1,14 @@
+BEGIN;
+
+-- Units spent per API key per quota period, shared by every worker. Keys are
+-- only stored as digests. YouTube's quota is a daily budget per key that
+-- resets at midnight Pacific time.
+CREATE TABLE IF NOT EXISTS quota_ledger (
+    quota_key VARCHAR(255) NOT NULL,
+    period DATE NOT NULL,
+    spent BIGINT NOT NULL DEFAULT 0,
+    ts_updated TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (quota_key, period)
+);
+
+COMMIT;
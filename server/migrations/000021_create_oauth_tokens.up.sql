@@ -0,0 +1,14 @@
+BEGIN;
+
+-- OAuth access tokens shared by the worker replicas so they don't each fetch
+-- their own (see temporal/v19700101/credentials.go). These are live, if
+-- short lived, credentials. Tokens are keyed by a digest of the credentials
+-- they were issued for.
+CREATE TABLE IF NOT EXISTS oauth_tokens (
+    token_key VARCHAR(255) PRIMARY KEY,
+    access_token TEXT NOT NULL,
+    ts_expires TIMESTAMPTZ NOT NULL,
+    ts_updated TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+COMMIT;
@@ -0,0 +1,4 @@
+BEGIN;
+DROP INDEX IF EXISTS metric_samples_idempotency;
+ALTER TABLE metric_samples DROP COLUMN IF EXISTS idempotency_key;
+COMMIT;
@@ -0,0 +1,13 @@
+BEGIN;
+
+-- Uploads from the worker carry an idempotency key and the time the samples
+-- were observed, neither of which change when the upload is retried, so a
+-- retried upload conflicts with the samples it already wrote and is dropped.
+-- Samples without a key (e.g., from before this migration) never conflict.
+-- The index has to include ts because metric_samples is a hypertable. The
+-- legacy per-metric tables aren't written to anymore, so they're left alone.
+ALTER TABLE metric_samples ADD COLUMN IF NOT EXISTS idempotency_key VARCHAR(255);
+CREATE UNIQUE INDEX IF NOT EXISTS metric_samples_idempotency
+    ON metric_samples (request_kind, id, metric, ts, idempotency_key);
+
+COMMIT;
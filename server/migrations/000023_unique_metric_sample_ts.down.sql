@@ -0,0 +1,5 @@
+BEGIN;
+DROP INDEX IF EXISTS metric_samples_sample;
+CREATE UNIQUE INDEX IF NOT EXISTS metric_samples_idempotency
+    ON metric_samples (request_kind, id, metric, ts, idempotency_key);
+COMMIT;
@@ -0,0 +1,22 @@
+BEGIN;
+
+-- Samples from the worker are stamped with the time their response was
+-- fetched, so a sample is identified by (request_kind, id, metric, ts) alone.
+-- Keying on the idempotency key too let a backfill of an archived response
+-- (keyed by archive key) write a second copy of every sample that was already
+-- ingested live (keyed by the upload activity). Drop any such copies, keeping
+-- one of each; rows with the same ts are in the same chunk, so comparing ctids
+-- is safe. The idempotency key is still recorded.
+DELETE FROM metric_samples a
+USING metric_samples b
+WHERE a.request_kind = b.request_kind
+    AND a.id = b.id
+    AND a.metric = b.metric
+    AND a.ts = b.ts
+    AND a.ctid > b.ctid;
+
+DROP INDEX IF EXISTS metric_samples_idempotency;
+CREATE UNIQUE INDEX IF NOT EXISTS metric_samples_sample
+    ON metric_samples (request_kind, id, metric, ts);
+
+COMMIT;
@@ -0,0 +1,356 @@
+// Package schedspec evaluates Temporal schedule specs (client.ScheduleSpec)
+// without asking the Temporal server: when a schedule runs next, and when it
+// runs over a period. It follows the server's semantics for calendars,
+// intervals, skips, start/end bounds and time zones. Runs are reported at
+// their nominal times (i.e., without jitter), and cron expressions aren't
+// supported; the server compiles those to calendars when the schedule is
+// created, so specs read back from the server don't have any.
+package schedspec
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	schedulepb "go.temporal.io/api/schedule/v1"
+	"go.temporal.io/sdk/client"
+)
+
+var ErrCronUnsupported = errors.New("cron expressions aren't supported")
+
+const (
+	// how many days ahead to look for a calendar's next run
+	maxSearchDays = 5 * 366
+	// how many skipped runs in a row to step over before giving up
+	maxSkipped = 100000
+)
+
+// Spec is a compiled client.ScheduleSpec.
+type Spec struct {
+	loc        *time.Location
+	calendars  []calendar
+	skips      []calendar
+	intervals  []client.ScheduleIntervalSpec
+	start, end time.Time
+	jitter     time.Duration
+}
+
+// calendar is a compiled client.ScheduleCalendarSpec.
+type calendar struct {
+	// seconds of the day, sorted
+	secs  []int
+	dom   [32]bool
+	month [13]bool
+	dow   [7]bool
+	years []client.ScheduleRange
+}
+
+// New compiles the spec.
+func New(s client.ScheduleSpec) (*Spec, error) {
+	if len(s.CronExpressions) > 0 {
+		return nil, ErrCronUnsupported
+	}
+	loc := time.UTC
+	if s.TimeZoneName != "" {
+		var err error
+		if loc, err = time.LoadLocation(s.TimeZoneName); err != nil {
+			return nil, fmt.Errorf("bad time zone: %w", err)
+		}
+	}
+	for _, i := range s.Intervals {
+		if i.Every <= 0 {
+			return nil, fmt.Errorf("interval must be positive")
+		}
+	}
+	sp := &Spec{loc: loc, intervals: s.Intervals, start: s.StartAt, end: s.EndAt, jitter: s.Jitter}
+	for _, c := range s.Calendars {
+		sp.calendars = append(sp.calendars, compileCalendar(c))
+	}
+	for _, c := range s.Skip {
+		sp.skips = append(sp.skips, compileCalendar(c))
+	}
+	return sp, nil
+}
+
+// MustNew is like New but panics on error.
+func MustNew(s client.ScheduleSpec) *Spec {
+	sp, err := New(s)
+	if err != nil {
+		panic(err)
+	}
+	return sp
+}
+
+// Jitter returns the spec's jitter; each run happens up to this much after
+// its nominal time.
+func (s *Spec) Jitter() time.Duration {
+	return s.jitter
+}
+
+// Next returns the first run strictly after the supplied time, or the zero
+// time if the schedule doesn't run again.
+func (s *Spec) Next(after time.Time) time.Time {
+	if !s.start.IsZero() && after.Before(s.start) {
+		after = s.start.Add(-time.Nanosecond)
+	}
+	for range maxSkipped {
+		var next time.Time
+		for _, c := range s.calendars {
+			if t := c.next(after, s.loc); !t.IsZero() && (next.IsZero() || t.Before(next)) {
+				next = t
+			}
+		}
+		for _, i := range s.intervals {
+			if t := intervalNext(i, after); next.IsZero() || t.Before(next) {
+				next = t
+			}
+		}
+		if next.IsZero() || (!s.end.IsZero() && next.After(s.end)) {
+			return time.Time{}
+		}
+		if !s.skipped(next) {
+			return next
+		}
+		after = next
+	}
+	return time.Time{}
+}
+
+// Runs returns the runs in [start, end], at most limit of them (no limit if
+// limit <= 0).
+func (s *Spec) Runs(start, end time.Time, limit int) []time.Time {
+	ts := []time.Time{}
+	for t := s.Next(start.Add(-time.Nanosecond)); !t.IsZero() && !t.After(end); t = s.Next(t) {
+		ts = append(ts, t)
+		if limit > 0 && len(ts) >= limit {
+			break
+		}
+	}
+	return ts
+}
+
+// Histogram counts the runs in each of the n buckets of width bucket starting
+// at start.
+func (s *Spec) Histogram(start time.Time, bucket time.Duration, n int) []int {
+	counts := make([]int, n)
+	end := start.Add(time.Duration(n) * bucket)
+	for t := s.Next(start.Add(-time.Nanosecond)); !t.IsZero() && t.Before(end); t = s.Next(t) {
+		counts[int(t.Sub(start)/bucket)]++
+	}
+	return counts
+}
+
+// MinGap returns the shortest time between two runs of any one interval, or
+// of the calendars, ignoring their day, month and year fields. Runs of
+// different intervals (or of an interval and the calendars) may be closer.
+// It returns 0 if the spec has neither.
+func (s *Spec) MinGap() time.Duration {
+	var gap time.Duration
+	for _, i := range s.intervals {
+		if gap == 0 || i.Every < gap {
+			gap = i.Every
+		}
+	}
+	var secs []int
+	for _, c := range s.calendars {
+		secs = append(secs, c.secs...)
+	}
+	slices.Sort(secs)
+	secs = slices.Compact(secs)
+	if len(secs) == 0 {
+		return gap
+	}
+	daily := secs[0] + 24*3600 - secs[len(secs)-1]
+	for i := 1; i < len(secs); i++ {
+		daily = min(daily, secs[i]-secs[i-1])
+	}
+	if d := time.Duration(daily) * time.Second; gap == 0 || d < gap {
+		gap = d
+	}
+	return gap
+}
+
+func (s *Spec) skipped(t time.Time) bool {
+	for _, c := range s.skips {
+		if c.matches(t.In(s.loc)) {
+			return true
+		}
+	}
+	return false
+}
+
+// intervalNext returns the first time after the supplied time that's a
+// multiple of Every (plus the Offset) since the epoch.
+func intervalNext(i client.ScheduleIntervalSpec, after time.Time) time.Time {
+	base := time.Unix(0, 0).Add(i.Offset)
+	k := after.Sub(base) / i.Every
+	t := base.Add(k * i.Every)
+	for !t.After(after) {
+		t = t.Add(i.Every)
+	}
+	return t
+}
+
+func compileCalendar(c client.ScheduleCalendarSpec) calendar {
+	cal := calendar{years: c.Year}
+	for _, h := range expand(c.Hour, 0, 23, false) {
+		for _, m := range expand(c.Minute, 0, 59, false) {
+			for _, s := range expand(c.Second, 0, 59, false) {
+				cal.secs = append(cal.secs, h*3600+m*60+s)
+			}
+		}
+	}
+	slices.Sort(cal.secs)
+	cal.secs = slices.Compact(cal.secs)
+	for _, d := range expand(c.DayOfMonth, 1, 31, true) {
+		cal.dom[d] = true
+	}
+	for _, m := range expand(c.Month, 1, 12, true) {
+		cal.month[m] = true
+	}
+	for _, d := range expand(c.DayOfWeek, 0, 6, true) {
+		cal.dow[d] = true
+	}
+	return cal
+}
+
+// expand returns the values in [lo, hi] matched by the ranges the way
+// Temporal does: an End before the Start means just the Start and a zero
+// Step is 1. No ranges match every value if all is set, and 0 otherwise.
+func expand(rs []client.ScheduleRange, lo, hi int, all bool) []int {
+	if len(rs) == 0 {
+		rs = []client.ScheduleRange{{Start: 0}}
+		if all {
+			rs = []client.ScheduleRange{{Start: lo, End: hi}}
+		}
+	}
+	var vs []int
+	for _, r := range rs {
+		end, step := max(r.End, r.Start), max(r.Step, 1)
+		for v := r.Start; v <= min(end, hi); v += step {
+			if v >= lo {
+				vs = append(vs, v)
+			}
+		}
+	}
+	return vs
+}
+
+func inRanges(rs []client.ScheduleRange, v int) bool {
+	if len(rs) == 0 {
+		return true
+	}
+	for _, r := range rs {
+		end, step := max(r.End, r.Start), max(r.Step, 1)
+		if v >= r.Start && v <= end && (v-r.Start)%step == 0 {
+			return true
+		}
+	}
+	return false
+}
+
+func (c calendar) matchesDay(t time.Time) bool {
+	return c.dom[t.Day()] && c.month[t.Month()] && c.dow[t.Weekday()] && inRanges(c.years, t.Year())
+}
+
+// matches reports whether t (in the spec's location) is one of the
+// calendar's times.
+func (c calendar) matches(t time.Time) bool {
+	if !c.matchesDay(t) || t.Nanosecond() != 0 {
+		return false
+	}
+	_, ok := slices.BinarySearch(c.secs, t.Hour()*3600+t.Minute()*60+t.Second())
+	return ok
+}
+
+// next returns the calendar's first time after the supplied time. Calendars
+// match clock times literally, so times that don't exist on a day (i.e., in a
+// DST gap) are skipped.
+func (c calendar) next(after time.Time, loc *time.Location) time.Time {
+	if len(c.secs) == 0 {
+		return time.Time{}
+	}
+	a := after.In(loc)
+	day := time.Date(a.Year(), a.Month(), a.Day(), 0, 0, 0, 0, loc)
+	first, _ := slices.BinarySearch(c.secs, a.Hour()*3600+a.Minute()*60+a.Second())
+	for i := range maxSearchDays {
+		d := day.AddDate(0, 0, i)
+		if !c.matchesDay(d) {
+			continue
+		}
+		j := 0
+		if i == 0 {
+			j = first
+		}
+		for _, sec := range c.secs[j:] {
+			h, m, s := sec/3600, sec/60%60, sec%60
+			t := time.Date(d.Year(), d.Month(), d.Day(), h, m, s, 0, loc)
+			if t.Hour() != h || t.Minute() != m {
+				continue
+			}
+			if t.After(after) {
+				return t
+			}
+		}
+	}
+	return time.Time{}
+}
+
+// FromProto converts a spec as returned by the Temporal API (e.g., in a
+// ListSchedules response) into a client.ScheduleSpec.
+func FromProto(s *schedulepb.ScheduleSpec) client.ScheduleSpec {
+	if s == nil {
+		return client.ScheduleSpec{}
+	}
+	spec := client.ScheduleSpec{
+		Calendars:    calendarsFromProto(s.StructuredCalendar),
+		Skip:         calendarsFromProto(s.ExcludeStructuredCalendar),
+		TimeZoneName: s.TimezoneName,
+	}
+	for _, i := range s.Interval {
+		var iv client.ScheduleIntervalSpec
+		if i.Interval != nil {
+			iv.Every = *i.Interval
+		}
+		if i.Phase != nil {
+			iv.Offset = *i.Phase
+		}
+		spec.Intervals = append(spec.Intervals, iv)
+	}
+	if s.StartTime != nil {
+		spec.StartAt = *s.StartTime
+	}
+	if s.EndTime != nil {
+		spec.EndAt = *s.EndTime
+	}
+	if s.Jitter != nil {
+		spec.Jitter = *s.Jitter
+	}
+	return spec
+}
+
+func calendarsFromProto(cs []*schedulepb.StructuredCalendarSpec) []client.ScheduleCalendarSpec {
+	var cals []client.ScheduleCalendarSpec
+	for _, c := range cs {
+		cals = append(cals, client.ScheduleCalendarSpec{
+			Second:     rangesFromProto(c.Second),
+			Minute:     rangesFromProto(c.Minute),
+			Hour:       rangesFromProto(c.Hour),
+			DayOfMonth: rangesFromProto(c.DayOfMonth),
+			Month:      rangesFromProto(c.Month),
+			Year:       rangesFromProto(c.Year),
+			DayOfWeek:  rangesFromProto(c.DayOfWeek),
+			Comment:    c.Comment,
+		})
+	}
+	return cals
+}
+
+func rangesFromProto(rs []*schedulepb.Range) []client.ScheduleRange {
+	var out []client.ScheduleRange
+	for _, r := range rs {
+		out = append(out, client.ScheduleRange{Start: int(r.Start), End: int(r.End), Step: int(r.Step)})
+	}
+	return out
+}
@@ -0,0 +1,358 @@
+package schedspec
+
+import (
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"go.temporal.io/sdk/client"
+)
+
+func at(s string) time.Time {
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+var (
+	hourly = client.ScheduleCalendarSpec{
+		Second: []client.ScheduleRange{{Start: 0}},
+		Minute: []client.ScheduleRange{{Start: 0}},
+		Hour:   []client.ScheduleRange{{Start: 0, End: 23}},
+	}
+	halfHourly = client.ScheduleIntervalSpec{Every: 30 * time.Minute}
+)
+
+func TestNew(t *testing.T) {
+	cases := []struct {
+		name string
+		spec client.ScheduleSpec
+		err  bool
+	}{
+		{"empty", client.ScheduleSpec{}, false},
+		{"cron", client.ScheduleSpec{CronExpressions: []string{"0 * * * *"}}, true},
+		{"bad time zone", client.ScheduleSpec{TimeZoneName: "Nowhere/Special"}, true},
+		{"zero interval", client.ScheduleSpec{Intervals: []client.ScheduleIntervalSpec{{}}}, true},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			_, err := New(c.spec)
+			if (err != nil) != c.err {
+				t.Fatalf("expected error=%v, got %v", c.err, err)
+			}
+			if c.name == "cron" && !errors.Is(err, ErrCronUnsupported) {
+				t.Errorf("expected ErrCronUnsupported, got %v", err)
+			}
+		})
+	}
+}
+
+func TestNext(t *testing.T) {
+	cases := []struct {
+		name  string
+		spec  client.ScheduleSpec
+		after time.Time
+		want  time.Time
+	}{
+		{
+			name:  "hourly calendar",
+			spec:  client.ScheduleSpec{Calendars: []client.ScheduleCalendarSpec{hourly}},
+			after: at("2024-05-01T10:30:00Z"),
+			want:  at("2024-05-01T11:00:00Z"),
+		},
+		{
+			name:  "hourly calendar on a run",
+			spec:  client.ScheduleSpec{Calendars: []client.ScheduleCalendarSpec{hourly}},
+			after: at("2024-05-01T11:00:00Z"),
+			want:  at("2024-05-01T12:00:00Z"),
+		},
+		{
+			name:  "default calendar is midnight",
+			spec:  client.ScheduleSpec{Calendars: []client.ScheduleCalendarSpec{{}}},
+			after: at("2024-05-01T10:30:00Z"),
+			want:  at("2024-05-02T00:00:00Z"),
+		},
+		{
+			name:  "calendar day of week",
+			spec:  client.ScheduleSpec{Calendars: []client.ScheduleCalendarSpec{{DayOfWeek: []client.ScheduleRange{{Start: 1}}}}},
+			after: at("2024-05-01T10:30:00Z"),
+			want:  at("2024-05-06T00:00:00Z"),
+		},
+		{
+			name:  "interval",
+			spec:  client.ScheduleSpec{Intervals: []client.ScheduleIntervalSpec{halfHourly}},
+			after: at("2024-05-01T10:30:00Z"),
+			want:  at("2024-05-01T11:00:00Z"),
+		},
+		{
+			name:  "interval with offset",
+			spec:  client.ScheduleSpec{Intervals: []client.ScheduleIntervalSpec{{Every: 15 * time.Minute, Offset: 5 * time.Minute}}},
+			after: at("2024-05-01T10:30:00Z"),
+			want:  at("2024-05-01T10:35:00Z"),
+		},
+		{
+			name: "earliest of calendar and interval",
+			spec: client.ScheduleSpec{
+				Calendars: []client.ScheduleCalendarSpec{hourly},
+				Intervals: []client.ScheduleIntervalSpec{{Every: 15 * time.Minute, Offset: 5 * time.Minute}},
+			},
+			after: at("2024-05-01T10:55:00Z"),
+			want:  at("2024-05-01T11:00:00Z"),
+		},
+		{
+			name: "skip",
+			spec: client.ScheduleSpec{
+				Calendars: []client.ScheduleCalendarSpec{hourly},
+				Skip:      []client.ScheduleCalendarSpec{{Hour: []client.ScheduleRange{{Start: 11}}}},
+			},
+			after: at("2024-05-01T10:30:00Z"),
+			want:  at("2024-05-01T12:00:00Z"),
+		},
+		{
+			name: "before StartAt",
+			spec: client.ScheduleSpec{
+				Intervals: []client.ScheduleIntervalSpec{halfHourly},
+				StartAt:   at("2024-05-01T12:00:00Z"),
+			},
+			after: at("2024-05-01T10:30:00Z"),
+			want:  at("2024-05-01T12:00:00Z"),
+		},
+		{
+			name: "at EndAt",
+			spec: client.ScheduleSpec{
+				Intervals: []client.ScheduleIntervalSpec{halfHourly},
+				EndAt:     at("2024-05-01T11:00:00Z"),
+			},
+			after: at("2024-05-01T10:30:00Z"),
+			want:  at("2024-05-01T11:00:00Z"),
+		},
+		{
+			name: "after EndAt",
+			spec: client.ScheduleSpec{
+				Intervals: []client.ScheduleIntervalSpec{halfHourly},
+				EndAt:     at("2024-05-01T11:00:00Z"),
+			},
+			after: at("2024-05-01T11:00:00Z"),
+		},
+		{
+			name: "time zone across spring forward",
+			spec: client.ScheduleSpec{
+				Calendars:    []client.ScheduleCalendarSpec{{Hour: []client.ScheduleRange{{Start: 9}}}},
+				TimeZoneName: "America/New_York",
+			},
+			// 09:00 EST on the 9th, then 09:00 EDT on the 10th
+			after: at("2024-03-09T14:00:00Z"),
+			want:  at("2024-03-10T13:00:00Z"),
+		},
+		{
+			name: "time in the spring forward gap is skipped",
+			spec: client.ScheduleSpec{
+				Calendars: []client.ScheduleCalendarSpec{{
+					Hour:   []client.ScheduleRange{{Start: 2}},
+					Minute: []client.ScheduleRange{{Start: 30}},
+				}},
+				TimeZoneName: "America/New_York",
+			},
+			after: at("2024-03-09T12:00:00Z"),
+			want:  at("2024-03-11T06:30:00Z"),
+		},
+		{
+			name: "repeated time at fall back runs once",
+			spec: client.ScheduleSpec{
+				Calendars: []client.ScheduleCalendarSpec{{
+					Hour:   []client.ScheduleRange{{Start: 1}},
+					Minute: []client.ScheduleRange{{Start: 30}},
+				}},
+				TimeZoneName: "America/New_York",
+			},
+			// 01:30 EDT on the 3rd, then 01:30 EST on the 4th
+			after: at("2024-11-03T05:30:00Z"),
+			want:  at("2024-11-04T06:30:00Z"),
+		},
+		{
+			name:  "no calendars or intervals",
+			spec:  client.ScheduleSpec{},
+			after: at("2024-05-01T10:30:00Z"),
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			got := MustNew(c.spec).Next(c.after)
+			if !got.Equal(c.want) {
+				t.Errorf("expected %s, got %s", c.want, got)
+			}
+		})
+	}
+}
+
+func TestRuns(t *testing.T) {
+	start := at("2024-05-01T10:00:00Z")
+	hours := func(hs ...int) []time.Time {
+		ts := []time.Time{}
+		for _, h := range hs {
+			ts = append(ts, start.Add(time.Duration(h)*time.Hour))
+		}
+		return ts
+	}
+	cases := []struct {
+		name  string
+		spec  client.ScheduleSpec
+		end   time.Time
+		limit int
+		want  []time.Time
+	}{
+		{
+			name: "bounds are inclusive",
+			spec: client.ScheduleSpec{Calendars: []client.ScheduleCalendarSpec{hourly}},
+			end:  start.Add(3 * time.Hour),
+			want: hours(0, 1, 2, 3),
+		},
+		{
+			name:  "limit",
+			spec:  client.ScheduleSpec{Calendars: []client.ScheduleCalendarSpec{hourly}},
+			end:   start.Add(3 * time.Hour),
+			limit: 2,
+			want:  hours(0, 1),
+		},
+		{
+			// fewer runs left than the limit (the schedule page asks for 10)
+			name: "fewer runs than the limit before EndAt",
+			spec: client.ScheduleSpec{
+				Calendars: []client.ScheduleCalendarSpec{hourly},
+				EndAt:     start.Add(2 * time.Hour),
+			},
+			end:   start.Add(72 * time.Hour),
+			limit: 10,
+			want:  hours(0, 1, 2),
+		},
+		{
+			name: "skips",
+			spec: client.ScheduleSpec{
+				Calendars: []client.ScheduleCalendarSpec{hourly},
+				Skip:      []client.ScheduleCalendarSpec{{Hour: []client.ScheduleRange{{Start: 11, End: 12}}}},
+			},
+			end:  start.Add(3 * time.Hour),
+			want: hours(0, 3),
+		},
+		{
+			name: "ended",
+			spec: client.ScheduleSpec{
+				Calendars: []client.ScheduleCalendarSpec{hourly},
+				EndAt:     start.Add(-time.Hour),
+			},
+			end:   start.Add(72 * time.Hour),
+			limit: 10,
+			want:  hours(),
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			got := MustNew(c.spec).Runs(start, c.end, c.limit)
+			if !slices.EqualFunc(got, c.want, time.Time.Equal) {
+				t.Errorf("expected %v, got %v", c.want, got)
+			}
+		})
+	}
+}
+
+func TestHistogram(t *testing.T) {
+	start := at("2024-05-01T10:00:00Z")
+	cases := []struct {
+		name string
+		spec client.ScheduleSpec
+		want []int
+	}{
+		{
+			name: "interval",
+			spec: client.ScheduleSpec{Intervals: []client.ScheduleIntervalSpec{halfHourly}},
+			want: []int{2, 2, 2},
+		},
+		{
+			name: "calendar with skip",
+			spec: client.ScheduleSpec{
+				Calendars: []client.ScheduleCalendarSpec{hourly},
+				Skip:      []client.ScheduleCalendarSpec{{Hour: []client.ScheduleRange{{Start: 11}}}},
+			},
+			want: []int{1, 0, 1},
+		},
+		{
+			name: "StartAt and EndAt",
+			spec: client.ScheduleSpec{
+				Intervals: []client.ScheduleIntervalSpec{halfHourly},
+				StartAt:   start.Add(30 * time.Minute),
+				EndAt:     start.Add(90 * time.Minute),
+			},
+			want: []int{1, 2, 0},
+		},
+		{
+			name: "none",
+			spec: client.ScheduleSpec{},
+			want: []int{0, 0, 0},
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			got := MustNew(c.spec).Histogram(start, time.Hour, 3)
+			if !slices.Equal(got, c.want) {
+				t.Errorf("expected %v, got %v", c.want, got)
+			}
+		})
+	}
+}
+
+func TestMinGap(t *testing.T) {
+	cases := []struct {
+		name string
+		spec client.ScheduleSpec
+		want time.Duration
+	}{
+		{
+			name: "intervals",
+			spec: client.ScheduleSpec{Intervals: []client.ScheduleIntervalSpec{{Every: time.Hour}, {Every: 15 * time.Minute}}},
+			want: 15 * time.Minute,
+		},
+		{
+			name: "calendar minutes",
+			spec: client.ScheduleSpec{Calendars: []client.ScheduleCalendarSpec{{
+				Minute: []client.ScheduleRange{{Start: 0}, {Start: 45}},
+				Hour:   []client.ScheduleRange{{Start: 0, End: 23}},
+			}}},
+			want: 15 * time.Minute,
+		},
+		{
+			name: "calendar wraps around midnight",
+			spec: client.ScheduleSpec{Calendars: []client.ScheduleCalendarSpec{{
+				Hour: []client.ScheduleRange{{Start: 0}, {Start: 23}},
+			}}},
+			want: time.Hour,
+		},
+		{
+			name: "default calendar",
+			spec: client.ScheduleSpec{Calendars: []client.ScheduleCalendarSpec{{}}},
+			want: 24 * time.Hour,
+		},
+		{
+			name: "calendar and interval",
+			spec: client.ScheduleSpec{
+				Calendars: []client.ScheduleCalendarSpec{hourly},
+				Intervals: []client.ScheduleIntervalSpec{{Every: 2 * time.Hour}},
+			},
+			want: time.Hour,
+		},
+		{
+			name: "none",
+			spec: client.ScheduleSpec{},
+			want: 0,
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if got := MustNew(c.spec).MinGap(); got != c.want {
+				t.Errorf("expected %s, got %s", c.want, got)
+			}
+		})
+	}
+}
@@ -12,7 +12,9 @@ import (
 	"slices"
 	"strings"
 
+	"github.com/brojonat/kaggo/server/alerts"
 	"github.com/brojonat/kaggo/server/db/dbgen"
+	kt "github.com/brojonat/kaggo/temporal/v19700101"
 	"github.com/brojonat/server-tools/stools"
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
@@ -92,6 +94,11 @@ func RunHTTPServer(
 		return fmt.Errorf("could not initialize Temporal client: %w", err)
 	}
 	defer tc.Close()
+	// schedules can't be created without their search attributes, but not
+	// every deployment lets us register them, so don't fail on error
+	if err := kt.RegisterSearchAttributes(ctx, tc, client.DefaultNamespace); err != nil {
+		l.Error("could not register search attributes", "error", err.Error())
+	}
 
 	prometheus.MustRegister(slices.Collect(maps.Values(promMetrics))...)
 	router, err := getRouter(l, p, q, tc, promMetrics)
@@ -111,6 +118,11 @@ func getRouter(
 	tc client.Client,
 	pms map[string]prometheus.Collector,
 ) (http.Handler, error) {
+	// make sure every RequestKind is fully wired up before serving anything
+	if err := kt.ValidateRequestKindRegistry(); err != nil {
+		return nil, fmt.Errorf("startup: invalid request kind registry: %w", err)
+	}
+
 	// new router
 	mux := http.NewServeMux()
 
@@ -138,6 +150,11 @@ func getRouter(
 		return nil, fmt.Errorf("error with prom metric %s", PromMetricHandlerRequestCounter)
 	}
 
+	// alert rules are evaluated as metrics are ingested
+	al := alerts.NewAlerter(l, q, alerts.GetDefaultNotifiers())
+	// platform events are delivered to user webhooks via temporal
+	wh := newWebhooker(l, q, tc)
+
 	// static files and template parsing
 	plotTmpl = template.Must(template.ParseFS(static, "static/templates/plots/plot.tmpl"))
 	// d3Tmpl = template.Must(template.ParseFS(static, "static/templates/plots/d3.tmpl"))
@@ -159,6 +176,7 @@ func getRouter(
 		withPromCounter(prcounter),
 		atLeastOneAuth(
 			bearerAuthorizerCtxSetToken(getSecretKey),
+			apiKeyAuthorizerCtxSetKey(l, q),
 			basicAuthorizerCtxSetEmail(getSecretKey),
 		),
 	))
@@ -177,13 +195,19 @@ func getRouter(
 	// serves the data to the plot static file; Bearer token protected
 	mux.Handle("GET /plot-data", stools.AdaptHandler(
 		handleGetPlotData(l, q, tc),
-		atLeastOneAuth(bearerAuthorizerCtxSetToken(getSecretKey)),
+		requireScope(scopeScheduleAdmin),
+		atLeastOneAuth(
+			bearerAuthorizerCtxSetToken(getSecretKey),
+			apiKeyAuthorizerCtxSetKey(l, q),
+		),
 	))
 	// prometheus metric handler
 	mux.Handle("/metrics", stools.AdaptHandler(
 		handlePromMetrics(promhttp.Handler()),
+		requireScope(scopeScheduleAdmin),
 		atLeastOneAuth(
 			bearerAuthorizerCtxSetToken(getSecretKey),
+			apiKeyAuthorizerCtxSetKey(l, q),
 			basicAuthorizerCtxSetEmail(getSecretKey),
 		),
 	))
@@ -192,31 +216,251 @@ func getRouter(
 	mux.HandleFunc("GET /users", stools.AdaptHandler(
 		handleGetUsers(l, q),
 		apiMode(l, maxBytes, headers, methods, origins),
-		atLeastOneAuth(bearerAuthorizerCtxSetToken(getSecretKey)),
+		requireScope(scopeUserAdmin),
+		atLeastOneAuth(
+			bearerAuthorizerCtxSetToken(getSecretKey),
+			apiKeyAuthorizerCtxSetKey(l, q),
+		),
 		withPromCounter(prcounter),
 	))
 	mux.HandleFunc("POST /users", stools.AdaptHandler(
 		handleAddUser(l, q),
 		apiMode(l, maxBytes, headers, methods, origins),
-		atLeastOneAuth(bearerAuthorizerCtxSetToken(getSecretKey)),
+		requireScope(scopeUserAdmin),
+		atLeastOneAuth(
+			bearerAuthorizerCtxSetToken(getSecretKey),
+			apiKeyAuthorizerCtxSetKey(l, q),
+		),
 		withPromCounter(prcounter),
 	))
 	mux.HandleFunc("DELETE /users", stools.AdaptHandler(
 		handleDeleteUser(l, q),
 		apiMode(l, maxBytes, headers, methods, origins),
-		atLeastOneAuth(bearerAuthorizerCtxSetToken(getSecretKey)),
+		requireScope(scopeUserAdmin),
+		atLeastOneAuth(
+			bearerAuthorizerCtxSetToken(getSecretKey),
+			apiKeyAuthorizerCtxSetKey(l, q),
+		),
 		withPromCounter(prcounter),
 	))
 	mux.HandleFunc("GET /users/metrics", stools.AdaptHandler(
 		handleGetUserMetrics(l, q),
 		apiMode(l, maxBytes, headers, methods, origins),
-		atLeastOneAuth(bearerAuthorizerCtxSetToken(getSecretKey)),
+		requireScope(scopeRead),
+		atLeastOneAuth(
+			bearerAuthorizerCtxSetToken(getSecretKey),
+			apiKeyAuthorizerCtxSetKey(l, q),
+		),
 		withPromCounter(prcounter),
 	))
 	mux.HandleFunc("POST /users/metrics", stools.AdaptHandler(
 		handleUserMetricOperation(l, q),
 		apiMode(l, maxBytes, headers, methods, origins),
-		atLeastOneAuth(bearerAuthorizerCtxSetToken(getSecretKey)),
+		requireScope(scopeUserAdmin),
+		atLeastOneAuth(
+			bearerAuthorizerCtxSetToken(getSecretKey),
+			apiKeyAuthorizerCtxSetKey(l, q),
+		),
+		withPromCounter(prcounter),
+	))
+
+	// api keys
+	mux.HandleFunc("GET /api-keys", stools.AdaptHandler(
+		handleGetAPIKeys(l, q),
+		apiMode(l, maxBytes, headers, methods, origins),
+		requireScope(scopeUserAdmin),
+		atLeastOneAuth(
+			bearerAuthorizerCtxSetToken(getSecretKey),
+			apiKeyAuthorizerCtxSetKey(l, q),
+		),
+		withPromCounter(prcounter),
+	))
+	mux.HandleFunc("POST /api-keys", stools.AdaptHandler(
+		handleCreateAPIKey(l, q),
+		apiMode(l, maxBytes, headers, methods, origins),
+		requireScope(scopeUserAdmin),
+		atLeastOneAuth(
+			bearerAuthorizerCtxSetToken(getSecretKey),
+			apiKeyAuthorizerCtxSetKey(l, q),
+		),
+		withPromCounter(prcounter),
+	))
+	mux.HandleFunc("DELETE /api-keys", stools.AdaptHandler(
+		handleRevokeAPIKeys(l, q),
+		apiMode(l, maxBytes, headers, methods, origins),
+		requireScope(scopeUserAdmin),
+		atLeastOneAuth(
+			bearerAuthorizerCtxSetToken(getSecretKey),
+			apiKeyAuthorizerCtxSetKey(l, q),
+		),
+		withPromCounter(prcounter),
+	))
+
+	// alert rules and history
+	mux.HandleFunc("GET /alerts", stools.AdaptHandler(
+		handleGetAlertRules(l, q),
+		apiMode(l, maxBytes, headers, methods, origins),
+		requireScope(scopeRead),
+		atLeastOneAuth(
+			bearerAuthorizerCtxSetToken(getSecretKey),
+			apiKeyAuthorizerCtxSetKey(l, q),
+		),
+		withPromCounter(prcounter),
+	))
+	mux.HandleFunc("POST /alerts", stools.AdaptHandler(
+		handleCreateAlertRule(l, q, al),
+		apiMode(l, maxBytes, headers, methods, origins),
+		requireScope(scopeRead),
+		atLeastOneAuth(
+			bearerAuthorizerCtxSetToken(getSecretKey),
+			apiKeyAuthorizerCtxSetKey(l, q),
+		),
+		withPromCounter(prcounter),
+	))
+	mux.HandleFunc("DELETE /alerts", stools.AdaptHandler(
+		handleDeleteAlertRules(l, q),
+		apiMode(l, maxBytes, headers, methods, origins),
+		requireScope(scopeRead),
+		atLeastOneAuth(
+			bearerAuthorizerCtxSetToken(getSecretKey),
+			apiKeyAuthorizerCtxSetKey(l, q),
+		),
+		withPromCounter(prcounter),
+	))
+	mux.HandleFunc("GET /alerts/history", stools.AdaptHandler(
+		handleGetAlertHistory(l, q),
+		apiMode(l, maxBytes, headers, methods, origins),
+		requireScope(scopeRead),
+		atLeastOneAuth(
+			bearerAuthorizerCtxSetToken(getSecretKey),
+			apiKeyAuthorizerCtxSetKey(l, q),
+		),
+		withPromCounter(prcounter),
+	))
+	mux.HandleFunc("POST /alerts/test", stools.AdaptHandler(
+		handleTestNotifier(l, al),
+		apiMode(l, maxBytes, headers, methods, origins),
+		requireScope(scopeUserAdmin),
+		atLeastOneAuth(
+			bearerAuthorizerCtxSetToken(getSecretKey),
+			apiKeyAuthorizerCtxSetKey(l, q),
+		),
+		withPromCounter(prcounter),
+	))
+
+	// webhooks
+	mux.HandleFunc("GET /webhooks", stools.AdaptHandler(
+		handleGetWebhooks(l, q),
+		apiMode(l, maxBytes, headers, methods, origins),
+		requireScope(scopeRead),
+		atLeastOneAuth(
+			bearerAuthorizerCtxSetToken(getSecretKey),
+			apiKeyAuthorizerCtxSetKey(l, q),
+		),
+		withPromCounter(prcounter),
+	))
+	mux.HandleFunc("POST /webhooks", stools.AdaptHandler(
+		handleCreateWebhook(l, q),
+		apiMode(l, maxBytes, headers, methods, origins),
+		requireScope(scopeRead),
+		atLeastOneAuth(
+			bearerAuthorizerCtxSetToken(getSecretKey),
+			apiKeyAuthorizerCtxSetKey(l, q),
+		),
+		withPromCounter(prcounter),
+	))
+	mux.HandleFunc("DELETE /webhooks", stools.AdaptHandler(
+		handleDeleteWebhooks(l, q),
+		apiMode(l, maxBytes, headers, methods, origins),
+		requireScope(scopeRead),
+		atLeastOneAuth(
+			bearerAuthorizerCtxSetToken(getSecretKey),
+			apiKeyAuthorizerCtxSetKey(l, q),
+		),
+		withPromCounter(prcounter),
+	))
+	mux.HandleFunc("GET /webhooks/dead-letters", stools.AdaptHandler(
+		handleGetWebhookDeadLetters(l, q),
+		apiMode(l, maxBytes, headers, methods, origins),
+		requireScope(scopeRead),
+		atLeastOneAuth(
+			bearerAuthorizerCtxSetToken(getSecretKey),
+			apiKeyAuthorizerCtxSetKey(l, q),
+		),
+		withPromCounter(prcounter),
+	))
+	mux.HandleFunc("POST /webhooks/sign", stools.AdaptHandler(
+		handleSignWebhook(l, q),
+		apiMode(l, maxBytes, headers, methods, origins),
+		requireScope(scopeIngest),
+		atLeastOneAuth(
+			bearerAuthorizerCtxSetToken(getSecretKey),
+			apiKeyAuthorizerCtxSetKey(l, q),
+		),
+		withPromCounter(prcounter),
+	))
+	mux.HandleFunc("POST /webhooks/dead-letters", stools.AdaptHandler(
+		handlePostWebhookDeadLetter(l, q),
+		apiMode(l, maxBytes, headers, methods, origins),
+		requireScope(scopeIngest),
+		atLeastOneAuth(
+			bearerAuthorizerCtxSetToken(getSecretKey),
+			apiKeyAuthorizerCtxSetKey(l, q),
+		),
+		withPromCounter(prcounter),
+	))
+
+	// provider rate limit governor; see handlers_rate_limits.go
+	mux.HandleFunc("GET /rate-limits", stools.AdaptHandler(
+		handleGetRateLimitBuckets(l, q),
+		apiMode(l, maxBytes, headers, methods, origins),
+		requireScope(scopeScheduleAdmin),
+		atLeastOneAuth(
+			bearerAuthorizerCtxSetToken(getSecretKey),
+			apiKeyAuthorizerCtxSetKey(l, q),
+		),
+		withPromCounter(prcounter),
+	))
+	mux.HandleFunc("POST /rate-limits/acquire", stools.AdaptHandler(
+		handleAcquireRateLimitToken(l, p, q),
+		apiMode(l, maxBytes, headers, methods, origins),
+		requireScope(scopeIngest),
+		atLeastOneAuth(
+			bearerAuthorizerCtxSetToken(getSecretKey),
+			apiKeyAuthorizerCtxSetKey(l, q),
+		),
+		withPromCounter(prcounter),
+	))
+	mux.HandleFunc("POST /rate-limits/observe", stools.AdaptHandler(
+		handleObserveRateLimit(l, q),
+		apiMode(l, maxBytes, headers, methods, origins),
+		requireScope(scopeIngest),
+		atLeastOneAuth(
+			bearerAuthorizerCtxSetToken(getSecretKey),
+			apiKeyAuthorizerCtxSetKey(l, q),
+		),
+		withPromCounter(prcounter),
+	))
+
+	// provider quota ledger; see handlers_quota.go
+	mux.HandleFunc("GET /quota", stools.AdaptHandler(
+		handleGetQuotaLedger(l, q),
+		apiMode(l, maxBytes, headers, methods, origins),
+		requireScope(scopeScheduleAdmin),
+		atLeastOneAuth(
+			bearerAuthorizerCtxSetToken(getSecretKey),
+			apiKeyAuthorizerCtxSetKey(l, q),
+		),
+		withPromCounter(prcounter),
+	))
+	mux.HandleFunc("POST /quota/charge", stools.AdaptHandler(
+		handleChargeQuota(l, q),
+		apiMode(l, maxBytes, headers, methods, origins),
+		requireScope(scopeIngest),
+		atLeastOneAuth(
+			bearerAuthorizerCtxSetToken(getSecretKey),
+			apiKeyAuthorizerCtxSetKey(l, q),
+		),
 		withPromCounter(prcounter),
 	))
 
@@ -224,25 +468,51 @@ func getRouter(
 	mux.HandleFunc("GET /metadata", stools.AdaptHandler(
 		handleGetMetricMetadata(l, q),
 		apiMode(l, maxBytes, headers, methods, origins),
-		atLeastOneAuth(bearerAuthorizerCtxSetToken(getSecretKey)),
+		requireScope(scopeRead),
+		atLeastOneAuth(
+			bearerAuthorizerCtxSetToken(getSecretKey),
+			apiKeyAuthorizerCtxSetKey(l, q),
+		),
 		withPromCounter(prcounter),
 	))
 	mux.HandleFunc("GET /metadata/children", stools.AdaptHandler(
 		handleGetChildrenMetadata(l, q),
 		apiMode(l, maxBytes, headers, methods, origins),
-		atLeastOneAuth(bearerAuthorizerCtxSetToken(getSecretKey)),
+		requireScope(scopeRead),
+		atLeastOneAuth(
+			bearerAuthorizerCtxSetToken(getSecretKey),
+			apiKeyAuthorizerCtxSetKey(l, q),
+		),
 		withPromCounter(prcounter),
 	))
 	mux.HandleFunc("POST /metadata", stools.AdaptHandler(
-		handlePostMetricMetadata(l, q),
+		handlePostMetricMetadata(l, q, wh),
 		apiMode(l, maxBytes, headers, methods, origins),
-		atLeastOneAuth(bearerAuthorizerCtxSetToken(getSecretKey)),
+		requireScope(scopeIngest),
+		atLeastOneAuth(
+			bearerAuthorizerCtxSetToken(getSecretKey),
+			apiKeyAuthorizerCtxSetKey(l, q),
+		),
+		withPromCounter(prcounter),
+	))
+	mux.HandleFunc("POST /backfill", stools.AdaptHandler(
+		handleRunBackfillWF(l, tc),
+		apiMode(l, maxBytes, headers, methods, origins),
+		requireScope(scopeScheduleAdmin),
+		atLeastOneAuth(
+			bearerAuthorizerCtxSetToken(getSecretKey),
+			apiKeyAuthorizerCtxSetKey(l, q),
+		),
 		withPromCounter(prcounter),
 	))
 	mux.HandleFunc("POST /metadata/run-workflow", stools.AdaptHandler(
 		handleRunMetadataWF(l, q, tc),
 		apiMode(l, maxBytes, headers, methods, origins),
-		atLeastOneAuth(bearerAuthorizerCtxSetToken(getSecretKey)),
+		requireScope(scopeScheduleAdmin),
+		atLeastOneAuth(
+			bearerAuthorizerCtxSetToken(getSecretKey),
+			apiKeyAuthorizerCtxSetKey(l, q),
+		),
 		withPromCounter(prcounter),
 	))
 
@@ -250,7 +520,23 @@ func getRouter(
 	mux.HandleFunc("POST /add-listener-sub", stools.AdaptHandler(
 		handleAddListenerSub(l, q),
 		apiMode(l, maxBytes, headers, methods, origins),
-		atLeastOneAuth(bearerAuthorizerCtxSetToken(getSecretKey)),
+		requireScope(scopeScheduleAdmin),
+		atLeastOneAuth(
+			bearerAuthorizerCtxSetToken(getSecretKey),
+			apiKeyAuthorizerCtxSetKey(l, q),
+		),
+		withPromCounter(prcounter),
+	))
+
+	// workflow routes
+	mux.Handle("GET /workflow", stools.AdaptHandler(
+		handleListWorkflows(l, tc),
+		apiMode(l, maxBytes, headers, methods, origins),
+		requireScope(scopeScheduleAdmin),
+		atLeastOneAuth(
+			bearerAuthorizerCtxSetToken(getSecretKey),
+			apiKeyAuthorizerCtxSetKey(l, q),
+		),
 		withPromCounter(prcounter),
 	))
 
@@ -258,31 +544,131 @@ func getRouter(
 	mux.Handle("GET /schedule", stools.AdaptHandler(
 		handleGetSchedule(l, tc),
 		apiMode(l, maxBytes, headers, methods, origins),
-		atLeastOneAuth(bearerAuthorizerCtxSetToken(getSecretKey)),
+		requireScope(scopeScheduleAdmin),
+		atLeastOneAuth(
+			bearerAuthorizerCtxSetToken(getSecretKey),
+			apiKeyAuthorizerCtxSetKey(l, q),
+		),
+		withPromCounter(prcounter),
+	))
+	mux.Handle("GET /schedule/{id}", stools.AdaptHandler(
+		handleDescribeSchedule(l, tc),
+		apiMode(l, maxBytes, headers, methods, origins),
+		requireScope(scopeScheduleAdmin),
+		atLeastOneAuth(
+			bearerAuthorizerCtxSetToken(getSecretKey),
+			apiKeyAuthorizerCtxSetKey(l, q),
+		),
+		withPromCounter(prcounter),
+	))
+	mux.Handle("GET /schedule/capacity", stools.AdaptHandler(
+		handleGetScheduleCapacity(l, tc),
+		apiMode(l, maxBytes, headers, methods, origins),
+		requireScope(scopeScheduleAdmin),
+		atLeastOneAuth(
+			bearerAuthorizerCtxSetToken(getSecretKey),
+			apiKeyAuthorizerCtxSetKey(l, q),
+		),
 		withPromCounter(prcounter),
 	))
 	mux.Handle("POST /schedule", stools.AdaptHandler(
-		handleCreateSchedule(l, q, tc),
+		handleCreateSchedule(l, q, tc, wh),
 		apiMode(l, maxBytes, headers, methods, origins),
-		atLeastOneAuth(bearerAuthorizerCtxSetToken(getSecretKey)),
+		requireScope(scopeScheduleAdmin),
+		atLeastOneAuth(
+			bearerAuthorizerCtxSetToken(getSecretKey),
+			apiKeyAuthorizerCtxSetKey(l, q),
+		),
 		withPromCounter(prcounter),
 	))
 	mux.Handle("PUT /schedule", stools.AdaptHandler(
-		handleUpdateSchedule(l, tc),
+		handleUpdateSchedule(l, tc, wh),
 		apiMode(l, maxBytes, headers, methods, origins),
-		atLeastOneAuth(bearerAuthorizerCtxSetToken(getSecretKey)),
+		requireScope(scopeScheduleAdmin),
+		atLeastOneAuth(
+			bearerAuthorizerCtxSetToken(getSecretKey),
+			apiKeyAuthorizerCtxSetKey(l, q),
+		),
+		withPromCounter(prcounter),
+	))
+	mux.Handle("PUT /schedule/bulk", stools.AdaptHandler(
+		handleBulkUpdateSchedule(l, tc, wh),
+		apiMode(l, maxBytes, headers, methods, origins),
+		requireScope(scopeScheduleAdmin),
+		atLeastOneAuth(
+			bearerAuthorizerCtxSetToken(getSecretKey),
+			apiKeyAuthorizerCtxSetKey(l, q),
+		),
 		withPromCounter(prcounter),
 	))
 	mux.Handle("DELETE /schedule", stools.AdaptHandler(
 		handleCancelSchedule(l, tc),
 		apiMode(l, maxBytes, headers, methods, origins),
-		atLeastOneAuth(bearerAuthorizerCtxSetToken(getSecretKey)),
+		requireScope(scopeScheduleAdmin),
+		atLeastOneAuth(
+			bearerAuthorizerCtxSetToken(getSecretKey),
+			apiKeyAuthorizerCtxSetKey(l, q),
+		),
+		withPromCounter(prcounter),
+	))
+	mux.Handle("POST /schedule/watch-expiry", stools.AdaptHandler(
+		handleWatchScheduleExpiry(l, tc, wh),
+		apiMode(l, maxBytes, headers, methods, origins),
+		requireScope(scopeScheduleAdmin),
+		atLeastOneAuth(
+			bearerAuthorizerCtxSetToken(getSecretKey),
+			apiKeyAuthorizerCtxSetKey(l, q),
+		),
 		withPromCounter(prcounter),
 	))
 	mux.Handle("POST /schedule/trigger", stools.AdaptHandler(
 		handleTriggerSchedule(l, tc),
 		apiMode(l, maxBytes, headers, methods, origins),
-		atLeastOneAuth(bearerAuthorizerCtxSetToken(getSecretKey)),
+		requireScope(scopeScheduleAdmin),
+		atLeastOneAuth(
+			bearerAuthorizerCtxSetToken(getSecretKey),
+			apiKeyAuthorizerCtxSetKey(l, q),
+		),
+		withPromCounter(prcounter),
+	))
+	mux.Handle("POST /schedule/pause-failing", stools.AdaptHandler(
+		handlePauseFailingSchedule(l, q, tc),
+		apiMode(l, maxBytes, headers, methods, origins),
+		requireScope(scopeIngest),
+		atLeastOneAuth(
+			bearerAuthorizerCtxSetToken(getSecretKey),
+			apiKeyAuthorizerCtxSetKey(l, q),
+		),
+		withPromCounter(prcounter),
+	))
+	mux.Handle("POST /schedule/cadence", stools.AdaptHandler(
+		handleReviewScheduleCadence(l, q, tc),
+		apiMode(l, maxBytes, headers, methods, origins),
+		requireScope(scopeIngest),
+		atLeastOneAuth(
+			bearerAuthorizerCtxSetToken(getSecretKey),
+			apiKeyAuthorizerCtxSetKey(l, q),
+		),
+		withPromCounter(prcounter),
+	))
+	mux.Handle("POST /schedule/end-of-life", stools.AdaptHandler(
+		handleReviewScheduleEndOfLife(l, q, tc),
+		apiMode(l, maxBytes, headers, methods, origins),
+		requireScope(scopeIngest),
+		atLeastOneAuth(
+			bearerAuthorizerCtxSetToken(getSecretKey),
+			apiKeyAuthorizerCtxSetKey(l, q),
+		),
+		withPromCounter(prcounter),
+	))
+	mux.Handle("POST /schedule/expired", stools.AdaptHandler(
+		handleScheduleExpired(l, q, tc, wh),
+		apiMode(l, maxBytes, headers, methods, origins),
+		requireScope(scopeIngest),
+		atLeastOneAuth(
+			bearerAuthorizerCtxSetToken(getSecretKey),
+			apiKeyAuthorizerCtxSetKey(l, q),
+		),
 		withPromCounter(prcounter),
 	))
 
@@ -290,163 +676,320 @@ func getRouter(
 	mux.HandleFunc("GET /internal/generate", stools.AdaptHandler(
 		handleInternalMetricsGenerate(l),
 		apiMode(l, maxBytes, headers, methods, origins),
-		atLeastOneAuth(bearerAuthorizerCtxSetToken(getSecretKey)),
+		requireScope(scopeScheduleAdmin),
+		atLeastOneAuth(
+			bearerAuthorizerCtxSetToken(getSecretKey),
+			apiKeyAuthorizerCtxSetKey(l, q),
+		),
 		withPromCounter(prcounter),
 	))
 	mux.HandleFunc("GET /internal/metrics", stools.AdaptHandler(
-		handleInternalMetricsGet(l, q),
+		handleGetTimeSeriesByKind(l, q, kt.RequestKindInternalRandom),
 		apiMode(l, maxBytes, headers, methods, origins),
-		atLeastOneAuth(bearerAuthorizerCtxSetToken(getSecretKey)),
+		requireScope(scopeRead),
+		atLeastOneAuth(
+			bearerAuthorizerCtxSetToken(getSecretKey),
+			apiKeyAuthorizerCtxSetKey(l, q),
+		),
 		withPromCounter(prcounter),
 	))
 	mux.HandleFunc("POST /internal/metrics", stools.AdaptHandler(
-		handleInternalMetricsPost(l, q, pms),
+		handleInternalMetricsPost(l, q, al, pms),
 		apiMode(l, maxBytes, headers, methods, origins),
-		atLeastOneAuth(bearerAuthorizerCtxSetToken(getSecretKey)),
+		requireScope(scopeIngest),
+		atLeastOneAuth(
+			bearerAuthorizerCtxSetToken(getSecretKey),
+			apiKeyAuthorizerCtxSetKey(l, q),
+		),
 		withPromCounter(prcounter),
 	))
 
 	// kaggle notebook metrics
 	mux.HandleFunc("GET /kaggle/notebook", stools.AdaptHandler(
-		handleKaggleNotebookMetricsGet(l, q),
+		handleGetTimeSeriesByKind(l, q, kt.RequestKindKaggleNotebook),
 		apiMode(l, maxBytes, headers, methods, origins),
-		atLeastOneAuth(bearerAuthorizerCtxSetToken(getSecretKey)),
+		requireScope(scopeRead),
+		atLeastOneAuth(
+			bearerAuthorizerCtxSetToken(getSecretKey),
+			apiKeyAuthorizerCtxSetKey(l, q),
+		),
 		withPromCounter(prcounter),
 	))
 	mux.HandleFunc("POST /kaggle/notebook", stools.AdaptHandler(
-		handleKaggleNotebookPost(l, q),
+		handleKaggleNotebookPost(l, q, al),
 		apiMode(l, maxBytes, headers, methods, origins),
-		atLeastOneAuth(bearerAuthorizerCtxSetToken(getSecretKey)),
+		requireScope(scopeIngest),
+		atLeastOneAuth(
+			bearerAuthorizerCtxSetToken(getSecretKey),
+			apiKeyAuthorizerCtxSetKey(l, q),
+		),
 		withPromCounter(prcounter),
 	))
 
 	// kaggle dataset metrics
 	mux.HandleFunc("GET /kaggle/dataset", stools.AdaptHandler(
-		handleKaggleDatasetMetricsGet(l, q),
+		handleGetTimeSeriesByKind(l, q, kt.RequestKindKaggleDataset),
 		apiMode(l, maxBytes, headers, methods, origins),
-		atLeastOneAuth(bearerAuthorizerCtxSetToken(getSecretKey)),
+		requireScope(scopeRead),
+		atLeastOneAuth(
+			bearerAuthorizerCtxSetToken(getSecretKey),
+			apiKeyAuthorizerCtxSetKey(l, q),
+		),
 		withPromCounter(prcounter),
 	))
 	mux.HandleFunc("POST /kaggle/dataset", stools.AdaptHandler(
-		handleKaggleDatasetPost(l, q),
+		handleKaggleDatasetPost(l, q, al),
+		apiMode(l, maxBytes, headers, methods, origins),
+		requireScope(scopeIngest),
+		atLeastOneAuth(
+			bearerAuthorizerCtxSetToken(getSecretKey),
+			apiKeyAuthorizerCtxSetKey(l, q),
+		),
+		withPromCounter(prcounter),
+	))
+
+	// kaggle competition metrics; these are uploaded through POST /metrics
+	mux.HandleFunc("GET /kaggle/competition", stools.AdaptHandler(
+		handleGetTimeSeriesByKind(l, q, kt.RequestKindKaggleCompetition),
 		apiMode(l, maxBytes, headers, methods, origins),
-		atLeastOneAuth(bearerAuthorizerCtxSetToken(getSecretKey)),
+		requireScope(scopeRead),
+		atLeastOneAuth(
+			bearerAuthorizerCtxSetToken(getSecretKey),
+			apiKeyAuthorizerCtxSetKey(l, q),
+		),
 		withPromCounter(prcounter),
 	))
 
 	// youtube video metrics
 	mux.HandleFunc("GET /youtube/video", stools.AdaptHandler(
-		handleYouTubeVideoMetricsGet(l, q),
+		handleGetTimeSeriesByKind(l, q, kt.RequestKindYouTubeVideo),
 		apiMode(l, maxBytes, headers, methods, origins),
-		atLeastOneAuth(bearerAuthorizerCtxSetToken(getSecretKey)),
+		requireScope(scopeRead),
+		atLeastOneAuth(
+			bearerAuthorizerCtxSetToken(getSecretKey),
+			apiKeyAuthorizerCtxSetKey(l, q),
+		),
 		withPromCounter(prcounter),
 	))
 	mux.HandleFunc("POST /youtube/video", stools.AdaptHandler(
-		handleYouTubeVideoMetricsPost(l, q),
+		handleYouTubeVideoMetricsPost(l, q, al),
 		apiMode(l, maxBytes, headers, methods, origins),
-		atLeastOneAuth(bearerAuthorizerCtxSetToken(getSecretKey)),
+		requireScope(scopeIngest),
+		atLeastOneAuth(
+			bearerAuthorizerCtxSetToken(getSecretKey),
+			apiKeyAuthorizerCtxSetKey(l, q),
+		),
 		withPromCounter(prcounter),
 	))
 
 	// youtube channel metrics
 	mux.HandleFunc("GET /youtube/channel", stools.AdaptHandler(
-		handleYouTubeChannelMetricsGet(l, q),
+		handleGetTimeSeriesByKind(l, q, kt.RequestKindYouTubeChannel),
 		apiMode(l, maxBytes, headers, methods, origins),
-		atLeastOneAuth(bearerAuthorizerCtxSetToken(getSecretKey)),
+		requireScope(scopeRead),
+		atLeastOneAuth(
+			bearerAuthorizerCtxSetToken(getSecretKey),
+			apiKeyAuthorizerCtxSetKey(l, q),
+		),
 		withPromCounter(prcounter),
 	))
 	mux.HandleFunc("POST /youtube/channel", stools.AdaptHandler(
-		handleYouTubeChannelMetricsPost(l, q),
+		handleYouTubeChannelMetricsPost(l, q, al),
 		apiMode(l, maxBytes, headers, methods, origins),
-		atLeastOneAuth(bearerAuthorizerCtxSetToken(getSecretKey)),
+		requireScope(scopeIngest),
+		atLeastOneAuth(
+			bearerAuthorizerCtxSetToken(getSecretKey),
+			apiKeyAuthorizerCtxSetKey(l, q),
+		),
 		withPromCounter(prcounter),
 	))
 
 	// reddit post metrics
 	mux.HandleFunc("GET /reddit/post", stools.AdaptHandler(
-		handleRedditPostMetricsGet(l, q),
+		handleGetTimeSeriesByKind(l, q, kt.RequestKindRedditPost),
 		apiMode(l, maxBytes, headers, methods, origins),
-		atLeastOneAuth(bearerAuthorizerCtxSetToken(getSecretKey)),
+		requireScope(scopeRead),
+		atLeastOneAuth(
+			bearerAuthorizerCtxSetToken(getSecretKey),
+			apiKeyAuthorizerCtxSetKey(l, q),
+		),
 		withPromCounter(prcounter),
 	))
 	mux.HandleFunc("POST /reddit/post", stools.AdaptHandler(
-		handleRedditPostMetricsPost(l, q, pms),
+		handleRedditPostMetricsPost(l, q, al, pms),
 		apiMode(l, maxBytes, headers, methods, origins),
-		atLeastOneAuth(bearerAuthorizerCtxSetToken(getSecretKey)),
+		requireScope(scopeIngest),
+		atLeastOneAuth(
+			bearerAuthorizerCtxSetToken(getSecretKey),
+			apiKeyAuthorizerCtxSetKey(l, q),
+		),
 		withPromCounter(prcounter),
 	))
 
 	// reddit comment metrics
 	mux.HandleFunc("GET /reddit/comment", stools.AdaptHandler(
-		handleRedditCommentMetricsGet(l, q),
+		handleGetTimeSeriesByKind(l, q, kt.RequestKindRedditComment),
 		apiMode(l, maxBytes, headers, methods, origins),
-		atLeastOneAuth(bearerAuthorizerCtxSetToken(getSecretKey)),
+		requireScope(scopeRead),
+		atLeastOneAuth(
+			bearerAuthorizerCtxSetToken(getSecretKey),
+			apiKeyAuthorizerCtxSetKey(l, q),
+		),
 		withPromCounter(prcounter),
 	))
 	mux.HandleFunc("POST /reddit/comment", stools.AdaptHandler(
-		handleRedditCommentMetricsPost(l, q, pms),
+		handleRedditCommentMetricsPost(l, q, al, pms),
 		apiMode(l, maxBytes, headers, methods, origins),
-		atLeastOneAuth(bearerAuthorizerCtxSetToken(getSecretKey)),
+		requireScope(scopeIngest),
+		atLeastOneAuth(
+			bearerAuthorizerCtxSetToken(getSecretKey),
+			apiKeyAuthorizerCtxSetKey(l, q),
+		),
 		withPromCounter(prcounter),
 	))
 
 	// reddit subreddit metrics
 	mux.HandleFunc("GET /reddit/subreddit", stools.AdaptHandler(
-		handleRedditSubredditMetricsGet(l, q),
+		handleGetTimeSeriesByKind(l, q, kt.RequestKindRedditSubreddit),
 		apiMode(l, maxBytes, headers, methods, origins),
-		atLeastOneAuth(bearerAuthorizerCtxSetToken(getSecretKey)),
+		requireScope(scopeRead),
+		atLeastOneAuth(
+			bearerAuthorizerCtxSetToken(getSecretKey),
+			apiKeyAuthorizerCtxSetKey(l, q),
+		),
 		withPromCounter(prcounter),
 	))
 	mux.HandleFunc("POST /reddit/subreddit", stools.AdaptHandler(
-		handleRedditSubredditMetricsPost(l, q, pms),
+		handleRedditSubredditMetricsPost(l, q, al, pms),
 		apiMode(l, maxBytes, headers, methods, origins),
-		atLeastOneAuth(bearerAuthorizerCtxSetToken(getSecretKey)),
+		requireScope(scopeIngest),
+		atLeastOneAuth(
+			bearerAuthorizerCtxSetToken(getSecretKey),
+			apiKeyAuthorizerCtxSetKey(l, q),
+		),
 		withPromCounter(prcounter),
 	))
 
 	// reddit user metrics
 	mux.HandleFunc("GET /reddit/user", stools.AdaptHandler(
-		handleRedditUserMetricsGet(l, q),
+		handleGetTimeSeriesByKind(l, q, kt.RequestKindRedditUser),
 		apiMode(l, maxBytes, headers, methods, origins),
-		atLeastOneAuth(bearerAuthorizerCtxSetToken(getSecretKey)),
+		requireScope(scopeRead),
+		atLeastOneAuth(
+			bearerAuthorizerCtxSetToken(getSecretKey),
+			apiKeyAuthorizerCtxSetKey(l, q),
+		),
 		withPromCounter(prcounter),
 	))
 	mux.HandleFunc("POST /reddit/user", stools.AdaptHandler(
-		handleRedditUserMetricsPost(l, q, pms),
+		handleRedditUserMetricsPost(l, q, al, pms),
 		apiMode(l, maxBytes, headers, methods, origins),
-		atLeastOneAuth(bearerAuthorizerCtxSetToken(getSecretKey)),
+		requireScope(scopeIngest),
+		atLeastOneAuth(
+			bearerAuthorizerCtxSetToken(getSecretKey),
+			apiKeyAuthorizerCtxSetKey(l, q),
+		),
 		withPromCounter(prcounter),
 	))
 
 	// twitch clip metrics
+	mux.HandleFunc("GET /twitch/clip", stools.AdaptHandler(
+		handleGetTimeSeriesByKind(l, q, kt.RequestKindTwitchClip),
+		apiMode(l, maxBytes, headers, methods, origins),
+		requireScope(scopeRead),
+		atLeastOneAuth(
+			bearerAuthorizerCtxSetToken(getSecretKey),
+			apiKeyAuthorizerCtxSetKey(l, q),
+		),
+		withPromCounter(prcounter),
+	))
 	mux.HandleFunc("POST /twitch/clip", stools.AdaptHandler(
-		handleTwitchClipMetricsPost(l, q, pms),
+		handleTwitchClipMetricsPost(l, q, al, pms),
 		apiMode(l, maxBytes, headers, methods, origins),
-		atLeastOneAuth(bearerAuthorizerCtxSetToken(getSecretKey)),
+		requireScope(scopeIngest),
+		atLeastOneAuth(
+			bearerAuthorizerCtxSetToken(getSecretKey),
+			apiKeyAuthorizerCtxSetKey(l, q),
+		),
 		withPromCounter(prcounter),
 	))
 
 	// twitch video metrics
+	mux.HandleFunc("GET /twitch/video", stools.AdaptHandler(
+		handleGetTimeSeriesByKind(l, q, kt.RequestKindTwitchVideo),
+		apiMode(l, maxBytes, headers, methods, origins),
+		requireScope(scopeRead),
+		atLeastOneAuth(
+			bearerAuthorizerCtxSetToken(getSecretKey),
+			apiKeyAuthorizerCtxSetKey(l, q),
+		),
+		withPromCounter(prcounter),
+	))
 	mux.HandleFunc("POST /twitch/video", stools.AdaptHandler(
-		handleTwitchVideoMetricsPost(l, q, pms),
+		handleTwitchVideoMetricsPost(l, q, al, pms),
 		apiMode(l, maxBytes, headers, methods, origins),
-		atLeastOneAuth(bearerAuthorizerCtxSetToken(getSecretKey)),
+		requireScope(scopeIngest),
+		atLeastOneAuth(
+			bearerAuthorizerCtxSetToken(getSecretKey),
+			apiKeyAuthorizerCtxSetKey(l, q),
+		),
 		withPromCounter(prcounter),
 	))
 
 	// twitch stream metrics
+	mux.HandleFunc("GET /twitch/stream", stools.AdaptHandler(
+		handleGetTimeSeriesByKind(l, q, kt.RequestKindTwitchStream),
+		apiMode(l, maxBytes, headers, methods, origins),
+		requireScope(scopeRead),
+		atLeastOneAuth(
+			bearerAuthorizerCtxSetToken(getSecretKey),
+			apiKeyAuthorizerCtxSetKey(l, q),
+		),
+		withPromCounter(prcounter),
+	))
 	mux.HandleFunc("POST /twitch/stream", stools.AdaptHandler(
-		handleTwitchStreamMetricsPost(l, q, pms),
+		handleTwitchStreamMetricsPost(l, q, al, pms),
 		apiMode(l, maxBytes, headers, methods, origins),
-		atLeastOneAuth(bearerAuthorizerCtxSetToken(getSecretKey)),
+		requireScope(scopeIngest),
+		atLeastOneAuth(
+			bearerAuthorizerCtxSetToken(getSecretKey),
+			apiKeyAuthorizerCtxSetKey(l, q),
+		),
 		withPromCounter(prcounter),
 	))
 
 	// twitch user-past-dec metrics
+	mux.HandleFunc("GET /twitch/user-past-dec", stools.AdaptHandler(
+		handleGetTimeSeriesByKind(l, q, kt.RequestKindTwitchUserPastDec),
+		apiMode(l, maxBytes, headers, methods, origins),
+		requireScope(scopeRead),
+		atLeastOneAuth(
+			bearerAuthorizerCtxSetToken(getSecretKey),
+			apiKeyAuthorizerCtxSetKey(l, q),
+		),
+		withPromCounter(prcounter),
+	))
 	mux.HandleFunc("POST /twitch/user-past-dec", stools.AdaptHandler(
-		handleTwitchUserPastDecMetricsPost(l, q, pms),
+		handleTwitchUserPastDecMetricsPost(l, q, al, pms),
 		apiMode(l, maxBytes, headers, methods, origins),
-		atLeastOneAuth(bearerAuthorizerCtxSetToken(getSecretKey)),
+		requireScope(scopeIngest),
+		atLeastOneAuth(
+			bearerAuthorizerCtxSetToken(getSecretKey),
+			apiKeyAuthorizerCtxSetKey(l, q),
+		),
+		withPromCounter(prcounter),
+	))
+
+	// generic metric upload; this shares a path with the prometheus handler
+	// above, but that one is only ever scraped with GET
+	mux.HandleFunc("POST /metrics", stools.AdaptHandler(
+		handleMetricsPost(l, q, al),
+		apiMode(l, maxBytes, headers, methods, origins),
+		requireScope(scopeIngest),
+		atLeastOneAuth(
+			bearerAuthorizerCtxSetToken(getSecretKey),
+			apiKeyAuthorizerCtxSetKey(l, q),
+		),
 		withPromCounter(prcounter),
 	))
 
@@ -454,13 +997,21 @@ func getRouter(
 	mux.HandleFunc("GET /timeseries/raw", stools.AdaptHandler(
 		handleGetTimeSeriesByIDs(l, q),
 		apiMode(l, maxBytes, headers, methods, origins),
-		atLeastOneAuth(bearerAuthorizerCtxSetToken(getSecretKey)),
+		requireScope(scopeRead),
+		atLeastOneAuth(
+			bearerAuthorizerCtxSetToken(getSecretKey),
+			apiKeyAuthorizerCtxSetKey(l, q),
+		),
 		withPromCounter(prcounter),
 	))
 	mux.HandleFunc("GET /timeseries/bucketed", stools.AdaptHandler(
 		handleGetTimeSeriesByIDsBucketed(l, q),
 		apiMode(l, maxBytes, headers, methods, origins),
-		atLeastOneAuth(bearerAuthorizerCtxSetToken(getSecretKey)),
+		requireScope(scopeRead),
+		atLeastOneAuth(
+			bearerAuthorizerCtxSetToken(getSecretKey),
+			apiKeyAuthorizerCtxSetKey(l, q),
+		),
 		withPromCounter(prcounter),
 	))
 
@@ -468,7 +1019,11 @@ func getRouter(
 	mux.HandleFunc("GET /notification/youtube/targets", stools.AdaptHandler(
 		handleGetYouTubeWebSubTargets(l, q),
 		apiMode(l, maxBytes, headers, methods, origins),
-		atLeastOneAuth(bearerAuthorizerCtxSetToken(getSecretKey)),
+		requireScope(scopeIngest),
+		atLeastOneAuth(
+			bearerAuthorizerCtxSetToken(getSecretKey),
+			apiKeyAuthorizerCtxSetKey(l, q),
+		),
 		withPromCounter(prcounter),
 	))
 	mux.HandleFunc("GET /notification/youtube/websub", stools.AdaptHandler(
@@ -484,7 +1039,11 @@ func getRouter(
 	mux.HandleFunc("POST /run-youtube-listener-wf", stools.AdaptHandler(
 		handleRunYouTubeListener(l, q, tc),
 		apiMode(l, maxBytes, headers, methods, origins),
-		atLeastOneAuth(bearerAuthorizerCtxSetToken(getSecretKey)),
+		requireScope(scopeScheduleAdmin),
+		atLeastOneAuth(
+			bearerAuthorizerCtxSetToken(getSecretKey),
+			apiKeyAuthorizerCtxSetKey(l, q),
+		),
 		withPromCounter(prcounter),
 	))
 
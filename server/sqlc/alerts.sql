@@ -0,0 +1,44 @@
+-- name: InsertAlertRule :one
+INSERT INTO alert_rules (email, request_kind, id, metric, condition, params, notifier, target, cooldown_seconds)
+VALUES (@email, @request_kind, @id, @metric, @condition, @params, @notifier, @target, @cooldown_seconds)
+RETURNING *;
+
+-- name: GetAlertRules :many
+SELECT *
+FROM alert_rules
+WHERE email = @email
+ORDER BY rule_id;
+
+-- name: GetAlertRulesForMetric :many
+SELECT *
+FROM alert_rules
+WHERE
+    enabled AND
+    request_kind = @request_kind AND
+    LOWER(id) = LOWER(@id) AND
+    metric = @metric;
+
+-- name: DeleteAlertRules :exec
+DELETE FROM alert_rules
+WHERE email = @email AND rule_id = ANY(@rule_ids::BIGINT[]);
+
+-- Marks the rule as fired at ts, but only if that's outside its cooldown;
+-- nothing is returned if it isn't.
+-- name: SetAlertRuleLastFired :one
+UPDATE alert_rules
+SET ts_last_fired = @ts
+WHERE
+    rule_id = @rule_id AND
+    (ts_last_fired IS NULL OR ts_last_fired <= @ts - make_interval(secs => cooldown_seconds))
+RETURNING rule_id;
+
+-- name: InsertAlertHistory :exec
+INSERT INTO alert_history (rule_id, ts, value, message, error)
+VALUES (@rule_id, @ts, @value, @message, @error);
+
+-- name: GetAlertHistory :many
+SELECT h.rule_id, h.ts, h.value, h.message, h.error, r.request_kind, r.id, r.metric
+FROM alert_history h
+INNER JOIN alert_rules r ON h.rule_id = r.rule_id
+WHERE r.email = @email AND h.ts >= @ts_start
+ORDER BY h.ts DESC;
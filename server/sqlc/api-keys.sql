@@ -0,0 +1,31 @@
+-- name: InsertAPIKey :one
+INSERT INTO api_keys (email, name, key_prefix, key_hash, scopes, ts_expires)
+VALUES (@email, @name, @key_prefix, @key_hash, @scopes, @ts_expires)
+RETURNING key_id, email, name, key_prefix, scopes, ts_created, ts_expires, ts_last_used, ts_revoked;
+
+-- name: GetAPIKeys :many
+SELECT key_id, email, name, key_prefix, scopes, ts_created, ts_expires, ts_last_used, ts_revoked
+FROM api_keys
+WHERE email = @email OR @email::VARCHAR = ''
+ORDER BY key_id;
+
+-- Returns the key if it's usable (i.e., not revoked or expired).
+-- name: GetActiveAPIKeyByHash :one
+SELECT *
+FROM api_keys
+WHERE
+    key_hash = @key_hash AND
+    ts_revoked IS NULL AND
+    (ts_expires IS NULL OR ts_expires > NOW());
+
+-- This is called on every authenticated request, so only write if the last
+-- use is stale.
+-- name: SetAPIKeyLastUsed :exec
+UPDATE api_keys
+SET ts_last_used = NOW()
+WHERE key_id = @key_id AND (ts_last_used IS NULL OR ts_last_used < NOW() - INTERVAL '1 minute');
+
+-- name: RevokeAPIKeys :exec
+UPDATE api_keys
+SET ts_revoked = NOW()
+WHERE key_id = ANY(@key_ids::BIGINT[]) AND ts_revoked IS NULL;
@@ -14,6 +14,14 @@ SELECT id, request_kind, data
 FROM metadata
 WHERE request_kind = @request_kind AND LOWER(id) = LOWER(@id);
 
+-- Marks the entity as no longer tracked; see jsonb.MetadataJSON.Status.
+-- name: SetMetadataStatus :execrows
+UPDATE metadata
+SET data = data || jsonb_build_object(
+    'status', @status::VARCHAR,
+    'status_reason', @status_reason::VARCHAR,
+    'ts_ended', @ts_ended::TIMESTAMPTZ)
+WHERE request_kind = @request_kind AND LOWER(id) = LOWER(@id);
 
 -- name: GetChildrenMetadataByID :many
 SELECT
@@ -28,7 +36,7 @@ LEFT JOIN (
 		id AS id,
         m2.request_kind AS request_kind,
 		m2."data" AS "data",
-		m2."data" ->> 'parent_user_name' AS parent_id
+		m2."data" ->> @parent_field::TEXT AS parent_id
 	FROM metadata m2
 	WHERE m2.request_kind = @child_request_kind
 ) children ON m.id = children.parent_id
@@ -0,0 +1,73 @@
+-- Samples that were already written with the same idempotency key are
+-- dropped; the returned row count is 0 for those.
+-- name: InsertMetricSample :execrows
+INSERT INTO metric_samples (request_kind, id, metric, ts, value, idempotency_key)
+VALUES (@request_kind, @id, @metric, @ts, @value, @idempotency_key)
+ON CONFLICT DO NOTHING;
+
+-- Bulk load for workers that write directly to the database. COPY can't skip
+-- conflicting rows, so a batch containing a sample that was already written
+-- fails as a whole; the caller falls back to InsertMetricSample.
+-- name: CopyMetricSamples :copyfrom
+INSERT INTO metric_samples (request_kind, id, metric, ts, value, idempotency_key)
+VALUES (@request_kind, @id, @metric, @ts, @value, @idempotency_key);
+
+-- name: GetMetricSamplesByIDs :many
+SELECT
+    m.id AS "id",
+    m.ts AS "ts",
+    m.value AS "value",
+    (m.request_kind || '.' || m.metric)::VARCHAR AS "metric"
+FROM metric_samples AS m
+WHERE
+    m.request_kind = @request_kind AND
+    m.id ILIKE ANY(@ids::VARCHAR[]) AND
+    m.ts >= @ts_start AND
+    m.ts <= @ts_end
+ORDER BY m.id, m.metric, m.ts;
+
+-- Bucketed metric samples. The bucket width is any postgres interval (e.g.,
+-- '15 minutes') and the aggregate is one of last, max, min, avg, or delta.
+-- The delta is the change in the last value from the previous bucket, so the
+-- first bucket in the range is omitted for that aggregate.
+-- name: GetMetricSamplesByIDsBucketed :many
+SELECT tab.id, tab.bucket, tab.value::DOUBLE PRECISION AS "value", tab.metric
+FROM (
+    SELECT
+        m.id AS "id",
+        time_bucket(@bucket_width::INTERVAL, m.ts) AS "bucket",
+        CASE @aggregate::VARCHAR
+            WHEN 'max' THEN MAX(m.value)
+            WHEN 'min' THEN MIN(m.value)
+            WHEN 'avg' THEN AVG(m.value)
+            WHEN 'delta' THEN last(m.value, m.ts) - LAG(last(m.value, m.ts)) OVER (
+                PARTITION BY m.id, m.metric ORDER BY MIN(m.ts))
+            ELSE last(m.value, m.ts)
+        END AS "value",
+        (m.request_kind || '.' || m.metric)::VARCHAR AS "metric"
+    FROM metric_samples AS m
+    WHERE
+        m.request_kind = @request_kind AND
+        m.id ILIKE ANY(@ids::VARCHAR[]) AND
+        m.ts >= @ts_start::TIMESTAMPTZ AND
+        m.ts <= @ts_end::TIMESTAMPTZ
+    GROUP BY m.id, "bucket", m.request_kind, m.metric
+) AS tab
+WHERE tab.value IS NOT NULL
+ORDER BY tab.id, tab.metric, tab.bucket;
+
+-- Summary of the samples for a single metric in [ts_start, ts_end); used to
+-- evaluate alert rules against a lookback window.
+-- name: GetMetricSampleWindowStats :one
+SELECT
+    COUNT(*)::INTEGER AS "count",
+    COALESCE(first(m.value, m.ts), 0)::DOUBLE PRECISION AS "first",
+    COALESCE(AVG(m.value), 0)::DOUBLE PRECISION AS "mean",
+    COALESCE(STDDEV_SAMP(m.value), 0)::DOUBLE PRECISION AS "stddev"
+FROM metric_samples AS m
+WHERE
+    m.request_kind = @request_kind AND
+    LOWER(m.id) = LOWER(@id) AND
+    m.metric = @metric AND
+    m.ts >= @ts_start::TIMESTAMPTZ AND
+    m.ts < @ts_end::TIMESTAMPTZ;
@@ -0,0 +1,15 @@
+-- name: GetOAuthToken :one
+SELECT *
+FROM oauth_tokens
+WHERE token_key = @token_key;
+
+-- Serializes refreshes of the token across workers until the end of the
+-- transaction.
+-- name: LockOAuthToken :exec
+SELECT pg_advisory_xact_lock(hashtext(@token_key));
+
+-- name: UpsertOAuthToken :exec
+INSERT INTO oauth_tokens (token_key, access_token, ts_expires)
+VALUES (@token_key, @access_token, @ts_expires)
+ON CONFLICT (token_key) DO UPDATE
+SET access_token = EXCLUDED.access_token, ts_expires = EXCLUDED.ts_expires, ts_updated = NOW();
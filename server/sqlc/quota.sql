@@ -0,0 +1,21 @@
+-- Charges the key, but only if that keeps it within its budget; nothing is
+-- returned if it doesn't. That goes for the first charge of a period, too.
+-- name: ChargeQuota :one
+INSERT INTO quota_ledger (quota_key, period, spent)
+SELECT @quota_key::VARCHAR, @period::DATE, @cost::BIGINT
+WHERE @cost::BIGINT <= @budget::BIGINT
+ON CONFLICT (quota_key, period) DO UPDATE
+SET spent = quota_ledger.spent + EXCLUDED.spent, ts_updated = NOW()
+WHERE quota_ledger.spent + EXCLUDED.spent <= @budget::BIGINT
+RETURNING spent;
+
+-- name: GetQuotaLedger :many
+SELECT *
+FROM quota_ledger
+WHERE period >= @period_start
+ORDER BY period DESC, quota_key;
+
+-- name: GetQuotaSpend :many
+SELECT quota_key, spent
+FROM quota_ledger
+WHERE period = @period AND quota_key = ANY(@quota_keys::VARCHAR[]);
@@ -0,0 +1,38 @@
+-- name: GetRateLimitBuckets :many
+SELECT *
+FROM rate_limit_buckets
+ORDER BY bucket_key;
+
+-- Locks the bucket for the rest of the transaction so concurrent acquires
+-- can't spend the same tokens.
+-- name: GetRateLimitBucketForUpdate :one
+SELECT *
+FROM rate_limit_buckets
+WHERE bucket_key = @bucket_key
+FOR UPDATE;
+
+-- name: SetRateLimitBucketTokens :exec
+UPDATE rate_limit_buckets
+SET tokens = @tokens, ts_updated = @ts_updated
+WHERE bucket_key = @bucket_key;
+
+-- name: RecordRateLimitBucketLimited :exec
+UPDATE rate_limit_buckets
+SET
+    delayed = delayed + @delayed,
+    skipped = skipped + @skipped,
+    last_reason = @last_reason,
+    ts_last_limited = NOW()
+WHERE bucket_key = @bucket_key;
+
+-- The provider's view of the limit always wins over our estimate.
+-- name: UpsertRateLimitBucket :exec
+INSERT INTO rate_limit_buckets (bucket_key, capacity, tokens, refill_rate, ts_updated, ts_reset)
+VALUES (@bucket_key, @capacity, @tokens, @refill_rate, @ts_updated, @ts_reset)
+ON CONFLICT (bucket_key) DO UPDATE
+SET
+    capacity = EXCLUDED.capacity,
+    tokens = EXCLUDED.tokens,
+    refill_rate = EXCLUDED.refill_rate,
+    ts_updated = EXCLUDED.ts_updated,
+    ts_reset = EXCLUDED.ts_reset;
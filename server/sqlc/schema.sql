@@ -239,3 +239,107 @@ CREATE TABLE IF NOT EXISTS twitch_user_past_dec_std_duration (
 CREATE TABLE IF NOT EXISTS youtube_channel_subscriptions (
     id VARCHAR(255) PRIMARY KEY NOT NULL
 );
+
+-- generic metric samples; metric is the short metric name (e.g., "score")
+CREATE TABLE IF NOT EXISTS metric_samples (
+    request_kind VARCHAR(255) NOT NULL,
+    id VARCHAR(255) NOT NULL,
+    metric VARCHAR(255) NOT NULL,
+    ts TIMESTAMPTZ NOT NULL,
+    value DOUBLE PRECISION NOT NULL,
+    idempotency_key VARCHAR(255)
+);
+CREATE UNIQUE INDEX IF NOT EXISTS metric_samples_sample
+    ON metric_samples (request_kind, id, metric, ts);
+
+-- per user alert rules
+CREATE TABLE IF NOT EXISTS alert_rules (
+    rule_id BIGSERIAL PRIMARY KEY,
+    email VARCHAR(255) NOT NULL REFERENCES users ON DELETE CASCADE,
+    request_kind VARCHAR(255) NOT NULL,
+    id VARCHAR(255) NOT NULL,
+    metric VARCHAR(255) NOT NULL,
+    condition VARCHAR(255) NOT NULL,
+    params JSONB NOT NULL DEFAULT '{}'::JSONB,
+    notifier VARCHAR(255) NOT NULL,
+    target VARCHAR(1024) NOT NULL,
+    cooldown_seconds INTEGER NOT NULL DEFAULT 3600,
+    enabled BOOLEAN NOT NULL DEFAULT TRUE,
+    ts_created TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    ts_last_fired TIMESTAMPTZ
+);
+
+-- alert firings
+CREATE TABLE IF NOT EXISTS alert_history (
+    rule_id BIGINT NOT NULL REFERENCES alert_rules ON DELETE CASCADE,
+    ts TIMESTAMPTZ NOT NULL,
+    value DOUBLE PRECISION NOT NULL,
+    message TEXT NOT NULL,
+    error TEXT NOT NULL DEFAULT ''
+);
+
+-- per user webhook endpoints
+CREATE TABLE IF NOT EXISTS webhooks (
+    webhook_id BIGSERIAL PRIMARY KEY,
+    email VARCHAR(255) NOT NULL REFERENCES users ON DELETE CASCADE,
+    url VARCHAR(1024) NOT NULL,
+    secret VARCHAR(255) NOT NULL,
+    events VARCHAR(255)[] NOT NULL DEFAULT '{}',
+    enabled BOOLEAN NOT NULL DEFAULT TRUE,
+    ts_created TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- undeliverable webhook events
+CREATE TABLE IF NOT EXISTS webhook_dead_letters (
+    webhook_id BIGINT NOT NULL REFERENCES webhooks ON DELETE CASCADE,
+    event_id VARCHAR(255) NOT NULL,
+    event_type VARCHAR(255) NOT NULL,
+    payload TEXT NOT NULL,
+    error TEXT NOT NULL,
+    ts TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- hashed api keys
+CREATE TABLE IF NOT EXISTS api_keys (
+    key_id BIGSERIAL PRIMARY KEY,
+    email VARCHAR(255) NOT NULL REFERENCES users ON DELETE CASCADE,
+    name VARCHAR(255) NOT NULL DEFAULT '',
+    key_prefix VARCHAR(16) NOT NULL,
+    key_hash VARCHAR(64) NOT NULL UNIQUE,
+    scopes VARCHAR(255)[] NOT NULL DEFAULT '{}',
+    ts_created TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    ts_expires TIMESTAMPTZ,
+    ts_last_used TIMESTAMPTZ,
+    ts_revoked TIMESTAMPTZ
+);
+
+-- provider rate limit token buckets
+CREATE TABLE IF NOT EXISTS rate_limit_buckets (
+    bucket_key VARCHAR(255) PRIMARY KEY,
+    capacity DOUBLE PRECISION NOT NULL,
+    tokens DOUBLE PRECISION NOT NULL,
+    refill_rate DOUBLE PRECISION NOT NULL,
+    ts_updated TIMESTAMPTZ NOT NULL,
+    ts_reset TIMESTAMPTZ NOT NULL,
+    delayed BIGINT NOT NULL DEFAULT 0,
+    skipped BIGINT NOT NULL DEFAULT 0,
+    last_reason TEXT NOT NULL DEFAULT '',
+    ts_last_limited TIMESTAMPTZ
+);
+
+-- api quota spend per key per period
+CREATE TABLE IF NOT EXISTS quota_ledger (
+    quota_key VARCHAR(255) NOT NULL,
+    period DATE NOT NULL,
+    spent BIGINT NOT NULL DEFAULT 0,
+    ts_updated TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (quota_key, period)
+);
+
+-- oauth access tokens shared by the workers
+CREATE TABLE IF NOT EXISTS oauth_tokens (
+    token_key VARCHAR(255) PRIMARY KEY,
+    access_token TEXT NOT NULL,
+    ts_expires TIMESTAMPTZ NOT NULL,
+    ts_updated TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
@@ -40,3 +40,13 @@ INNER JOIN users_metadata_through umt ON u.email = umt.email
 INNER JOIN metadata m ON umt.id = m.id AND umt.request_kind = m.request_kind
 WHERE u.email = @email
 ORDER BY m.request_kind, m.id;
+
+-- name: GetUserGrants :many
+SELECT request_kind, id
+FROM users_metadata_through
+WHERE email = @email;
+
+-- name: CountMetricGrants :one
+SELECT COUNT(*)
+FROM users_metadata_through
+WHERE request_kind = @request_kind AND LOWER(id) = ANY(@ids::VARCHAR[]);
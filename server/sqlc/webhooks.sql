@@ -0,0 +1,47 @@
+-- name: InsertWebhook :one
+INSERT INTO webhooks (email, url, secret, events)
+VALUES (@email, @url, @secret, @events)
+RETURNING *;
+
+-- name: GetWebhooks :many
+SELECT webhook_id, email, url, events, enabled, ts_created
+FROM webhooks
+WHERE email = @email
+ORDER BY webhook_id;
+
+-- Returns the enabled webhooks subscribed to the event type whose owners track
+-- the event's entity or its parent (e.g., the channel a new video was posted
+-- to).
+-- name: GetWebhooksForEvent :many
+SELECT *
+FROM webhooks
+WHERE
+    enabled AND
+    (cardinality(events) = 0 OR @event_type::VARCHAR = ANY(events)) AND
+    email IN (
+        SELECT umt.email
+        FROM users_metadata_through umt
+        WHERE
+            (umt.request_kind = @request_kind AND LOWER(umt.id) = LOWER(@id)) OR
+            (umt.request_kind = @parent_request_kind AND LOWER(umt.id) = LOWER(@parent_id))
+    );
+
+-- name: GetWebhookSecret :one
+SELECT secret
+FROM webhooks
+WHERE webhook_id = @webhook_id AND enabled;
+
+-- name: DeleteWebhooks :exec
+DELETE FROM webhooks
+WHERE email = @email AND webhook_id = ANY(@webhook_ids::BIGINT[]);
+
+-- name: InsertWebhookDeadLetter :exec
+INSERT INTO webhook_dead_letters (webhook_id, event_id, event_type, payload, error)
+VALUES (@webhook_id, @event_id, @event_type, @payload, @error);
+
+-- name: GetWebhookDeadLetters :many
+SELECT d.webhook_id, d.event_id, d.event_type, d.payload, d.error, d.ts, w.url
+FROM webhook_dead_letters d
+INNER JOIN webhooks w ON d.webhook_id = w.webhook_id
+WHERE w.email = @email AND d.ts >= @ts_start
+ORDER BY d.ts DESC;
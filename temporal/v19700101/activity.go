@@ -8,7 +8,6 @@ import (
 	"io"
 	"net/http"
 	"net/url"
-	"os"
 	"time"
 
 	"github.com/brojonat/kaggo/server/api"
@@ -20,6 +19,7 @@ const (
 	RequestKindInternalRandom         = "internal.random"
 	RequestKindKaggleNotebook         = "kaggle.notebook"
 	RequestKindKaggleDataset          = "kaggle.dataset"
+	RequestKindKaggleCompetition      = "kaggle.competition"
 	RequestKindYouTubeVideo           = "youtube.video"
 	RequestKindYouTubeChannel         = "youtube.channel"
 	RequestKindRedditPost             = "reddit.post"
@@ -37,38 +37,32 @@ const (
 	MetricXRatelimitUsed      = "x-ratelimit-used"
 	MetricXRatelimitRemaining = "x-ratelimit-remaining"
 	MetricXRatelimitReset     = "x-ratelimit-reset"
+	MetricRateLimitDelayed    = "rate-limit-delayed"
+	MetricRateLimitSkipped    = "rate-limit-skipped"
+	MetricQuotaRemaining      = "quota-remaining"
+	MetricQuotaSkipped        = "quota-skipped"
 )
 
-func GetSupportedRequestKinds() []string {
-	return []string{
-		RequestKindInternalRandom,
-		RequestKindKaggleNotebook,
-		RequestKindKaggleDataset,
-		RequestKindYouTubeVideo,
-		RequestKindYouTubeChannel,
-		RequestKindRedditPost,
-		RequestKindRedditComment,
-		RequestKindRedditSubreddit,
-		RequestKindRedditSubredditMonitor,
-		RequestKindRedditUser,
-		RequestKindRedditUserMonitor,
-		RequestKindTwitchClip,
-		RequestKindTwitchVideo,
-		RequestKindTwitchStream,
-		RequestKindTwitchUserPastDec,
-	}
-}
-
 type ActivityRedditListener struct{}
 type ActivityYouTubeListener struct{}
 
 type ActivityRequester struct {
-	RedditAuthToken            string
-	RedditAuthTokenExp         time.Time
-	RedditListenerAuthToken    string
-	RedditListenerAuthTokenExp time.Time
-	TwitchAuthToken            string
-	TwitchAuthTokenExp         time.Time
+	// Credentials hands out the OAuth tokens requests are made with. If nil,
+	// tokens are cached in memory.
+	Credentials CredentialProvider
+	// Archive is optional; if set, every response is archived to it (see
+	// archive.go).
+	Archive BlobStore
+	// MetricWriter is optional; if set, metrics are written directly to the
+	// database with it rather than uploaded to the server (see ingest.go).
+	MetricWriter *MetricWriter
+}
+
+func (a *ActivityRequester) credentials() CredentialProvider {
+	if a.Credentials == nil {
+		return defaultCredentialProvider
+	}
+	return a.Credentials
 }
 
 type ErrNoRetry struct {
@@ -86,8 +80,26 @@ func (e ErrNoRetry) Error() string {
 // sort frequently changing parameter should not set on the original request
 // because the original is hashed to create a unique identifier and prevent
 // duplicate schedules.
-func (a *ActivityRequester) prepareRequest(drp DoRequestActRequest) (*http.Request, error) {
-	buf := bufio.NewReader(bytes.NewReader(drp.Serial))
+func (a *ActivityRequester) prepareRequest(ctx context.Context, drp DoRequestActRequest) (*http.Request, error) {
+	r, err := deserializeRequest(drp.Serial)
+	if err != nil {
+		return nil, err
+	}
+	r = r.WithContext(ctx)
+	rks, err := GetRequestKindSpec(drp.RequestKind)
+	if err != nil {
+		return nil, err
+	}
+	if err = rks.Prepare(a, r); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// deserializeRequest parses a request prototype into a request that can be
+// sent.
+func deserializeRequest(serial []byte) (*http.Request, error) {
+	buf := bufio.NewReader(bytes.NewReader(serial))
 	r, err := http.ReadRequest(buf)
 	if err != nil {
 		return nil, fmt.Errorf("error deserializing request: %w", err)
@@ -104,67 +116,48 @@ func (a *ActivityRequester) prepareRequest(drp DoRequestActRequest) (*http.Reque
 
 	r.URL = u
 	r.RequestURI = ""
+	return r, nil
+}
 
-	switch drp.RequestKind {
-	case RequestKindInternalRandom:
-		// for internal requests, just set the authorization token
-		r.Header.Set("Authorization", fmt.Sprintf("Bearer %s", os.Getenv("AUTH_TOKEN")))
-	case
-		RequestKindKaggleNotebook,
-		RequestKindKaggleDataset:
-		// basic auth
-		r.SetBasicAuth(os.Getenv("KAGGLE_USERNAME"), os.Getenv("KAGGLE_API_KEY"))
-	case
-		RequestKindYouTubeVideo,
-		RequestKindYouTubeChannel:
-		// for youtube requests, set the non-identifier query params
-		q := r.URL.Query()
-		q.Set("part", "snippet,contentDetails,statistics")
-		q.Set("key", os.Getenv("YOUTUBE_API_KEY"))
-		r.URL.RawQuery = q.Encode()
+func (a *ActivityRequester) DoRequest(ctx context.Context, drp DoRequestActRequest) (*DoRequestActResult, error) {
+	r, err := a.prepareRequest(ctx, drp)
+	if err != nil {
+		return nil, err
+	}
+	res, err := doRequest(ctx, drp.RequestKind, r)
+	if err != nil {
+		return nil, err
+	}
+	a.archiveResponse(ctx, res, drp.IDs, drp.Metadata, false)
+	return res, nil
+}
 
-	case
-		RequestKindRedditPost,
-		RequestKindRedditComment,
-		RequestKindRedditSubreddit,
-		RequestKindRedditUser:
-		// refresh key and set bearer
-		err = a.ensureValidRedditToken(time.Duration(60 * time.Second))
+// doRequest sends the prepared request and packages up the response. Requests
+// to rate limited providers go through the governor (see ratelimit.go), and
+// requests to providers with a unit quota are charged to the ledger (see
+// quota.go); either may cause the request to be skipped.
+func doRequest(ctx context.Context, rk string, r *http.Request) (*DoRequestActResult, error) {
+	rks, err := GetRequestKindSpec(rk)
+	if err != nil {
+		return nil, err
+	}
+	if rks.Quota != nil {
+		reason, err := chargeQuota(ctx, rk, rks.Quota, r)
 		if err != nil {
 			return nil, err
 		}
-		r.Header.Set("User-Agent", os.Getenv("REDDIT_USER_AGENT"))
-		r.Header.Set("Authorization", "bearer "+a.RedditAuthToken)
-	case
-		RequestKindRedditSubredditMonitor,
-		RequestKindRedditUserMonitor:
-		// refresh key and set bearer
-		err = a.ensureValidRedditListenerToken(time.Duration(60 * time.Second))
-		if err != nil {
-			return nil, err
+		if reason != "" {
+			return &DoRequestActResult{RequestKind: rk, SkipReason: reason}, nil
 		}
-		r.Header.Set("User-Agent", os.Getenv("REDDIT_LISTENER_USER_AGENT"))
-		r.Header.Set("Authorization", "bearer "+a.RedditListenerAuthToken)
-		// FIXME: we should set the `after` param here, but we'd need to stick
-		// a db cursor onto this struct and start tracking the last monitored
-		// post for users and subreddits. Not worth it at the moment.
-	case RequestKindTwitchClip, RequestKindTwitchVideo, RequestKindTwitchStream, RequestKindTwitchUserPastDec:
-		err = a.ensureValidTwitchToken(time.Duration(60 * time.Second))
+	}
+	if rks.RateLimit != nil {
+		reason, err := awaitRateLimit(ctx, rk, rks.RateLimit)
 		if err != nil {
 			return nil, err
 		}
-		r.Header.Set("Client-Id", os.Getenv("TWITCH_CLIENT_ID"))
-		r.Header.Set("Authorization", "Bearer "+a.TwitchAuthToken)
-	default:
-		return nil, fmt.Errorf("unsupported RequestKind %s", drp.RequestKind)
-	}
-	return r, nil
-}
-
-func (a *ActivityRequester) DoRequest(ctx context.Context, drp DoRequestActRequest) (*DoRequestActResult, error) {
-	r, err := a.prepareRequest(drp)
-	if err != nil {
-		return nil, err
+		if reason != "" {
+			return &DoRequestActResult{RequestKind: rk, SkipReason: reason}, nil
+		}
 	}
 
 	resp, err := http.DefaultClient.Do(r)
@@ -176,94 +169,71 @@ func (a *ActivityRequester) DoRequest(ctx context.Context, drp DoRequestActReque
 	if err != nil {
 		return nil, fmt.Errorf("error reading response body: %w", err)
 	}
+	fetchedAt := time.Now()
+	if rks.RateLimit != nil {
+		observeRateLimit(ctx, rks.RateLimit, resp.StatusCode, resp.Header)
+	}
 
 	// return the activity result
 	res := DoRequestActResult{
-		RequestKind:        drp.RequestKind,
+		RequestKind:        rk,
 		ResponseStatusCode: resp.StatusCode,
 		ResponseBody:       b,
 		ResponseHeader:     resp.Header,
+		FetchedAt:          fetchedAt,
+	}
+	if resp.StatusCode != http.StatusOK {
+		res.Failure = classifyFailure(rk, resp.StatusCode, b)
+	}
+	if d, err := http.ParseTime(resp.Header.Get("Date")); err == nil {
+		res.ProviderDate = d
+		checkClockSkew(ctx, rk, fetchedAt, d)
 	}
 	return &res, nil
 }
 
+// Samples are stamped with the worker's clock, so warn if it disagrees with
+// the provider's by more than this.
+const maxProviderClockSkew = 30 * time.Second
+
+func checkClockSkew(ctx context.Context, rk string, fetchedAt, providerDate time.Time) {
+	skew := fetchedAt.Sub(providerDate)
+	if skew.Abs() <= maxProviderClockSkew || !activity.IsActivity(ctx) {
+		return
+	}
+	activity.GetLogger(ctx).Warn(
+		"worker clock disagrees with provider clock", "request_kind", rk,
+		"fetched_at", fetchedAt.Format(time.RFC3339), "provider_date", providerDate.Format(time.RFC3339), "skew", skew.String())
+}
+
 // UploadResponseMetadata handles the result of a DoRequest activity when that
 // request is a metadata request.
 func (a *ActivityRequester) UploadResponseMetadata(ctx context.Context, drr DoRequestActResult) (*api.DefaultJSONResponse, error) {
 	l := activity.GetLogger(ctx)
-	switch drr.RequestKind {
-	case RequestKindInternalRandom:
-		return a.handleInternalRandomMetadata(l, drr.ResponseStatusCode, drr.ResponseBody)
-	case RequestKindYouTubeVideo:
-		return a.handleYouTubeVideoMetadata(l, drr.ResponseStatusCode, drr.ResponseBody)
-	case RequestKindYouTubeChannel:
-		return a.handleYouTubeChannelMetadata(l, drr.ResponseStatusCode, drr.ResponseBody)
-	case RequestKindKaggleNotebook:
-		return a.handleKaggleNotebookMetadata(l, drr.ResponseStatusCode, drr.ResponseBody)
-	case RequestKindKaggleDataset:
-		return a.handleKaggleDatasetMetadata(l, drr.ResponseStatusCode, drr.ResponseBody)
-	case RequestKindRedditPost:
-		return a.handleRedditPostMetadata(l, drr.ResponseStatusCode, drr.ResponseBody)
-	case RequestKindRedditComment:
-		return a.handleRedditCommentMetadata(l, drr.ResponseStatusCode, drr.ResponseBody)
-	case RequestKindRedditSubreddit:
-		return a.handleRedditSubredditMetadata(l, drr.ResponseStatusCode, drr.ResponseBody)
-	case RequestKindRedditSubredditMonitor:
-		return a.handleRedditSubredditMonitorMetadata(l, drr.ResponseStatusCode, drr.ResponseBody)
-	case RequestKindRedditUser:
-		return a.handleRedditUserMetadata(l, drr.ResponseStatusCode, drr.ResponseBody)
-	case RequestKindRedditUserMonitor:
-		return a.handleRedditUserMonitorMetadata(l, drr.ResponseStatusCode, drr.ResponseBody)
-	case RequestKindTwitchClip:
-		return a.handleTwitchClipMetadata(l, drr.ResponseStatusCode, drr.ResponseBody)
-	case RequestKindTwitchVideo:
-		return a.handleTwitchVideoMetadata(l, drr.ResponseStatusCode, drr.ResponseBody)
-	case RequestKindTwitchStream:
-		return a.handleTwitchStreamMetadata(l, drr.ResponseStatusCode, drr.ResponseBody)
-	case RequestKindTwitchUserPastDec:
-		return a.handleTwitchUserPastDecMetadata(l, drr.ResponseStatusCode, drr.ResponseBody)
-	default:
-		return nil, fmt.Errorf("unrecognized RequestKind: %s", drr.RequestKind)
+	rks, err := GetRequestKindSpec(drr.RequestKind)
+	if err != nil {
+		return nil, err
 	}
+	h := rks.metadataHandler()
+	if h == nil {
+		return nil, fmt.Errorf("no metadata handler for RequestKind: %s", drr.RequestKind)
+	}
+	return h(a, ctx, l, drr.ResponseStatusCode, drr.ResponseBody)
 }
 
 // UploadResponseData handles the result of a DoRequest activity
 func (a *ActivityRequester) UploadResponseData(ctx context.Context, drr DoRequestActResult) (*api.DefaultJSONResponse, error) {
 	l := activity.GetLogger(ctx)
-	switch drr.RequestKind {
-	case RequestKindInternalRandom:
-		return a.handleInternalRandomMetrics(l, drr.ResponseStatusCode, drr.ResponseBody)
-	case RequestKindYouTubeVideo:
-		return a.handleYouTubeVideoMetrics(l, drr.ResponseStatusCode, drr.ResponseBody)
-	case RequestKindYouTubeChannel:
-		return a.handleYouTubeChannelMetrics(l, drr.ResponseStatusCode, drr.ResponseBody)
-	case RequestKindKaggleNotebook:
-		return a.handleKaggleNotebookMetrics(l, drr.ResponseStatusCode, drr.ResponseBody)
-	case RequestKindKaggleDataset:
-		return a.handleKaggleDatasetMetrics(l, drr.ResponseStatusCode, drr.ResponseBody)
-	case RequestKindRedditPost:
-		return a.handleRedditPostMetrics(l, drr.ResponseStatusCode, drr.ResponseBody)
-	case RequestKindRedditComment:
-		return a.handleRedditCommentMetrics(l, drr.ResponseStatusCode, drr.ResponseBody)
-	case RequestKindRedditSubreddit:
-		return a.handleRedditSubredditMetrics(l, drr.ResponseStatusCode, drr.ResponseBody)
-	case RequestKindRedditSubredditMonitor:
-		return a.handleRedditSubredditMonitorMetrics(l, drr.ResponseStatusCode, drr.ResponseBody)
-	case RequestKindRedditUser:
-		return a.handleRedditUserMetrics(l, drr.ResponseStatusCode, drr.ResponseBody)
-	case RequestKindRedditUserMonitor:
-		return a.handleRedditUserMonitorMetrics(l, drr.ResponseStatusCode, drr.ResponseBody)
-	case RequestKindTwitchClip:
-		return a.handleTwitchClipMetrics(l, drr.ResponseStatusCode, drr.ResponseBody)
-	case RequestKindTwitchVideo:
-		return a.handleTwitchVideoMetrics(l, drr.ResponseStatusCode, drr.ResponseBody)
-	case RequestKindTwitchStream:
-		return a.handleTwitchStreamMetrics(l, drr.ResponseStatusCode, drr.ResponseBody)
-	case RequestKindTwitchUserPastDec:
-		return a.handleTwitchUserPastDecMetrics(l, drr.ResponseStatusCode, drr.ResponseBody)
-	default:
-		return nil, fmt.Errorf("unrecognized RequestKind: %s", drr.RequestKind)
+	rks, err := GetRequestKindSpec(drr.RequestKind)
+	if err != nil {
+		return nil, err
+	}
+	h := rks.metricsHandler()
+	if h == nil {
+		return nil, fmt.Errorf("no metrics handler for RequestKind: %s", drr.RequestKind)
 	}
+	ctx = withObservedAt(ctx, drr.FetchedAt)
+	return h(a, ctx, l, drr.ResponseStatusCode, drr.ResponseBody)
 }
 
 // UploadMetrics will handle the response from a get metrics request
@@ -273,29 +243,12 @@ func (a *ActivityRequester) SetWorkerMetrics(ctx context.Context, drr DoRequestA
 	// Main entry point to set metrics on every response. Ideally avoid sending
 	// the response off to some other caller that may tamper with it, since
 	// we make no guarantees here.
-	switch drr.RequestKind {
-	case
-		RequestKindRedditSubreddit,
-		RequestKindRedditUser,
-		RequestKindRedditPost,
-		RequestKindRedditComment:
-		// set X-Ratelimit-Foo headers
-		labels := map[string]string{"polling_client": "reddit_poller"}
-		a.setRedditPromMetrics(l, mh.WithTags(labels), drr.ResponseHeader)
-	case
-		RequestKindRedditSubredditMonitor,
-		RequestKindRedditUserMonitor:
-		// set X-Ratelimit-Foo headers
-		labels := map[string]string{"polling_client": "reddit_monitor"}
-		a.setRedditPromMetrics(l, mh.WithTags(labels), drr.ResponseHeader)
-	case
-		RequestKindTwitchClip,
-		RequestKindTwitchVideo,
-		RequestKindTwitchStream,
-		RequestKindTwitchUserPastDec:
-		// set Ratelimit-Foo headers
-		labels := map[string]string{"polling_client": "twitch"}
-		a.setTwitchPromMetrics(l, mh.WithTags(labels), drr.ResponseHeader)
+	rks, err := GetRequestKindSpec(drr.RequestKind)
+	if err != nil {
+		return nil, err
+	}
+	if rks.SetWorkerMetrics != nil {
+		rks.SetWorkerMetrics(a, l, mh, drr.ResponseHeader)
 	}
 	return &api.DefaultJSONResponse{Message: "ok"}, nil
 }
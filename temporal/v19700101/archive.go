@@ -0,0 +1,271 @@
+package temporal
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/credentials"
+	"go.temporal.io/sdk/activity"
+)
+
+// The response archive. Metrics handlers only keep what they extract, so when
+// a field is added to an extractor there's no history for it. Workers can
+// optionally archive every raw response to a blob store (a directory, or an
+// S3 compatible bucket such as MinIO), and BackfillWF (see backfill.go)
+// replays the archive through the current extractors. Each response is stored
+// as gzipped JSON under a key made from its kind, the day it was fetched, and
+// the digest of its contents, so keys never collide and a blob can always be
+// checked against its key. Archiving is best effort; a response that can't be
+// archived is still handled.
+
+// BlobStore is where archived responses are kept.
+type BlobStore interface {
+	Put(ctx context.Context, key string, b []byte) error
+	Get(ctx context.Context, key string) ([]byte, error)
+	// List returns the keys under the prefix in lexical order.
+	List(ctx context.Context, prefix string) ([]string, error)
+}
+
+// ArchivedResponse is a response as it's stored in the archive.
+type ArchivedResponse struct {
+	RequestKind string `json:"request_kind"`
+	// IDs are the identifier(s) the request was for; there's more than one
+	// if the request was batched. It may be empty if the ID(s) couldn't be
+	// determined from the request.
+	IDs        []string    `json:"ids,omitempty"`
+	Metadata   bool        `json:"metadata,omitempty"`
+	Batched    bool        `json:"batched,omitempty"`
+	FetchedAt  time.Time   `json:"fetched_at"`
+	StatusCode int         `json:"status_code"`
+	Header     http.Header `json:"header"`
+	Body       []byte      `json:"body"`
+}
+
+// NewBlobStore returns the store described by the URL. Supported schemes are
+// file (file:///var/lib/kaggo/archive), s3 (s3://host[:port]/bucket[/prefix]),
+// and s3+http for S3 compatible services that aren't behind TLS. S3
+// credentials are read from AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY.
+func NewBlobStore(rawURL string) (BlobStore, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing blob store url: %w", err)
+	}
+	switch u.Scheme {
+	case "file":
+		return NewFileBlobStore(u.Path)
+	case "s3", "s3+http":
+		bucket, prefix, _ := strings.Cut(strings.Trim(u.Path, "/"), "/")
+		if u.Host == "" || bucket == "" {
+			return nil, fmt.Errorf("s3 blob store url must include a host and bucket: %s", rawURL)
+		}
+		return NewS3BlobStore(u.Host, bucket, prefix, u.Scheme == "s3")
+	}
+	return nil, fmt.Errorf("unsupported blob store scheme: %q", u.Scheme)
+}
+
+type fileBlobStore struct {
+	dir string
+}
+
+// NewFileBlobStore returns a store that keeps blobs in the directory.
+func NewFileBlobStore(dir string) (BlobStore, error) {
+	if dir == "" {
+		return nil, fmt.Errorf("must supply a directory")
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("error creating blob store directory: %w", err)
+	}
+	return &fileBlobStore{dir: dir}, nil
+}
+
+// Put writes to a temporary file first so readers never see a partial blob.
+func (s *fileBlobStore) Put(ctx context.Context, key string, b []byte) error {
+	p := filepath.Join(s.dir, filepath.FromSlash(key))
+	if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+		return err
+	}
+	f, err := os.CreateTemp(filepath.Dir(p), ".tmp-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+	if _, err = f.Write(b); err != nil {
+		f.Close()
+		return err
+	}
+	if err = f.Close(); err != nil {
+		return err
+	}
+	return os.Rename(f.Name(), p)
+}
+
+func (s *fileBlobStore) Get(ctx context.Context, key string) ([]byte, error) {
+	return os.ReadFile(filepath.Join(s.dir, filepath.FromSlash(key)))
+}
+
+func (s *fileBlobStore) List(ctx context.Context, prefix string) ([]string, error) {
+	// walk the directory the prefix is in
+	keys := []string{}
+	root := filepath.Join(s.dir, filepath.FromSlash(path.Dir(prefix+"x")))
+	err := filepath.WalkDir(root, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				return nil
+			}
+			return err
+		}
+		if d.IsDir() || strings.HasPrefix(d.Name(), ".tmp-") {
+			return nil
+		}
+		rel, err := filepath.Rel(s.dir, p)
+		if err != nil {
+			return err
+		}
+		if k := filepath.ToSlash(rel); strings.HasPrefix(k, prefix) {
+			keys = append(keys, k)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	slices.Sort(keys)
+	return keys, nil
+}
+
+type s3BlobStore struct {
+	c      *minio.Client
+	bucket string
+	prefix string
+}
+
+// NewS3BlobStore returns a store that keeps blobs in an S3 compatible bucket,
+// optionally under a prefix.
+func NewS3BlobStore(endpoint, bucket, prefix string, secure bool) (BlobStore, error) {
+	c, err := minio.New(endpoint, &minio.Options{
+		Creds:  credentials.NewEnvAWS(),
+		Secure: secure,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error creating s3 client: %w", err)
+	}
+	if prefix != "" {
+		prefix += "/"
+	}
+	return &s3BlobStore{c: c, bucket: bucket, prefix: prefix}, nil
+}
+
+func (s *s3BlobStore) Put(ctx context.Context, key string, b []byte) error {
+	_, err := s.c.PutObject(ctx, s.bucket, s.prefix+key, bytes.NewReader(b), int64(len(b)), minio.PutObjectOptions{
+		ContentType: "application/gzip",
+	})
+	return err
+}
+
+func (s *s3BlobStore) Get(ctx context.Context, key string) ([]byte, error) {
+	o, err := s.c.GetObject(ctx, s.bucket, s.prefix+key, minio.GetObjectOptions{})
+	if err != nil {
+		return nil, err
+	}
+	defer o.Close()
+	return io.ReadAll(o)
+}
+
+func (s *s3BlobStore) List(ctx context.Context, prefix string) ([]string, error) {
+	keys := []string{}
+	for o := range s.c.ListObjects(ctx, s.bucket, minio.ListObjectsOptions{Prefix: s.prefix + prefix, Recursive: true}) {
+		if o.Err != nil {
+			return nil, o.Err
+		}
+		keys = append(keys, strings.TrimPrefix(o.Key, s.prefix))
+	}
+	return keys, nil
+}
+
+// archiveDayPrefix is the prefix of the keys of the kind's responses fetched
+// on the (UTC) day of t.
+func archiveDayPrefix(rk string, t time.Time) string {
+	return fmt.Sprintf("responses/%s/%s/", rk, t.UTC().Format("2006/01/02"))
+}
+
+// putArchivedResponse stores the response and returns its key.
+func putArchivedResponse(ctx context.Context, s BlobStore, ar ArchivedResponse) (string, error) {
+	b, err := json.Marshal(ar)
+	if err != nil {
+		return "", fmt.Errorf("error serializing response: %w", err)
+	}
+	h := sha256.Sum256(b)
+	key := archiveDayPrefix(ar.RequestKind, ar.FetchedAt) + hex.EncodeToString(h[:]) + ".json.gz"
+
+	buf := &bytes.Buffer{}
+	zw := gzip.NewWriter(buf)
+	if _, err = zw.Write(b); err != nil {
+		return "", err
+	}
+	if err = zw.Close(); err != nil {
+		return "", err
+	}
+	if err = s.Put(ctx, key, buf.Bytes()); err != nil {
+		return "", fmt.Errorf("error storing response: %w", err)
+	}
+	return key, nil
+}
+
+// getArchivedResponse loads the response stored under key.
+func getArchivedResponse(ctx context.Context, s BlobStore, key string) (*ArchivedResponse, error) {
+	b, err := s.Get(ctx, key)
+	if err != nil {
+		return nil, fmt.Errorf("error loading %s: %w", key, err)
+	}
+	zr, err := gzip.NewReader(bytes.NewReader(b))
+	if err != nil {
+		return nil, fmt.Errorf("error decompressing %s: %w", key, err)
+	}
+	defer zr.Close()
+	var ar ArchivedResponse
+	if err = json.NewDecoder(zr).Decode(&ar); err != nil {
+		return nil, fmt.Errorf("error deserializing %s: %w", key, err)
+	}
+	return &ar, nil
+}
+
+// archiveResponse archives the result of a DoRequest (or DoBatchRequest)
+// activity if the worker has an archive. ids are the entities the response
+// covers; metadata is set for the response to a metadata request.
+func (a *ActivityRequester) archiveResponse(ctx context.Context, res *DoRequestActResult, ids []string, metadata, batched bool) {
+	if a.Archive == nil || res.SkipReason != "" {
+		return
+	}
+	key, err := putArchivedResponse(ctx, a.Archive, ArchivedResponse{
+		RequestKind: res.RequestKind,
+		IDs:         ids,
+		Metadata:    metadata,
+		Batched:     batched,
+		FetchedAt:   res.FetchedAt,
+		StatusCode:  res.ResponseStatusCode,
+		Header:      res.ResponseHeader,
+		Body:        res.ResponseBody,
+	})
+	if err != nil {
+		activity.GetLogger(ctx).Warn("error archiving response", "request_kind", res.RequestKind, "error", err.Error())
+		return
+	}
+	activity.GetLogger(ctx).Debug("archived response", "request_kind", res.RequestKind, "key", key)
+}
@@ -0,0 +1,212 @@
+package temporal
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/brojonat/kaggo/server/api"
+	"go.temporal.io/sdk/activity"
+	"go.temporal.io/sdk/log"
+	"go.temporal.io/sdk/temporal"
+	"go.temporal.io/sdk/workflow"
+)
+
+// Backfill. BackfillWF replays the archived responses (see archive.go) for a
+// kind through its current extractor spec and uploads the metrics with the
+// time the response was fetched. The archive is organized by day, so the
+// workflow runs one activity per day in the range; the activity heartbeats
+// its progress so a retry picks up where it left off. Only kinds whose
+// metrics are uploaded to the generic metrics endpoint by an extractor spec
+// can be backfilled; the rest have hand written handlers that may do more
+// than upload metrics (e.g., create schedules).
+
+// BackfillWF backfills the metrics of a kind over [Start, End).
+func BackfillWF(ctx workflow.Context, r BackfillWFRequest) (*BackfillResult, error) {
+	if !r.Start.Before(r.End) {
+		return nil, fmt.Errorf("start must be before end")
+	}
+	activityOptions := workflow.ActivityOptions{
+		StartToCloseTimeout: time.Hour,
+		HeartbeatTimeout:    2 * time.Minute,
+		RetryPolicy: &temporal.RetryPolicy{
+			MaximumAttempts:        5,
+			BackoffCoefficient:     5,
+			NonRetryableErrorTypes: []string{"ErrNoRetry"},
+		},
+	}
+	ctx = workflow.WithActivityOptions(ctx, activityOptions)
+
+	var a *ActivityRequester
+	total := BackfillResult{}
+	start := r.Start.UTC().Truncate(24 * time.Hour)
+	for day := start; day.Before(r.End); day = day.AddDate(0, 0, 1) {
+		var res BackfillResult
+		req := BackfillDayActRequest{BackfillWFRequest: r, Day: day}
+		if err := workflow.ExecuteActivity(ctx, a.BackfillArchivedDay, req).Get(ctx, &res); err != nil {
+			return &total, fmt.Errorf("error backfilling %s: %w", day.Format(time.DateOnly), err)
+		}
+		total.add(res)
+	}
+	workflow.GetLogger(ctx).Info(
+		"backfill complete", "request_kind", r.RequestKind,
+		"responses", total.Responses, "samples", total.Samples, "skipped", total.Skipped, "failed", total.Failed)
+	return &total, nil
+}
+
+func (r *BackfillResult) add(o BackfillResult) {
+	r.Responses += o.Responses
+	r.Samples += o.Samples
+	r.Skipped += o.Skipped
+	r.Failed += o.Failed
+}
+
+// backfillProgress is the activity heartbeat.
+type backfillProgress struct {
+	Next   int            `json:"next"`
+	Result BackfillResult `json:"result"`
+}
+
+// BackfillArchivedDay replays the kind's responses archived on the day.
+func (a *ActivityRequester) BackfillArchivedDay(ctx context.Context, r BackfillDayActRequest) (*BackfillResult, error) {
+	l := activity.GetLogger(ctx)
+	if a.Archive == nil {
+		return nil, ErrNoRetry{Err: fmt.Errorf("this worker has no response archive")}
+	}
+	rks, err := GetRequestKindSpec(r.RequestKind)
+	if err != nil {
+		return nil, ErrNoRetry{Err: err}
+	}
+	s := GetExtractorSpec(r.RequestKind)
+	if s == nil || s.Metrics == nil || s.Metrics.Path != "" {
+		return nil, ErrNoRetry{Err: fmt.Errorf("%s has no extractor spec for the generic metrics endpoint; it can't be backfilled", r.RequestKind)}
+	}
+
+	keys, err := a.Archive.List(ctx, archiveDayPrefix(r.RequestKind, r.Day))
+	if err != nil {
+		return nil, fmt.Errorf("error listing archive: %w", err)
+	}
+	var p backfillProgress
+	if activity.HasHeartbeatDetails(ctx) {
+		if err := activity.GetHeartbeatDetails(ctx, &p); err != nil {
+			l.Warn("error reading backfill progress; starting over", "error", err.Error())
+			p = backfillProgress{}
+		}
+	}
+	for p.Next < len(keys) {
+		if err := a.backfillArchivedResponse(ctx, l, rks, s, r.BackfillWFRequest, keys[p.Next], &p.Result); err != nil {
+			return nil, err
+		}
+		p.Next++
+		activity.RecordHeartbeat(ctx, p)
+	}
+	return &p.Result, nil
+}
+
+// backfillArchivedResponse replays a single archived response and tallies the
+// outcome in res. Only upload errors are returned; anything wrong with the
+// response itself is counted as a failure and logged.
+func (a *ActivityRequester) backfillArchivedResponse(ctx context.Context, l log.Logger, rks RequestKindSpec, s *ExtractorSpec, r BackfillWFRequest, key string, res *BackfillResult) error {
+	ar, err := getArchivedResponse(ctx, a.Archive, key)
+	if err != nil {
+		l.Error("error reading archived response", "key", key, "error", err.Error())
+		res.Failed++
+		return nil
+	}
+	if !backfillWants(r, ar) {
+		res.Skipped++
+		return nil
+	}
+	bodies := [][]byte{ar.Body}
+	if ar.Batched && rks.Batch != nil {
+		items, err := rks.Batch.Split(ar.Body)
+		if err != nil {
+			l.Error("error splitting archived response", "key", key, "error", err.Error())
+			res.Failed++
+			return nil
+		}
+		bodies = bodies[:0]
+		for _, it := range items {
+			bodies = append(bodies, it.Body)
+		}
+	}
+	for _, b := range bodies {
+		n, err := a.backfillBody(ctx, l, s, r, key, ar.FetchedAt, b)
+		if err != nil {
+			var nr ErrNoRetry
+			if !errors.As(err, &nr) {
+				return err
+			}
+			l.Error("error extracting archived response", "key", key, "error", err.Error())
+			res.Failed++
+			continue
+		}
+		res.Samples += n
+	}
+	res.Responses++
+	return nil
+}
+
+// backfillWants reports whether the archived response should be replayed.
+func backfillWants(r BackfillWFRequest, ar *ArchivedResponse) bool {
+	if ar.Metadata || ar.StatusCode != http.StatusOK {
+		return false
+	}
+	if ar.FetchedAt.Before(r.Start) || !ar.FetchedAt.Before(r.End) {
+		return false
+	}
+	if len(r.IDs) > 0 && len(ar.IDs) > 0 {
+		return slices.ContainsFunc(ar.IDs, func(id string) bool { return containsFold(r.IDs, id) })
+	}
+	return true
+}
+
+// backfillBody extracts the metrics from the body and uploads the requested
+// ones, returning the number of samples uploaded. The samples carry the time the
+// response was fetched, so samples already recorded at that time (live or by an
+// earlier backfill) are skipped and backfilling a range twice is harmless. Extraction
+// errors are ErrNoRetry; upload errors aren't.
+func (a *ActivityRequester) backfillBody(ctx context.Context, l log.Logger, s *ExtractorSpec, r BackfillWFRequest, key string, ts time.Time, b []byte) (int, error) {
+	payload, err := s.ExtractMetrics(b)
+	if err != nil {
+		return 0, ErrNoRetry{Err: err}
+	}
+	if payload == nil {
+		return 0, nil
+	}
+	var p api.MetricSamplesPayload
+	if err = json.Unmarshal(payload, &p); err != nil {
+		return 0, ErrNoRetry{Err: fmt.Errorf("error deserializing metrics: %w", err)}
+	}
+	if len(r.IDs) > 0 && !containsFold(r.IDs, p.ID) {
+		return 0, nil
+	}
+	if len(r.Metrics) > 0 {
+		for m := range p.Metrics {
+			if !slices.Contains(r.Metrics, m) {
+				delete(p.Metrics, m)
+			}
+		}
+	}
+	if len(p.Metrics) == 0 {
+		return 0, nil
+	}
+	p.Ts = &ts
+	p.IdempotencyKey = key
+	if payload, err = json.Marshal(p); err != nil {
+		return 0, ErrNoRetry{Err: fmt.Errorf("error serializing metrics: %w", err)}
+	}
+	if _, err = a.uploadMetrics(ctx, l, MetricsUploadPath, payload); err != nil {
+		return 0, err
+	}
+	return len(p.Metrics), nil
+}
+
+func containsFold(ss []string, s string) bool {
+	return slices.ContainsFunc(ss, func(v string) bool { return strings.EqualFold(v, s) })
+}
@@ -0,0 +1,500 @@
+package temporal
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/brojonat/kaggo/server/api"
+	"go.temporal.io/sdk/activity"
+	"go.temporal.io/sdk/client"
+	"go.temporal.io/sdk/temporal"
+	"go.temporal.io/sdk/workflow"
+)
+
+// Batched polling. Many APIs will happily return a bunch of IDs per request
+// (e.g., YouTube takes 50 video IDs), so issuing one request per ID wastes
+// quota. When batching is enabled for a kind, DoPollingRequestWF doesn't make
+// the request itself; instead, it hands its request off to a long running
+// per-kind DoBatchPollingRequestWF. That workflow gathers the IDs that come
+// due over a short window, issues a single combined request, splits the
+// response back into single item responses, and passes each of those to the
+// kind's regular metrics handler. The schedules themselves are untouched, so
+// cadence, pausing and expiry work exactly the same as unbatched polling.
+
+const (
+	// BatchPollSignal carries a DoRequestActRequest to the batch workflow.
+	BatchPollSignal = "batch-poll"
+	// batchWindow is how long the batch workflow waits for a batch to fill
+	// up after the first request arrives.
+	batchWindow = 30 * time.Second
+	// batchesPerRun bounds the workflow history; the batch workflow continues
+	// as new after this many batches.
+	batchesPerRun = 100
+)
+
+// BatchSpec describes how polling requests for a kind are combined. The
+// request prototypes must only differ by the ID(s) in Param.
+type BatchSpec struct {
+	// Size is the maximum number of IDs per request.
+	Size int
+	// Param is the query parameter that holds the ID(s).
+	Param string
+	// Repeat the parameter for each ID (e.g., Twitch's id=a&id=b) rather than
+	// joining the IDs with commas (e.g., YouTube's id=a,b).
+	Repeat bool
+	// Split splits a combined response body into bodies shaped like the
+	// response to an unbatched request.
+	Split func(b []byte) ([]BatchItem, error)
+}
+
+// BatchItem is one item of a combined response; ID is the item's ID as it
+// appears in the request's Param.
+type BatchItem struct {
+	ID   string
+	Body []byte
+}
+
+// the kinds that are polled in batches; see SetBatchPollingKinds
+var batchPollingKinds = map[string]bool{}
+
+// SetBatchPollingKinds enables batched polling for the supplied kinds. This is
+// not safe to call concurrently with workflow execution; call it on startup
+// before the worker starts.
+func SetBatchPollingKinds(rks []string) error {
+	kinds := map[string]bool{}
+	for _, rk := range rks {
+		rk = strings.TrimSpace(rk)
+		if rk == "" {
+			continue
+		}
+		s, err := GetRequestKindSpec(rk)
+		if err != nil {
+			return err
+		}
+		if s.Batch == nil {
+			return fmt.Errorf("%s does not support batched polling", rk)
+		}
+		kinds[rk] = true
+	}
+	batchPollingKinds = kinds
+	return nil
+}
+
+// BatchPollingEnabled reports whether the kind is polled in batches.
+func BatchPollingEnabled(rk string) bool {
+	return batchPollingKinds[rk]
+}
+
+func batchWorkflowID(rk string) string {
+	return fmt.Sprintf("batch-poll %s", rk)
+}
+
+// splitListBody returns a BatchSpec.Split that splits the list found under
+// keys into one body per element, each nested under the same keys (e.g.,
+// {"items": [a, b]} becomes {"items": [a]} and {"items": [b]}). Anything else in
+// the body is dropped. Each element's ID is read from the dot separated path id
+// (e.g., "data.name").
+func splitListBody(id string, keys ...string) func([]byte) ([]BatchItem, error) {
+	return func(b []byte) ([]BatchItem, error) {
+		var data interface{}
+		if err := json.Unmarshal(b, &data); err != nil {
+			return nil, fmt.Errorf("error deserializing response: %w", err)
+		}
+		for _, k := range keys {
+			m, ok := data.(map[string]interface{})
+			if !ok {
+				return nil, fmt.Errorf("error splitting response: %s is not an object", k)
+			}
+			data = m[k]
+		}
+		items, ok := data.([]interface{})
+		if !ok {
+			return nil, fmt.Errorf("error splitting response: %s is not a list", strings.Join(keys, "."))
+		}
+		res := make([]BatchItem, 0, len(items))
+		for _, item := range items {
+			var iid interface{} = item
+			for _, k := range strings.Split(id, ".") {
+				m, _ := iid.(map[string]interface{})
+				iid = m[k]
+			}
+			sid, ok := iid.(string)
+			if !ok {
+				return nil, fmt.Errorf("error splitting response: item has no %s", id)
+			}
+			var v interface{} = []interface{}{item}
+			for i := len(keys) - 1; i >= 0; i-- {
+				v = map[string]interface{}{keys[i]: v}
+			}
+			ib, err := json.Marshal(v)
+			if err != nil {
+				return nil, fmt.Errorf("error serializing item: %w", err)
+			}
+			res = append(res, BatchItem{ID: sid, Body: ib})
+		}
+		return res, nil
+	}
+}
+
+// requestIDs returns the IDs a request asks for.
+func requestIDs(bs *BatchSpec, r *http.Request) []string {
+	ids := []string{}
+	for _, v := range r.URL.Query()[bs.Param] {
+		for _, id := range strings.Split(v, ",") {
+			if id != "" {
+				ids = append(ids, id)
+			}
+		}
+	}
+	return ids
+}
+
+// mergeBatchRequests combines the request prototypes into a single request
+// for all of their IDs. The first prototype is used as the template.
+func mergeBatchRequests(bs *BatchSpec, serials [][]byte) (*http.Request, error) {
+	if len(serials) == 0 {
+		return nil, fmt.Errorf("no requests to merge")
+	}
+	var r *http.Request
+	ids := []string{}
+	for _, serial := range serials {
+		sr, err := deserializeRequest(serial)
+		if err != nil {
+			return nil, err
+		}
+		if r == nil {
+			r = sr
+		}
+		for _, id := range requestIDs(bs, sr) {
+			if !slices.Contains(ids, id) {
+				ids = append(ids, id)
+			}
+		}
+	}
+	if len(ids) > bs.Size {
+		return nil, fmt.Errorf("batch of %d ids exceeds the batch size %d", len(ids), bs.Size)
+	}
+	qs := r.URL.Query()
+	qs.Del(bs.Param)
+	if bs.Repeat {
+		for _, id := range ids {
+			qs.Add(bs.Param, id)
+		}
+	} else {
+		qs.Set(bs.Param, strings.Join(ids, ","))
+	}
+	r.URL.RawQuery = qs.Encode()
+	return r, nil
+}
+
+// DoBatchPollingRequestWF runs (more or less) forever, polling the requests
+// signaled to it in batches. There's one of these per kind; it's started by
+// the first EnqueueBatchPoll.
+func DoBatchPollingRequestWF(ctx workflow.Context, r DoBatchPollingRequestWFRequest) error {
+	rks, err := GetRequestKindSpec(r.RequestKind)
+	if err != nil {
+		return err
+	}
+	if rks.Batch == nil {
+		return fmt.Errorf("%s does not support batched polling", r.RequestKind)
+	}
+	l := workflow.GetLogger(ctx)
+	ch := workflow.GetSignalChannel(ctx, BatchPollSignal)
+
+	// runs started before failure tracking existed don't count failures
+	track := workflow.GetVersion(ctx, "batch-failure-tracking", workflow.DefaultVersion, 1) == 1
+	schedules, failures := r.Schedules, r.Failures
+	if schedules == nil {
+		schedules = map[string]string{}
+	}
+	if failures == nil {
+		failures = map[string]int{}
+	}
+
+	// the same schedule may fire more than once before its batch is polled,
+	// so dedupe the pending requests
+	pending := r.Pending
+	receive := func(c workflow.ReceiveChannel, more bool) {
+		var req BatchPollRequest
+		c.Receive(ctx, &req)
+		if !slices.ContainsFunc(pending, func(s []byte) bool { return string(s) == string(req.Serial) }) {
+			pending = append(pending, req.Serial)
+		}
+		if req.ScheduleID == "" {
+			return
+		}
+		sr, err := deserializeRequest(req.Serial)
+		if err != nil {
+			l.Error("error reading batch request", "request_kind", r.RequestKind, "error", err.Error())
+			return
+		}
+		for _, id := range requestIDs(rks.Batch, sr) {
+			schedules[id] = req.ScheduleID
+		}
+	}
+
+	for range batchesPerRun {
+		// wait for something to do (or to be cancelled)
+		if len(pending) == 0 {
+			sel := workflow.NewSelector(ctx)
+			sel.AddReceive(ch, receive)
+			sel.AddReceive(ctx.Done(), func(c workflow.ReceiveChannel, more bool) {})
+			sel.Select(ctx)
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+		}
+
+		// gather requests until the batch is full or the window closes
+		tctx, cancel := workflow.WithCancel(ctx)
+		timer := workflow.NewTimer(tctx, batchWindow)
+		for closed := false; !closed && len(pending) < rks.Batch.Size; {
+			sel := workflow.NewSelector(ctx)
+			sel.AddReceive(ch, receive)
+			sel.AddFuture(timer, func(f workflow.Future) {
+				closed = true
+			})
+			sel.Select(ctx)
+		}
+		cancel()
+
+		n := min(len(pending), rks.Batch.Size)
+		batch := pending[:n]
+		pending = slices.Clone(pending[n:])
+		// A failed batch is dropped, just like a failed unbatched poll; there
+		// will be another one soon enough.
+		res, err := doBatchPoll(ctx, r.RequestKind, batch)
+		if err != nil {
+			l.Error("error polling batch", "request_kind", r.RequestKind, "size", len(batch), "error", err.Error())
+		} else if track && res != nil {
+			countBatchFailures(ctx, r.RequestKind, *res, schedules, failures)
+		}
+		// the schedules map their IDs again the next time they fire
+		for _, serial := range batch {
+			if sr, err := deserializeRequest(serial); err == nil {
+				for _, id := range requestIDs(rks.Batch, sr) {
+					delete(schedules, id)
+				}
+			}
+		}
+	}
+
+	// don't lose any requests that were signaled in the meantime
+	for ch.Len() > 0 {
+		receive(ch, true)
+	}
+	return workflow.NewContinueAsNewError(ctx, DoBatchPollingRequestWF, DoBatchPollingRequestWFRequest{
+		RequestKind: r.RequestKind,
+		Pending:     pending,
+		Schedules:   schedules,
+		Failures:    failures,
+	})
+}
+
+// doBatchPoll mirrors DoPollingRequestWF for a batch of requests. The result
+// is nil if the batch was skipped.
+func doBatchPoll(ctx workflow.Context, rk string, serials [][]byte) (*UploadBatchResult, error) {
+	var a *ActivityRequester
+
+	activityOptions := workflow.ActivityOptions{
+		StartToCloseTimeout: 30 * time.Second,
+		RetryPolicy:         &temporal.RetryPolicy{MaximumAttempts: 1},
+	}
+	actx := workflow.WithActivityOptions(ctx, activityOptions)
+	req := DoBatchRequestActRequest{RequestKind: rk, Serials: serials}
+	var res DoRequestActResult
+	if err := workflow.ExecuteActivity(actx, a.DoBatchRequest, req).Get(actx, &res); err != nil {
+		return nil, err
+	}
+	if res.SkipReason != "" {
+		workflow.GetLogger(ctx).Warn("batch skipped", "request_kind", rk, "size", len(serials), "reason", res.SkipReason)
+		return nil, nil
+	}
+	if res.ResponseStatusCode != http.StatusOK {
+		return nil, fmt.Errorf("non-200 response: %d (%s): %s",
+			res.ResponseStatusCode, http.StatusText(res.ResponseStatusCode), res.ResponseBody)
+	}
+
+	activityOptions = workflow.ActivityOptions{
+		StartToCloseTimeout: 2 * time.Minute,
+		RetryPolicy:         &temporal.RetryPolicy{MaximumAttempts: 5, BackoffCoefficient: 5},
+	}
+	actx = workflow.WithActivityOptions(ctx, activityOptions)
+	var ubr UploadBatchResult
+	ureq := UploadBatchActRequest{DoRequestActResult: res, Serials: serials}
+	if err := workflow.ExecuteActivity(actx, a.UploadBatchResponseData, ureq).Get(actx, &ubr); err != nil {
+		return nil, err
+	}
+	if err := workflow.ExecuteActivity(actx, a.SetWorkerMetrics, res).Get(actx, nil); err != nil {
+		return nil, err
+	}
+	return &ubr, nil
+}
+
+// countBatchFailures is the batched counterpart of pollingFailedPermanently.
+// Batched runs of a schedule don't see their own outcome, so the batch
+// workflow keeps the count of permanent failures in a row for each schedule
+// instead, and pauses the ones that reach pauseAfterPermanentFailures. Only
+// gone items (see UploadBatchResponseData) count; a failed batch leaves the
+// counts alone, like a transient failure.
+func countBatchFailures(ctx workflow.Context, rk string, res UploadBatchResult, schedules map[string]string, failures map[string]int) {
+	l := workflow.GetLogger(ctx)
+	for _, id := range res.Uploaded {
+		delete(failures, schedules[id])
+	}
+	for _, id := range res.Gone {
+		sid := schedules[id]
+		if sid == "" {
+			continue
+		}
+		failures[sid]++
+		l.Warn("polling request failed permanently", "request_kind", rk, "schedule_id", sid,
+			"consecutive_failures", failures[sid], "id", id)
+		if failures[sid] < pauseAfterPermanentFailures {
+			continue
+		}
+		var a *ActivityRequester
+		activityOptions := workflow.ActivityOptions{
+			StartToCloseTimeout: 30 * time.Second,
+			RetryPolicy:         &temporal.RetryPolicy{MaximumAttempts: 5, BackoffCoefficient: 5},
+		}
+		actx := workflow.WithActivityOptions(ctx, activityOptions)
+		p := api.SchedulePauseFailingPayload{
+			ScheduleID: sid,
+			Failures:   failures[sid],
+			Reason:     string(FailureDeleted),
+		}
+		if err := workflow.ExecuteActivity(actx, a.PauseFailingSchedule, p).Get(actx, nil); err != nil {
+			l.Error("error pausing failing schedule", "schedule_id", sid, "error", err.Error())
+			continue
+		}
+		delete(failures, sid)
+	}
+}
+
+// ActivityBatchPoller needs a Temporal client to signal the batch workflows.
+type ActivityBatchPoller struct {
+	Client client.Client
+}
+
+// EnqueueBatchPoll signals the request to the kind's batch workflow, starting
+// the workflow if it isn't running.
+func (a *ActivityBatchPoller) EnqueueBatchPoll(ctx context.Context, r BatchPollRequest) error {
+	_, err := a.Client.SignalWithStartWorkflow(
+		ctx,
+		batchWorkflowID(r.RequestKind),
+		BatchPollSignal,
+		r,
+		client.StartWorkflowOptions{
+			ID:        batchWorkflowID(r.RequestKind),
+			TaskQueue: os.Getenv("TEMPORAL_TASK_QUEUE"),
+		},
+		DoBatchPollingRequestWF,
+		DoBatchPollingRequestWFRequest{RequestKind: r.RequestKind},
+	)
+	return err
+}
+
+// DoBatchRequest does a single request for all of the supplied requests.
+func (a *ActivityRequester) DoBatchRequest(ctx context.Context, r DoBatchRequestActRequest) (*DoRequestActResult, error) {
+	rks, err := GetRequestKindSpec(r.RequestKind)
+	if err != nil {
+		return nil, err
+	}
+	if rks.Batch == nil {
+		return nil, ErrNoRetry{Err: fmt.Errorf("%s does not support batched polling", r.RequestKind)}
+	}
+	req, err := mergeBatchRequests(rks.Batch, r.Serials)
+	if err != nil {
+		return nil, ErrNoRetry{Err: err}
+	}
+	req = req.WithContext(ctx)
+	ids := []string{}
+	for _, v := range req.URL.Query()[rks.Batch.Param] {
+		ids = append(ids, strings.Split(v, ",")...)
+	}
+	if err = rks.Prepare(a, req); err != nil {
+		return nil, err
+	}
+	res, err := doRequest(ctx, r.RequestKind, req)
+	if err != nil {
+		return nil, err
+	}
+	a.archiveResponse(ctx, res, ids, false, true)
+	return res, nil
+}
+
+// UploadBatchResponseData splits the response to a batched request and uploads
+// each item with the kind's metrics handler. Items that are gone, i.e., IDs
+// missing from the response (e.g., deleted videos) or items the handler
+// reports as ErrContentGone, are reported in the result so that the batch
+// workflow can count them against their schedules. Items that can't be
+// extracted (ErrNoRetry) are logged and skipped. If any other item fails, the
+// activity fails and is retried; the items that were already uploaded carry
+// the same idempotency keys on the retry, so the server drops them.
+func (a *ActivityRequester) UploadBatchResponseData(ctx context.Context, r UploadBatchActRequest) (*UploadBatchResult, error) {
+	l := activity.GetLogger(ctx)
+	drr := r.DoRequestActResult
+	rks, err := GetRequestKindSpec(drr.RequestKind)
+	if err != nil {
+		return nil, err
+	}
+	if rks.Batch == nil {
+		return nil, ErrNoRetry{Err: fmt.Errorf("%s does not support batched polling", drr.RequestKind)}
+	}
+	h := rks.metricsHandler()
+	if h == nil {
+		return nil, fmt.Errorf("no metrics handler for RequestKind: %s", drr.RequestKind)
+	}
+	items, err := rks.Batch.Split(drr.ResponseBody)
+	if err != nil {
+		return nil, ErrNoRetry{Err: err}
+	}
+	ctx = withObservedAt(ctx, drr.FetchedAt)
+	res := &UploadBatchResult{Uploaded: []string{}, Gone: []string{}}
+	var failed int
+	var lastErr error
+	seen := map[string]bool{}
+	for _, it := range items {
+		seen[it.ID] = true
+		if _, err := h(a, ctx, l, drr.ResponseStatusCode, it.Body); err != nil {
+			var gone ErrContentGone
+			if errors.As(err, &gone) {
+				res.Gone = append(res.Gone, it.ID)
+				continue
+			}
+			l.Error("error uploading batch item", "request_kind", drr.RequestKind, "id", it.ID, "error", err.Error())
+			var nr ErrNoRetry
+			if !errors.As(err, &nr) {
+				failed++
+				lastErr = err
+			}
+			continue
+		}
+		res.Uploaded = append(res.Uploaded, it.ID)
+	}
+	for _, serial := range r.Serials {
+		sr, err := deserializeRequest(serial)
+		if err != nil {
+			return nil, ErrNoRetry{Err: err}
+		}
+		for _, id := range requestIDs(rks.Batch, sr) {
+			if !seen[id] {
+				seen[id] = true
+				res.Gone = append(res.Gone, id)
+			}
+		}
+	}
+	if failed > 0 {
+		return nil, fmt.Errorf("error uploading batch; %d/%d items failed: %w", failed, len(items), lastErr)
+	}
+	res.Message = fmt.Sprintf("uploaded %d/%d, %d gone", len(res.Uploaded), len(items), len(res.Gone))
+	return res, nil
+}
@@ -0,0 +1,146 @@
+package temporal
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/brojonat/kaggo/server/api"
+	"go.temporal.io/sdk/temporal"
+	"go.temporal.io/sdk/workflow"
+)
+
+// Adaptive cadence. The default schedules poll at a fixed rate, but a new
+// Reddit post changes a lot in its first hours and barely at all by its third
+// week. Kinds with a CadenceSpec have the interval of their schedules adapted
+// to how quickly a metric is changing: roughly often enough that each poll
+// sees the metric move by cadenceTargetChange, within the kind's bounds. Every
+// cadenceReviewEvery, a polling run asks the server to review its schedule;
+// the server computes the metric's recent rate of change, and if that calls
+// for a different interval, updates the schedule spec and sets the reason in
+// the schedule's note. Intervals are snapped to cadenceSteps so small wobbles
+// in the rate don't update the schedule. Paused schedules, and schedules
+// created with a fixed cadence, are left alone.
+
+const (
+	// polls should see the metric change by about this fraction
+	cadenceTargetChange = 0.01
+	cadenceReviewEvery  = time.Hour
+	// the rate of change is computed over at least this much history
+	cadenceMinWindow = 6 * time.Hour
+	// schedule memo key that opts a schedule out of adaptation
+	MemoFixedCadence = "fixed_cadence"
+)
+
+// cadenceSteps are the intervals a schedule is adapted to.
+var cadenceSteps = []time.Duration{
+	time.Minute, 2 * time.Minute, 5 * time.Minute, 10 * time.Minute, 15 * time.Minute, 30 * time.Minute,
+	time.Hour, 2 * time.Hour, 3 * time.Hour, 6 * time.Hour, 12 * time.Hour, 24 * time.Hour,
+}
+
+// CadenceSpec describes how a kind's polling interval adapts.
+type CadenceSpec struct {
+	// Metric is the (short) name of the metric whose rate of change sets the
+	// interval.
+	Metric string
+	Min    time.Duration
+	Max    time.Duration
+}
+
+// CadenceSample is a sample of the cadence metric.
+type CadenceSample struct {
+	Ts    time.Time
+	Value float64
+}
+
+// Window returns how far back to look for samples given the current interval.
+func (c *CadenceSpec) Window(current time.Duration) time.Duration {
+	return max(cadenceMinWindow, 3*current)
+}
+
+// Interval returns the interval the samples (oldest first) call for and the
+// reason. It returns false if there aren't enough samples to tell.
+func (c *CadenceSpec) Interval(samples []CadenceSample) (time.Duration, string, bool) {
+	rate, elapsed, ok := relativeRate(samples)
+	if !ok {
+		return 0, "", false
+	}
+	want := c.Max
+	if rate > 0 {
+		want = time.Duration(min(cadenceTargetChange/rate*float64(time.Hour), float64(c.Max)))
+	}
+	d := c.snap(want)
+	reason := fmt.Sprintf(
+		"adaptive cadence: %s changed %.2f%%/h over the last %s; polling every %s (min %s, max %s)",
+		c.Metric, 100*rate, elapsed.Round(time.Minute), d, c.Min, c.Max)
+	return d, reason, true
+}
+
+// relativeRate returns the relative change per hour of the samples (oldest
+// first) and the time they span. It returns false if there aren't enough
+// samples to tell.
+func relativeRate(samples []CadenceSample) (float64, time.Duration, bool) {
+	if len(samples) < 2 {
+		return 0, 0, false
+	}
+	first, last := samples[0], samples[len(samples)-1]
+	elapsed := last.Ts.Sub(first.Ts)
+	if elapsed <= 0 {
+		return 0, 0, false
+	}
+	// small values are treated as 1 so that a metric going from 0 to 2
+	// doesn't look infinitely fast
+	rate := math.Abs(last.Value-first.Value) / math.Max(math.Abs(last.Value), 1) / elapsed.Hours()
+	return rate, elapsed, true
+}
+
+// snap returns the longest step no longer than d, within the bounds.
+func (c *CadenceSpec) snap(d time.Duration) time.Duration {
+	s := c.Min
+	for _, step := range cadenceSteps {
+		if step <= d && step > s {
+			s = step
+		}
+	}
+	return min(s, c.Max)
+}
+
+// reviewCadence has the server review the schedule's cadence if the kind's
+// cadence is adaptive and it hasn't been reviewed for a while. Errors are only
+// logged; the cadence will be reviewed by a later run.
+func reviewCadence(ctx workflow.Context, r DoPollingRequestWFRequest, res *DoPollingRequestWFResult) {
+	rks, err := GetRequestKindSpec(r.RequestKind)
+	if err != nil || rks.Cadence == nil {
+		return
+	}
+	sid := scheduledByID(ctx)
+	now := workflow.Now(ctx)
+	if sid == "" || now.Sub(res.CadenceReviewedAt) < cadenceReviewEvery {
+		return
+	}
+	res.CadenceReviewedAt = now
+
+	var a *ActivityRequester
+	activityOptions := workflow.ActivityOptions{
+		StartToCloseTimeout: 30 * time.Second,
+		RetryPolicy:         &temporal.RetryPolicy{MaximumAttempts: 3},
+	}
+	ctx = workflow.WithActivityOptions(ctx, activityOptions)
+	var resp api.DefaultJSONResponse
+	p := api.ScheduleCadencePayload{ScheduleID: sid}
+	if err := workflow.ExecuteActivity(ctx, a.ReviewScheduleCadence, p).Get(ctx, &resp); err != nil {
+		workflow.GetLogger(ctx).Warn("error reviewing schedule cadence", "schedule_id", sid, "error", err.Error())
+		return
+	}
+	workflow.GetLogger(ctx).Info("reviewed schedule cadence", "schedule_id", sid, "result", resp.Message)
+}
+
+// ReviewScheduleCadence asks the kaggo server to adapt the schedule's cadence.
+func (a *ActivityRequester) ReviewScheduleCadence(ctx context.Context, p api.ScheduleCadencePayload) (*api.DefaultJSONResponse, error) {
+	var res api.DefaultJSONResponse
+	if err := postToKaggo(ctx, "/schedule/cadence", p, &res); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
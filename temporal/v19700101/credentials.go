@@ -0,0 +1,246 @@
+package temporal
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/brojonat/kaggo/server/db/dbgen"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"golang.org/x/sync/singleflight"
+)
+
+// OAuth credentials. Several providers need a short lived access token on
+// every request. These are handed out by a CredentialProvider, which caches
+// them, refreshes them ahead of their expiry, and makes sure there's only one
+// refresh per credential in flight at a time. The in-memory provider does
+// this per worker; the Postgres provider also shares tokens between worker
+// replicas (and across restarts). Adding an OAuth provider is a matter of
+// declaring an OAuthSource (see reddit.go and twitch.go).
+
+const (
+	// tokens are never handed out with less than this left on them
+	oauthMinValidity = time.Minute
+	// tokens with less than this left are refreshed in the background
+	oauthRefreshAhead = 10 * time.Minute
+	// upper bound on a refresh, which may be shared by several callers
+	oauthRefreshTimeout = 30 * time.Second
+)
+
+// OAuthToken is an access token and its expiry.
+type OAuthToken struct {
+	AccessToken string
+	Expiry      time.Time
+}
+
+// OAuthSource describes how to get an access token for a credential.
+type OAuthSource struct {
+	// Key identifies the credential; tokens are cached under it, so changing
+	// the credential doesn't serve a stale token.
+	Key func() string
+	// NewTokenRequest builds the request for a new token. The response must
+	// be the usual JSON body with an access_token and expires_in.
+	NewTokenRequest func() (*http.Request, error)
+}
+
+// CredentialProvider hands out valid access tokens.
+type CredentialProvider interface {
+	Token(ctx context.Context, src *OAuthSource) (string, error)
+}
+
+// NewMemoryCredentialProvider returns a provider that caches tokens in memory.
+func NewMemoryCredentialProvider() CredentialProvider {
+	return &credentialCache{tokens: map[string]OAuthToken{}}
+}
+
+// NewPostgresCredentialProvider returns a provider that shares tokens through
+// the oauth_tokens table. Tokens are still cached in memory; the table is
+// only consulted when the cached token is due for a refresh.
+func NewPostgresCredentialProvider(p *pgxpool.Pool) CredentialProvider {
+	return &credentialCache{
+		tokens: map[string]OAuthToken{},
+		store:  &pgTokenStore{p: p, q: dbgen.New(p)},
+	}
+}
+
+// used by ActivityRequesters without a CredentialProvider
+var defaultCredentialProvider = NewMemoryCredentialProvider()
+
+// tokenStore is where a credentialCache shares its tokens.
+type tokenStore interface {
+	// load returns the stored token; the zero token if there isn't one.
+	load(ctx context.Context, key string) (OAuthToken, error)
+	// refresh stores a new token from fetch, unless someone else stored a
+	// fresh one first, in which case that's returned.
+	refresh(ctx context.Context, key string, fetch func(context.Context) (OAuthToken, error)) (OAuthToken, error)
+}
+
+type credentialCache struct {
+	mu     sync.Mutex
+	tokens map[string]OAuthToken
+	group  singleflight.Group
+	// store is nil for the in-memory provider
+	store tokenStore
+}
+
+func (c *credentialCache) Token(ctx context.Context, src *OAuthSource) (string, error) {
+	key := src.Key()
+	tok := c.current(ctx, key)
+	if time.Until(tok.Expiry) > oauthRefreshAhead {
+		return tok.AccessToken, nil
+	}
+	if time.Until(tok.Expiry) > oauthMinValidity {
+		// still good for now; refresh in the background so nobody waits
+		go c.refresh(context.Background(), src, key)
+		return tok.AccessToken, nil
+	}
+	tok, err := c.refresh(ctx, src, key)
+	if err != nil {
+		return "", err
+	}
+	return tok.AccessToken, nil
+}
+
+// current returns the best token we know of. If the cached token is due for a
+// refresh, another worker may already have refreshed it, so check the store.
+func (c *credentialCache) current(ctx context.Context, key string) OAuthToken {
+	c.mu.Lock()
+	tok := c.tokens[key]
+	c.mu.Unlock()
+	if c.store == nil || time.Until(tok.Expiry) > oauthRefreshAhead {
+		return tok
+	}
+	stored, err := c.store.load(ctx, key)
+	if err != nil || !stored.Expiry.After(tok.Expiry) {
+		return tok
+	}
+	c.set(key, stored)
+	return stored
+}
+
+func (c *credentialCache) set(key string, tok OAuthToken) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.tokens[key] = tok
+}
+
+// refresh gets a new token. Concurrent refreshes of the same key share the
+// first one; it isn't tied to any one caller's context, since the others are
+// waiting on it too.
+func (c *credentialCache) refresh(ctx context.Context, src *OAuthSource, key string) (OAuthToken, error) {
+	v, err, _ := c.group.Do(key, func() (interface{}, error) {
+		ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), oauthRefreshTimeout)
+		defer cancel()
+		fetch := func(ctx context.Context) (OAuthToken, error) {
+			return fetchOAuthToken(ctx, src)
+		}
+		var tok OAuthToken
+		var err error
+		if c.store == nil {
+			tok, err = fetch(ctx)
+		} else {
+			tok, err = c.store.refresh(ctx, key, fetch)
+		}
+		if err != nil {
+			return OAuthToken{}, err
+		}
+		c.set(key, tok)
+		return tok, nil
+	})
+	if err != nil {
+		return OAuthToken{}, err
+	}
+	return v.(OAuthToken), nil
+}
+
+// fetchOAuthToken requests a new token from the source.
+func fetchOAuthToken(ctx context.Context, src *OAuthSource) (OAuthToken, error) {
+	r, err := src.NewTokenRequest()
+	if err != nil {
+		return OAuthToken{}, err
+	}
+	resp, err := http.DefaultClient.Do(r.WithContext(ctx))
+	if err != nil {
+		return OAuthToken{}, err
+	}
+	defer resp.Body.Close()
+	var body struct {
+		AccessToken string `json:"access_token"`
+		ExpiresIn   int    `json:"expires_in"`
+		TokenType   string `json:"token_type"`
+	}
+	b, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return OAuthToken{}, err
+	}
+	if resp.StatusCode != 200 {
+		return OAuthToken{}, fmt.Errorf("bad response %d code for getting %s auth token: %s", resp.StatusCode, r.URL.Host, string(b))
+	}
+	err = json.Unmarshal(b, &body)
+	if err != nil {
+		return OAuthToken{}, err
+	}
+	dur := time.Duration(body.ExpiresIn * int(time.Second))
+	return OAuthToken{AccessToken: body.AccessToken, Expiry: time.Now().Add(dur)}, nil
+}
+
+type pgTokenStore struct {
+	p *pgxpool.Pool
+	q *dbgen.Queries
+}
+
+func (s *pgTokenStore) load(ctx context.Context, key string) (OAuthToken, error) {
+	row, err := s.q.GetOAuthToken(ctx, key)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return OAuthToken{}, nil
+		}
+		return OAuthToken{}, err
+	}
+	return OAuthToken{AccessToken: row.AccessToken, Expiry: row.TsExpires.Time}, nil
+}
+
+// refresh holds an advisory lock on the key while fetching, so only one
+// worker refreshes a token at a time; the rest pick up its token.
+func (s *pgTokenStore) refresh(ctx context.Context, key string, fetch func(context.Context) (OAuthToken, error)) (OAuthToken, error) {
+	tx, err := s.p.Begin(ctx)
+	if err != nil {
+		return OAuthToken{}, err
+	}
+	defer tx.Rollback(ctx)
+	qtx := s.q.WithTx(tx)
+	if err = qtx.LockOAuthToken(ctx, key); err != nil {
+		return OAuthToken{}, fmt.Errorf("error locking token: %w", err)
+	}
+	row, err := qtx.GetOAuthToken(ctx, key)
+	if err == nil && time.Until(row.TsExpires.Time) > oauthRefreshAhead {
+		return OAuthToken{AccessToken: row.AccessToken, Expiry: row.TsExpires.Time}, nil
+	}
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		return OAuthToken{}, err
+	}
+
+	tok, err := fetch(ctx)
+	if err != nil {
+		return OAuthToken{}, err
+	}
+	err = qtx.UpsertOAuthToken(ctx, dbgen.UpsertOAuthTokenParams{
+		TokenKey:    key,
+		AccessToken: tok.AccessToken,
+		TsExpires:   pgtype.Timestamptz{Time: tok.Expiry, Valid: true},
+	})
+	if err != nil {
+		return OAuthToken{}, fmt.Errorf("error storing token: %w", err)
+	}
+	if err = tx.Commit(ctx); err != nil {
+		return OAuthToken{}, err
+	}
+	return tok, nil
+}
@@ -0,0 +1,567 @@
+package temporal
+
+import (
+	"bytes"
+	"context"
+	"embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"math"
+	"path"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/brojonat/kaggo/server/api"
+	"github.com/brojonat/kaggo/server/db/jsonb"
+	"github.com/jmespath/go-jmespath"
+	"go.temporal.io/sdk/log"
+	"gopkg.in/yaml.v3"
+)
+
+// Extractor specs describe how to pull metrics and metadata out of a response
+// body with JMESPath expressions so that supporting a new field (or an entirely
+// new RequestKind with a simple response) doesn't require a hand written
+// handler. The defaults are embedded in the binary; the worker may override
+// them from a directory on startup (see LoadExtractorSpecs).
+//
+//go:embed extractors/*.yaml
+var defaultExtractorSpecs embed.FS
+
+// Supported field types. Values are coerced to these types after the JMESPath
+// expression is evaluated; e.g., YouTube returns counts as strings, so "int"
+// will happily parse "123".
+const (
+	FieldTypeString      = "string"
+	FieldTypeInt         = "int"
+	FieldTypeFloat       = "float"
+	FieldTypeBool        = "bool"
+	FieldTypeUnixTime    = "unix_time"
+	FieldTypeRFC3339Time = "rfc3339_time"
+	FieldTypeStrings     = "strings"
+)
+
+// FieldSpec describes how to extract a single value.
+type FieldSpec struct {
+	// Expr is the JMESPath expression evaluated against the response body.
+	Expr string `yaml:"expr" json:"expr"`
+	// Type is one of the FieldType* constants; defaults to string.
+	Type string `yaml:"type,omitempty" json:"type,omitempty"`
+	// Optional fields are omitted rather than failing the extraction when the
+	// expression evaluates to null.
+	Optional bool `yaml:"optional,omitempty" json:"optional,omitempty"`
+	// Split and Index pick a single element out of a delimited string result
+	// (e.g., the post title component of a reddit permalink). A negative Index
+	// counts from the end.
+	Split string `yaml:"split,omitempty" json:"split,omitempty"`
+	Index int    `yaml:"index,omitempty" json:"index,omitempty"`
+
+	compiled *jmespath.JMESPath
+}
+
+// MetricFieldSpec is a FieldSpec for a metric value. By default metrics are
+// uploaded to the generic metrics endpoint under their name, so Key and SetKey
+// are ignored. If the spec has a Path, metrics are instead uploaded as
+// {"<key>": value, "<set_key>": true}; Key defaults to the metric name with
+// dashes replaced by underscores and SetKey defaults to "set_<key>". An SetKey
+// of "-" omits the set flag entirely.
+type MetricFieldSpec struct {
+	FieldSpec `yaml:",inline"`
+	Key       string `yaml:"key,omitempty" json:"key,omitempty"`
+	SetKey    string `yaml:"set_key,omitempty" json:"set_key,omitempty"`
+}
+
+type MetricsExtractorSpec struct {
+	// Path is an optional kind specific kaggo endpoint the metrics are
+	// uploaded to. If empty, the metrics are uploaded to the generic metrics
+	// endpoint (see MetricsUploadPath).
+	Path string `yaml:"path,omitempty" json:"path,omitempty"`
+	// SkipIf is an optional expression; if it evaluates to a truthy value,
+	// nothing is uploaded (e.g., a Twitch user that isn't currently live).
+	SkipIf  string                     `yaml:"skip_if,omitempty" json:"skip_if,omitempty"`
+	ID      FieldSpec                  `yaml:"id" json:"id"`
+	Metrics map[string]MetricFieldSpec `yaml:"metrics" json:"metrics"`
+
+	skipIf *jmespath.JMESPath
+}
+
+type MetadataExtractorSpec struct {
+	ID FieldSpec `yaml:"id" json:"id"`
+	// Fields maps jsonb.MetadataJSON json keys to their FieldSpecs.
+	Fields map[string]FieldSpec `yaml:"fields" json:"fields"`
+}
+
+// ExtractorSpec is the extractor configuration for a single RequestKind.
+// Either section may be omitted, in which case the Go handler in the registry
+// is used.
+type ExtractorSpec struct {
+	RequestKind string                 `yaml:"request_kind" json:"request_kind"`
+	Metrics     *MetricsExtractorSpec  `yaml:"metrics,omitempty" json:"metrics,omitempty"`
+	Metadata    *MetadataExtractorSpec `yaml:"metadata,omitempty" json:"metadata,omitempty"`
+}
+
+// the loaded extractor specs keyed by RequestKind
+var (
+	extractorSpecs       map[string]*ExtractorSpec
+	extractorSpecLoadErr error
+)
+
+func init() {
+	fsys, err := fs.Sub(defaultExtractorSpecs, "extractors")
+	if err != nil {
+		extractorSpecLoadErr = err
+		return
+	}
+	extractorSpecs, extractorSpecLoadErr = ParseExtractorSpecs(fsys)
+}
+
+// LoadExtractorSpecs replaces the loaded extractor specs with those parsed from
+// the supplied filesystem. This is not safe to call concurrently with request
+// handling; call it on startup before the worker starts.
+func LoadExtractorSpecs(fsys fs.FS) error {
+	specs, err := ParseExtractorSpecs(fsys)
+	if err != nil {
+		return err
+	}
+	extractorSpecs, extractorSpecLoadErr = specs, nil
+	return nil
+}
+
+// GetExtractorSpec returns the loaded spec for the RequestKind, or nil.
+func GetExtractorSpec(rk string) *ExtractorSpec {
+	return extractorSpecs[rk]
+}
+
+// ParseExtractorSpecs parses and validates all the .yaml, .yml and .json files
+// in the root of fsys. Each file holds a single ExtractorSpec.
+func ParseExtractorSpecs(fsys fs.FS) (map[string]*ExtractorSpec, error) {
+	var files []string
+	for _, pattern := range []string{"*.yaml", "*.yml", "*.json"} {
+		m, err := fs.Glob(fsys, pattern)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, m...)
+	}
+
+	specs := map[string]*ExtractorSpec{}
+	var errs []error
+	for _, f := range files {
+		b, err := fs.ReadFile(fsys, f)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", f, err))
+			continue
+		}
+		s, err := ParseExtractorSpec(f, b)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", f, err))
+			continue
+		}
+		if _, ok := specs[s.RequestKind]; ok {
+			errs = append(errs, fmt.Errorf("%s: duplicate spec for %s", f, s.RequestKind))
+			continue
+		}
+		specs[s.RequestKind] = s
+	}
+	return specs, errors.Join(errs...)
+}
+
+// ParseExtractorSpec parses and validates a single spec. JSON is used if the
+// filename ends in .json, otherwise YAML.
+func ParseExtractorSpec(filename string, b []byte) (*ExtractorSpec, error) {
+	var s ExtractorSpec
+	if path.Ext(filename) == ".json" {
+		dec := json.NewDecoder(bytes.NewReader(b))
+		dec.DisallowUnknownFields()
+		if err := dec.Decode(&s); err != nil {
+			return nil, fmt.Errorf("error parsing spec: %w", err)
+		}
+	} else {
+		dec := yaml.NewDecoder(bytes.NewReader(b))
+		dec.KnownFields(true)
+		if err := dec.Decode(&s); err != nil {
+			return nil, fmt.Errorf("error parsing spec: %w", err)
+		}
+	}
+	if err := s.compile(); err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+// metadataJSONKeys are the json keys accepted in MetadataExtractorSpec.Fields.
+var metadataJSONKeys = func() map[string]bool {
+	keys := map[string]bool{}
+	t := reflect.TypeOf(jsonb.MetadataJSON{})
+	for i := 0; i < t.NumField(); i++ {
+		name, _, _ := strings.Cut(t.Field(i).Tag.Get("json"), ",")
+		if name != "" && name != "-" {
+			keys[name] = true
+		}
+	}
+	return keys
+}()
+
+func (f *FieldSpec) compile(name string) error {
+	if f.Expr == "" {
+		return fmt.Errorf("%s: missing expr", name)
+	}
+	switch f.Type {
+	case "":
+		f.Type = FieldTypeString
+	case FieldTypeString, FieldTypeInt, FieldTypeFloat, FieldTypeBool,
+		FieldTypeUnixTime, FieldTypeRFC3339Time, FieldTypeStrings:
+	default:
+		return fmt.Errorf("%s: unsupported type %s", name, f.Type)
+	}
+	if f.Split != "" && f.Type != FieldTypeString {
+		return fmt.Errorf("%s: split is only supported for string fields", name)
+	}
+	c, err := jmespath.Compile(f.Expr)
+	if err != nil {
+		return fmt.Errorf("%s: bad expr %q: %w", name, f.Expr, err)
+	}
+	f.compiled = c
+	return nil
+}
+
+func (s *ExtractorSpec) compile() error {
+	if s.RequestKind == "" {
+		return fmt.Errorf("missing request_kind")
+	}
+	if s.Metrics == nil && s.Metadata == nil {
+		return fmt.Errorf("%s: spec must have metrics and/or metadata", s.RequestKind)
+	}
+	var errs []error
+	if m := s.Metrics; m != nil {
+		if m.Path != "" && !strings.HasPrefix(m.Path, "/") {
+			errs = append(errs, fmt.Errorf("%s: metrics.path must start with /", s.RequestKind))
+		}
+		if err := m.ID.compile("metrics.id"); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", s.RequestKind, err))
+		}
+		if len(m.Metrics) == 0 {
+			errs = append(errs, fmt.Errorf("%s: metrics.metrics is empty", s.RequestKind))
+		}
+		for name, f := range m.Metrics {
+			if f.Type != FieldTypeInt && f.Type != FieldTypeFloat {
+				errs = append(errs, fmt.Errorf("%s: metric %s must be int or float", s.RequestKind, name))
+			}
+			if err := f.compile("metrics." + name); err != nil {
+				errs = append(errs, fmt.Errorf("%s: %w", s.RequestKind, err))
+			}
+			if f.Key == "" {
+				f.Key = strings.ReplaceAll(name, "-", "_")
+			}
+			if f.SetKey == "" {
+				f.SetKey = "set_" + f.Key
+			}
+			m.Metrics[name] = f
+		}
+		if m.SkipIf != "" {
+			c, err := jmespath.Compile(m.SkipIf)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("%s: bad skip_if %q: %w", s.RequestKind, m.SkipIf, err))
+			}
+			m.skipIf = c
+		}
+	}
+	if m := s.Metadata; m != nil {
+		if err := m.ID.compile("metadata.id"); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", s.RequestKind, err))
+		}
+		for key, f := range m.Fields {
+			if !metadataJSONKeys[key] {
+				errs = append(errs, fmt.Errorf("%s: metadata field %s is not a MetadataJSON field", s.RequestKind, key))
+				continue
+			}
+			if err := f.compile("metadata." + key); err != nil {
+				errs = append(errs, fmt.Errorf("%s: %w", s.RequestKind, err))
+			}
+			m.Fields[key] = f
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// extract evaluates the field against data and coerces the result. The second
+// return value is false if the field is optional and evaluated to null.
+// errNilField is returned when a required field evaluates to nil.
+var errNilField = errors.New("nil")
+
+func (f FieldSpec) extract(name string, data interface{}) (interface{}, bool, error) {
+	iface, err := f.compiled.Search(data)
+	if err != nil {
+		return nil, false, fmt.Errorf("error extracting %s: %w", name, err)
+	}
+	if iface == nil {
+		if f.Optional {
+			return nil, false, nil
+		}
+		return nil, false, fmt.Errorf("error extracting %s; %s is %w", name, name, errNilField)
+	}
+	v, err := coerceField(f.Type, iface)
+	if err != nil {
+		return nil, false, fmt.Errorf("error parsing %s: %w", name, err)
+	}
+	if f.Split != "" {
+		parts := strings.Split(v.(string), f.Split)
+		i := f.Index
+		if i < 0 {
+			i += len(parts)
+		}
+		if i < 0 || i >= len(parts) {
+			return nil, false, fmt.Errorf("error parsing %s: index %d out of range", name, f.Index)
+		}
+		v = parts[i]
+	}
+	return v, true, nil
+}
+
+func coerceField(typ string, iface interface{}) (interface{}, error) {
+	switch typ {
+	case FieldTypeString:
+		switch v := iface.(type) {
+		case string:
+			return v, nil
+		case float64:
+			return strconv.FormatFloat(v, 'f', -1, 64), nil
+		}
+	case FieldTypeInt:
+		switch v := iface.(type) {
+		case float64:
+			return int(math.Round(v)), nil
+		case string:
+			if i, err := strconv.Atoi(v); err == nil {
+				return i, nil
+			}
+			f, err := strconv.ParseFloat(v, 64)
+			if err != nil {
+				return nil, err
+			}
+			return int(math.Round(f)), nil
+		}
+	case FieldTypeFloat:
+		switch v := iface.(type) {
+		case float64:
+			return v, nil
+		case string:
+			return strconv.ParseFloat(v, 64)
+		}
+	case FieldTypeBool:
+		switch v := iface.(type) {
+		case bool:
+			return v, nil
+		case string:
+			return strconv.ParseBool(v)
+		}
+	case FieldTypeUnixTime:
+		switch v := iface.(type) {
+		case float64:
+			return time.Unix(int64(math.Round(v)), 0), nil
+		case string:
+			f, err := strconv.ParseFloat(v, 64)
+			if err != nil {
+				return nil, err
+			}
+			return time.Unix(int64(math.Round(f)), 0), nil
+		}
+	case FieldTypeRFC3339Time:
+		if v, ok := iface.(string); ok {
+			return time.Parse(time.RFC3339, v)
+		}
+	case FieldTypeStrings:
+		if vs, ok := iface.([]interface{}); ok {
+			ss := make([]string, 0, len(vs))
+			for _, v := range vs {
+				s, ok := v.(string)
+				if !ok {
+					return nil, fmt.Errorf("expected string, got %T", v)
+				}
+				ss = append(ss, s)
+			}
+			return ss, nil
+		}
+	}
+	return nil, fmt.Errorf("cannot coerce %T to %s", iface, typ)
+}
+
+// ExtractMetrics applies the metrics section of the spec to the response body
+// and returns the serialized upload payload. If the spec's skip_if condition
+// holds, the returned payload is nil.
+func (s *ExtractorSpec) ExtractMetrics(b []byte) ([]byte, error) {
+	if s.Metrics == nil {
+		return nil, fmt.Errorf("%s: no metrics extractor", s.RequestKind)
+	}
+	var data interface{}
+	if err := json.Unmarshal(b, &data); err != nil {
+		return nil, fmt.Errorf("error deserializing response: %w", err)
+	}
+	if s.Metrics.skipIf != nil {
+		iface, err := s.Metrics.skipIf.Search(data)
+		if err != nil {
+			return nil, fmt.Errorf("error evaluating skip_if: %w", err)
+		}
+		if isTruthy(iface) {
+			return nil, nil
+		}
+	}
+
+	// if there's no ID, the entity isn't in the response at all
+	id, _, err := s.Metrics.ID.extract("id", data)
+	if err != nil {
+		if errors.Is(err, errNilField) {
+			return nil, ErrContentGone{Err: err}
+		}
+		return nil, err
+	}
+	if s.Metrics.Path == "" {
+		payload := api.MetricSamplesPayload{
+			RequestKind: s.RequestKind,
+			ID:          fmt.Sprint(id),
+			Metrics:     map[string]float64{},
+		}
+		for name, f := range s.Metrics.Metrics {
+			v, ok, err := f.extract(name, data)
+			if err != nil {
+				return nil, err
+			}
+			if !ok {
+				continue
+			}
+			switch t := v.(type) {
+			case int:
+				payload.Metrics[name] = float64(t)
+			case float64:
+				payload.Metrics[name] = t
+			}
+		}
+		return json.Marshal(payload)
+	}
+	payload := map[string]interface{}{"id": id}
+	for name, f := range s.Metrics.Metrics {
+		v, ok, err := f.extract(name, data)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			continue
+		}
+		payload[f.Key] = v
+		if f.SetKey != "-" {
+			payload[f.SetKey] = true
+		}
+	}
+	return json.Marshal(payload)
+}
+
+// ExtractMetadata applies the metadata section of the spec to the response
+// body.
+func (s *ExtractorSpec) ExtractMetadata(b []byte) (*api.MetricMetadataPayload, error) {
+	if s.Metadata == nil {
+		return nil, fmt.Errorf("%s: no metadata extractor", s.RequestKind)
+	}
+	var data interface{}
+	if err := json.Unmarshal(b, &data); err != nil {
+		return nil, fmt.Errorf("error deserializing response: %w", err)
+	}
+	id, _, err := s.Metadata.ID.extract("id", data)
+	if err != nil {
+		return nil, err
+	}
+
+	// Build up the metadata as a map keyed by the json keys and then round
+	// trip it through json to get a MetadataJSON.
+	fields := map[string]interface{}{"id": id}
+	for key, f := range s.Metadata.Fields {
+		v, ok, err := f.extract(key, data)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			fields[key] = v
+		}
+	}
+	mb, err := json.Marshal(fields)
+	if err != nil {
+		return nil, fmt.Errorf("error serializing metadata: %w", err)
+	}
+	var md jsonb.MetadataJSON
+	if err = json.Unmarshal(mb, &md); err != nil {
+		return nil, fmt.Errorf("error deserializing metadata: %w", err)
+	}
+	if md.Tags == nil {
+		md.Tags = []string{}
+	}
+	return &api.MetricMetadataPayload{
+		ID:          md.ID,
+		RequestKind: s.RequestKind,
+		Data:        md,
+	}, nil
+}
+
+func isTruthy(v interface{}) bool {
+	switch t := v.(type) {
+	case nil:
+		return false
+	case bool:
+		return t
+	case string:
+		return t != ""
+	case []interface{}:
+		return len(t) > 0
+	case map[string]interface{}:
+		return len(t) > 0
+	}
+	return true
+}
+
+// extractorMetricsHandler is the ResponseHandler used for kinds whose metrics
+// are described by an extractor spec.
+func extractorMetricsHandler(rk string) ResponseHandler {
+	return func(a *ActivityRequester, ctx context.Context, l log.Logger, status int, b []byte) (*api.DefaultJSONResponse, error) {
+		s := GetExtractorSpec(rk)
+		if s == nil || s.Metrics == nil {
+			return nil, fmt.Errorf("no metrics extractor for %s", rk)
+		}
+		payload, err := s.ExtractMetrics(b)
+		if err != nil {
+			var gone ErrContentGone
+			if errors.As(err, &gone) {
+				return nil, gone
+			}
+			return nil, ErrNoRetry{Err: err}
+		}
+		if payload == nil {
+			return &api.DefaultJSONResponse{Message: "ok"}, nil
+		}
+		path := s.Metrics.Path
+		if path == "" {
+			path = MetricsUploadPath
+		}
+		return a.uploadMetrics(ctx, l, path, payload)
+	}
+}
+
+// extractorMetadataHandler is the ResponseHandler used for kinds whose metadata
+// is described by an extractor spec.
+func extractorMetadataHandler(rk string) ResponseHandler {
+	return func(a *ActivityRequester, ctx context.Context, l log.Logger, status int, b []byte) (*api.DefaultJSONResponse, error) {
+		s := GetExtractorSpec(rk)
+		if s == nil || s.Metadata == nil {
+			return nil, fmt.Errorf("no metadata extractor for %s", rk)
+		}
+		payload, err := s.ExtractMetadata(b)
+		if err != nil {
+			return nil, ErrNoRetry{Err: err}
+		}
+		b, err = json.Marshal(payload)
+		if err != nil {
+			return nil, ErrNoRetry{Err: fmt.Errorf("error serializing upload data: %w", err)}
+		}
+		return uploadMetadata(l, b)
+	}
+}
@@ -0,0 +1,168 @@
+package temporal
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/brojonat/kaggo/server/api"
+	"go.temporal.io/sdk/converter"
+	"go.temporal.io/sdk/temporal"
+	"go.temporal.io/sdk/workflow"
+)
+
+// Failure classification. When the content a schedule polls is deleted (or
+// made private), the provider keeps answering with a 404 (or a 200 without the
+// entity in it) until the schedule reaches its EndAt, which can be weeks of
+// wasted requests and quota. Polling failures are classified as permanent or
+// transient, and DoPollingRequestWF counts the permanent failures its schedule
+// has had in a row; each run hands the count to the next through its
+// completion result, so a run that fails permanently completes rather than
+// fails. Once there are pauseAfterPermanentFailures in a row, the server
+// pauses the schedule with a note and marks the entity ended. Transient
+// failures (e.g., 5xx responses) fail the run and leave the count alone.
+
+// pauseAfterPermanentFailures is the number of permanent failures in a row
+// that pauses a schedule.
+const pauseAfterPermanentFailures = 5
+
+// FailureClass is the kind of failure a polling request ran into.
+type FailureClass string
+
+const (
+	FailureTransient FailureClass = "transient"
+	FailureNotFound  FailureClass = "not-found"
+	FailureForbidden FailureClass = "forbidden"
+	FailureDeleted   FailureClass = "deleted"
+)
+
+// Permanent reports whether retrying later is pointless.
+func (c FailureClass) Permanent() bool {
+	return c == FailureNotFound || c == FailureForbidden || c == FailureDeleted
+}
+
+// FailureClassifier classifies a non-200 response.
+type FailureClassifier func(status int, body []byte) FailureClass
+
+// classifyStatus is the default FailureClassifier.
+func classifyStatus(status int, body []byte) FailureClass {
+	switch status {
+	case http.StatusNotFound:
+		return FailureNotFound
+	case http.StatusGone:
+		return FailureDeleted
+	case http.StatusForbidden:
+		return FailureForbidden
+	}
+	return FailureTransient
+}
+
+// classifyYouTubeFailure accounts for YouTube answering 403 when the key is
+// over quota, which says nothing about the content.
+func classifyYouTubeFailure(status int, body []byte) FailureClass {
+	if status == http.StatusForbidden {
+		var e struct {
+			Error struct {
+				Errors []struct {
+					Reason string `json:"reason"`
+				} `json:"errors"`
+			} `json:"error"`
+		}
+		if json.Unmarshal(body, &e) == nil {
+			for _, r := range e.Error.Errors {
+				switch r.Reason {
+				case "quotaExceeded", "dailyLimitExceeded", "rateLimitExceeded", "userRateLimitExceeded":
+					return FailureTransient
+				}
+			}
+		}
+	}
+	return classifyStatus(status, body)
+}
+
+// classifyFailure classifies a non-200 response to a request of kind rk.
+func classifyFailure(rk string, status int, body []byte) FailureClass {
+	rks, err := GetRequestKindSpec(rk)
+	if err == nil && rks.ClassifyFailure != nil {
+		return rks.ClassifyFailure(status, body)
+	}
+	return classifyStatus(status, body)
+}
+
+// ErrContentGone is returned by metrics handlers when a (200) response doesn't
+// contain the entity at all, as opposed to being malformed; e.g., YouTube
+// lists no items for a private video.
+type ErrContentGone struct {
+	Err error
+}
+
+func (e ErrContentGone) Error() string {
+	return e.Err.Error()
+}
+
+func (e ErrContentGone) Unwrap() error {
+	return e.Err
+}
+
+// isContentGone reports whether an activity failed with ErrContentGone.
+func isContentGone(err error) bool {
+	var ae *temporal.ApplicationError
+	return errors.As(err, &ae) && ae.Type() == "ErrContentGone"
+}
+
+// scheduledByID returns the ID of the schedule that started the workflow, if
+// it was started by one.
+func scheduledByID(ctx workflow.Context) string {
+	sa := workflow.GetInfo(ctx).SearchAttributes
+	if sa == nil || sa.IndexedFields["TemporalScheduledById"] == nil {
+		return ""
+	}
+	var sid string
+	if err := converter.GetDefaultDataConverter().FromPayload(sa.IndexedFields["TemporalScheduledById"], &sid); err != nil {
+		return ""
+	}
+	return sid
+}
+
+// pollingFailedPermanently records a permanent failure of a polling run and
+// pauses the schedule if it's had enough of them in a row.
+func pollingFailedPermanently(ctx workflow.Context, r DoPollingRequestWFRequest, prev DoPollingRequestWFResult, class FailureClass, cause error) (*DoPollingRequestWFResult, error) {
+	res := &DoPollingRequestWFResult{
+		ConsecutiveFailures: prev.ConsecutiveFailures + 1,
+		LastFailure:         fmt.Sprintf("%s: %s", class, cause),
+		CadenceReviewedAt:   prev.CadenceReviewedAt,
+	}
+	sid := scheduledByID(ctx)
+	workflow.GetLogger(ctx).Warn(
+		"polling request failed permanently", "request_kind", r.RequestKind, "schedule_id", sid,
+		"consecutive_failures", res.ConsecutiveFailures, "error", cause.Error())
+	if sid == "" || res.ConsecutiveFailures < pauseAfterPermanentFailures {
+		return res, nil
+	}
+
+	var a *ActivityRequester
+	activityOptions := workflow.ActivityOptions{
+		StartToCloseTimeout: 30 * time.Second,
+		RetryPolicy:         &temporal.RetryPolicy{MaximumAttempts: 5, BackoffCoefficient: 5},
+	}
+	ctx = workflow.WithActivityOptions(ctx, activityOptions)
+	p := api.SchedulePauseFailingPayload{
+		ScheduleID: sid,
+		Failures:   res.ConsecutiveFailures,
+		Reason:     string(class),
+	}
+	if err := workflow.ExecuteActivity(ctx, a.PauseFailingSchedule, p).Get(ctx, nil); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// PauseFailingSchedule asks the kaggo server to pause a schedule that keeps
+// failing permanently.
+func (a *ActivityRequester) PauseFailingSchedule(ctx context.Context, p api.SchedulePauseFailingPayload) error {
+	var res api.DefaultJSONResponse
+	return postToKaggo(ctx, "/schedule/pause-failing", p, &res)
+}
@@ -2,6 +2,7 @@ package temporal
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -10,17 +11,40 @@ import (
 	"os"
 	"slices"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/brojonat/kaggo/server/api"
 	"github.com/jmespath/go-jmespath"
+	"go.temporal.io/sdk/activity"
 	"go.temporal.io/sdk/log"
 	"golang.org/x/sync/errgroup"
 	"gonum.org/v1/gonum/stat"
 )
 
-// Helper to upload metrics to the kaggo backend
-func uploadMetrics(l log.Logger, path string, b []byte) (*api.DefaultJSONResponse, error) {
+// MetricsUploadPath is the generic kaggo endpoint that accepts an
+// api.MetricSamplesPayload for any RequestKind.
+const MetricsUploadPath = "/metrics"
+
+// Helper to upload metrics to the kaggo backend. Uploads are stamped with the
+// time the metrics were observed, and uploads to the generic endpoint are also
+// stamped with an idempotency key (see stampMetricSamples) so that retrying
+// the upload doesn't duplicate samples. If the requester has a MetricWriter,
+// uploads to the generic endpoint are written directly to the database
+// instead (see ingest.go).
+func (a *ActivityRequester) uploadMetrics(ctx context.Context, l log.Logger, path string, b []byte) (*api.DefaultJSONResponse, error) {
+	var err error
+	if path == MetricsUploadPath {
+		b, err = stampMetricSamples(ctx, b)
+	} else {
+		b, err = stampObservationTime(ctx, b, keyedUploadPaths[path])
+	}
+	if err != nil {
+		return nil, ErrNoRetry{Err: err}
+	}
+	if path == MetricsUploadPath && a.MetricWriter != nil {
+		return a.writeMetrics(ctx, b)
+	}
 	endpoint := os.Getenv("KAGGO_ENDPOINT") + path
 	r, err := http.NewRequest(http.MethodPost, endpoint, bytes.NewReader(b))
 	if err != nil {
@@ -49,7 +73,110 @@ func uploadMetrics(l log.Logger, path string, b []byte) (*api.DefaultJSONRespons
 	return &body, nil
 }
 
-func uploadMonitorPosts(l log.Logger, b []byte) error {
+type observedAtKey struct{}
+
+// withObservedAt returns a context carrying the time the response being
+// handled was fetched; metrics uploaded with it are stamped with that time.
+func withObservedAt(ctx context.Context, t time.Time) context.Context {
+	if t.IsZero() {
+		return ctx
+	}
+	return context.WithValue(ctx, observedAtKey{}, t)
+}
+
+// observedAt returns the time metrics uploaded with ctx were observed: when
+// the response was fetched, or, for responses fetched by workers that didn't
+// record that, when the upload activity was first scheduled. Either way it
+// doesn't change when the upload is retried.
+func observedAt(ctx context.Context) (time.Time, bool) {
+	if t, ok := ctx.Value(observedAtKey{}).(time.Time); ok {
+		return t, true
+	}
+	if !activity.IsActivity(ctx) {
+		return time.Time{}, false
+	}
+	return activity.GetInfo(ctx).ScheduledTime, true
+}
+
+// stampMetricSamples sets the idempotency key and observation time on an
+// api.MetricSamplesPayload, unless they're already set (e.g., by a backfill).
+// The key identifies the activity (the workflow ID and run ID, plus the
+// activity ID, since the batch workflow uploads many batches per run); like
+// the observation time, it doesn't change when the activity is retried, so the
+// server can drop the samples it already has.
+func stampMetricSamples(ctx context.Context, b []byte) ([]byte, error) {
+	if !activity.IsActivity(ctx) {
+		return b, nil
+	}
+	var p api.MetricSamplesPayload
+	if err := json.Unmarshal(b, &p); err != nil {
+		return nil, fmt.Errorf("error deserializing metrics: %w", err)
+	}
+	if p.IdempotencyKey == "" {
+		p.IdempotencyKey = idempotencyKey(ctx)
+	}
+	if p.Ts == nil {
+		ts, _ := observedAt(ctx)
+		p.Ts = &ts
+	}
+	return json.Marshal(p)
+}
+
+// idempotencyKey returns the key samples uploaded by the current activity are
+// stamped with (see stampMetricSamples).
+func idempotencyKey(ctx context.Context) string {
+	info := activity.GetInfo(ctx)
+	return fmt.Sprintf("%s %s %s", info.WorkflowExecution.ID, info.WorkflowExecution.RunID, info.ActivityID)
+}
+
+// Kind specific endpoints that accept an idempotency key like the generic
+// endpoint does.
+var keyedUploadPaths = map[string]bool{
+	"/internal/metrics": true,
+}
+
+// stampObservationTime sets the ts of a kind specific metrics payload and, if
+// key is set, its idempotency key, unless they're already set.
+func stampObservationTime(ctx context.Context, b []byte, key bool) ([]byte, error) {
+	ts, ok := observedAt(ctx)
+	if !ok {
+		return b, nil
+	}
+	var p map[string]json.RawMessage
+	if err := json.Unmarshal(b, &p); err != nil {
+		return nil, fmt.Errorf("error deserializing metrics: %w", err)
+	}
+	_, hasTs := p["ts"]
+	_, hasKey := p["idempotency_key"]
+	if hasTs && (!key || hasKey) {
+		return b, nil
+	}
+	if !hasTs {
+		v, err := json.Marshal(ts)
+		if err != nil {
+			return nil, err
+		}
+		p["ts"] = v
+	}
+	if key && !hasKey && activity.IsActivity(ctx) {
+		v, err := json.Marshal(idempotencyKey(ctx))
+		if err != nil {
+			return nil, err
+		}
+		p["idempotency_key"] = v
+	}
+	return json.Marshal(p)
+}
+
+// uploadMonitorPosts creates a schedule for each post in a subreddit/user
+// listing. The monitor (rk and the post's subreddit/author) is passed along as
+// the parent of each post so the server can notify anyone watching it.
+func uploadMonitorPosts(l log.Logger, rk string, b []byte) error {
+	parentField := "subreddit"
+	if rk == RequestKindRedditUserMonitor {
+		parentField = "author"
+	}
+
 	var data interface{}
 	if err := json.Unmarshal(b, &data); err != nil {
 		return fmt.Errorf("error deserializing response: %w", err)
@@ -116,11 +243,17 @@ func uploadMonitorPosts(l log.Logger, b []byte) error {
 				return fmt.Errorf("error extracting id for post %d: nil id", i)
 			}
 			id := iface.(string)
-			sched := GetDefaultScheduleSpec(RequestKindRedditPost, id)
+			iface, err = jmespath.Search(fmt.Sprintf("data.children[%d].data.%s", i, parentField), data)
+			if err != nil {
+				return fmt.Errorf("error extracting %s for post %d: %w", parentField, i, err)
+			}
+			parentID, _ := iface.(string)
 			payload := api.GenericScheduleRequestPayload{
-				RequestKind: RequestKindRedditPost,
-				ID:          id,
-				Schedule:    sched,
+				RequestKind:       RequestKindRedditPost,
+				ID:                id,
+				Preset:            SchedulePresetDefault,
+				ParentRequestKind: rk,
+				ParentID:          parentID,
 			}
 			b, err := json.Marshal(payload)
 			if err != nil {
@@ -154,751 +287,176 @@ func uploadMonitorPosts(l log.Logger, b []byte) error {
 	return errg.Wait()
 }
 
-// Handle RequestKindInternalRandom requests
-func (a *ActivityRequester) handleInternalRandomMetrics(l log.Logger, status int, b []byte) (*api.DefaultJSONResponse, error) {
-	var data interface{}
-	if err := json.Unmarshal(b, &data); err != nil {
-		return nil, ErrNoRetry{Err: fmt.Errorf("error deserializing internal response: %w", err)}
-	}
-
-	// id
-	iface, err := jmespath.Search("id", data)
-	if err != nil {
-		return nil, ErrNoRetry{Err: fmt.Errorf("error extracting id: %w", err)}
-	}
-	if iface == nil {
-		return nil, ErrNoRetry{Err: fmt.Errorf("error extracting id; id is nil")}
-	}
-	id := iface.(string)
-
-	// value
-	iface, err = jmespath.Search("value", data)
+func (a *ActivityRequester) handleRedditSubredditMonitorMetrics(ctx context.Context, l log.Logger, status int, b []byte) (*api.DefaultJSONResponse, error) {
+	err := uploadMonitorPosts(l, RequestKindRedditSubredditMonitor, b)
 	if err != nil {
-		return nil, ErrNoRetry{Err: fmt.Errorf("error extracting value: %w", err)}
-	}
-	if iface == nil {
-		return nil, ErrNoRetry{Err: fmt.Errorf("error extracting value; value is nil")}
-	}
-	value := iface.(float64)
-
-	payload := api.InternalMetricPayload{
-		ID:    id,
-		Value: int(math.Round(value)),
-	}
-	b, err = json.Marshal(payload)
-	if err != nil {
-		return nil, ErrNoRetry{Err: fmt.Errorf("error serializing upload data: %w", err)}
+		return nil, fmt.Errorf("error doing subreddit monitor upload: %w", err)
 	}
-	return uploadMetrics(l, "/internal/metrics", b)
+	return &api.DefaultJSONResponse{Message: "ok"}, nil
 }
 
-// Handle RequestKindYouTubeVideo requests
-func (a *ActivityRequester) handleYouTubeVideoMetrics(l log.Logger, status int, b []byte) (*api.DefaultJSONResponse, error) {
-	var data interface{}
-	if err := json.Unmarshal(b, &data); err != nil {
-		return nil, ErrNoRetry{Err: fmt.Errorf("error deserializing response: %w", err)}
-	}
-
-	// id
-	iface, err := jmespath.Search("items[0].id", data)
-	if err != nil {
-		return nil, ErrNoRetry{Err: fmt.Errorf("error extracting id: %w", err)}
-	}
-	if iface == nil {
-		return nil, ErrNoRetry{Err: fmt.Errorf("error extracting id; id is nil")}
-	}
-	id := iface.(string)
-
-	// views
-	iface, err = jmespath.Search("items[0].statistics.viewCount", data)
-	if err != nil {
-		return nil, ErrNoRetry{Err: fmt.Errorf("error extracting views: %w", err)}
-	}
-	if iface == nil {
-		return nil, ErrNoRetry{Err: fmt.Errorf("error extracting views; views is nil")}
-	}
-	viewsStr := iface.(string)
-	views, err := strconv.Atoi(viewsStr)
-	if err != nil {
-		return nil, ErrNoRetry{Err: fmt.Errorf("error parsing views")}
-	}
-
-	// comments
-	iface, err = jmespath.Search("items[0].statistics.commentCount", data)
-	if err != nil {
-		return nil, ErrNoRetry{Err: fmt.Errorf("error extracting commentCount: %w", err)}
-	}
-	if iface == nil {
-		return nil, ErrNoRetry{Err: fmt.Errorf("error extracting commentCount; commentCount is nil")}
-	}
-	commentsStr := iface.(string)
-	comments, err := strconv.Atoi(commentsStr)
-	if err != nil {
-		return nil, ErrNoRetry{Err: fmt.Errorf("error parsing comments")}
-	}
-
-	// likes
-	iface, err = jmespath.Search("items[0].statistics.likeCount", data)
+func (a *ActivityRequester) handleRedditUserMonitorMetrics(ctx context.Context, l log.Logger, status int, b []byte) (*api.DefaultJSONResponse, error) {
+	err := uploadMonitorPosts(l, RequestKindRedditUserMonitor, b)
 	if err != nil {
-		return nil, ErrNoRetry{Err: fmt.Errorf("error extracting likeCount: %w", err)}
-	}
-	if iface == nil {
-		return nil, ErrNoRetry{Err: fmt.Errorf("error extracting likeCount; likeCount is nil")}
-	}
-	likesStr := iface.(string)
-	likes, err := strconv.Atoi(likesStr)
-	if err != nil {
-		return nil, ErrNoRetry{Err: fmt.Errorf("error parsing likes")}
-	}
-
-	payload := api.YouTubeVideoMetricPayload{
-		ID:          id,
-		SetViews:    true,
-		Views:       views,
-		SetComments: true,
-		Comments:    comments,
-		SetLikes:    true,
-		Likes:       likes,
-	}
-	b, err = json.Marshal(payload)
-	if err != nil {
-		return nil, ErrNoRetry{Err: fmt.Errorf("error serializing upload data: %w", err)}
+		return nil, fmt.Errorf("error doing user monitor upload: %w", err)
 	}
-	return uploadMetrics(l, "/youtube/video", b)
+	return &api.DefaultJSONResponse{Message: "ok"}, nil
 }
 
-// Handle RequestKindYouTubeChannel requests
-func (a *ActivityRequester) handleYouTubeChannelMetrics(l log.Logger, status int, b []byte) (*api.DefaultJSONResponse, error) {
-	var data interface{}
-	if err := json.Unmarshal(b, &data); err != nil {
-		return nil, ErrNoRetry{Err: fmt.Errorf("error deserializing internal response: %w", err)}
-	}
-
-	// id
-	iface, err := jmespath.Search("items[0].id", data)
-	if err != nil {
-		return nil, ErrNoRetry{Err: fmt.Errorf("error extracting id: %w", err)}
-	}
-	if iface == nil {
-		return nil, ErrNoRetry{Err: fmt.Errorf("error extracting id; id is nil")}
-	}
-	id := iface.(string)
-
-	// views
-	iface, err = jmespath.Search("items[0].statistics.viewCount", data)
-	if err != nil {
-		return nil, ErrNoRetry{Err: fmt.Errorf("error extracting views: %w", err)}
-	}
-	if iface == nil {
-		return nil, ErrNoRetry{Err: fmt.Errorf("error extracting views; views is nil")}
-	}
-	viewsStr := iface.(string)
-	views, err := strconv.Atoi(viewsStr)
-	if err != nil {
-		return nil, ErrNoRetry{Err: fmt.Errorf("error parsing views")}
-	}
-
-	// subscribers
-	iface, err = jmespath.Search("items[0].statistics.subscriberCount", data)
-	if err != nil {
-		return nil, ErrNoRetry{Err: fmt.Errorf("error extracting subscriberCount: %w", err)}
-	}
-	if iface == nil {
-		return nil, ErrNoRetry{Err: fmt.Errorf("error extracting subscriberCount; subscriberCount is nil")}
-	}
-	subStr := iface.(string)
-	subscribers, err := strconv.Atoi(subStr)
-	if err != nil {
-		return nil, ErrNoRetry{Err: fmt.Errorf("error parsing subscribers")}
-	}
-
-	// videos
-	iface, err = jmespath.Search("items[0].statistics.videoCount", data)
-	if err != nil {
-		return nil, ErrNoRetry{Err: fmt.Errorf("error extracting videoCount: %w", err)}
-	}
-	if iface == nil {
-		return nil, ErrNoRetry{Err: fmt.Errorf("error extracting videoCount; videoCount is nil")}
-	}
-	videosStr := iface.(string)
-	videos, err := strconv.Atoi(videosStr)
-	if err != nil {
-		return nil, ErrNoRetry{Err: fmt.Errorf("error parsing videos")}
-	}
-
-	payload := api.YouTubeChannelMetricPayload{
-		ID:             id,
-		SetViews:       true,
-		Views:          views,
-		SetSubscribers: true,
-		Subscribers:    subscribers,
-		SetVideos:      true,
-		Videos:         videos,
-	}
-	b, err = json.Marshal(payload)
-	if err != nil {
-		return nil, ErrNoRetry{Err: fmt.Errorf("error serializing upload data: %w", err)}
+// Handle RequestKindTwitchUserLastDec requests
+func (a *ActivityRequester) handleTwitchUserPastDecMetrics(ctx context.Context, l log.Logger, status int, b []byte) (*api.DefaultJSONResponse, error) {
+	var body struct {
+		Data []struct {
+			UserID    string `json:"user_login"`
+			ViewCount int    `json:"view_count"`
+			Duration  string `json:"duration"`
+		}
 	}
-	return uploadMetrics(l, "/youtube/channel", b)
-}
 
-// Handle RequestKindKaggleNotebook requests
-func (a *ActivityRequester) handleKaggleNotebookMetrics(l log.Logger, status int, b []byte) (*api.DefaultJSONResponse, error) {
-	var data interface{}
-	if err := json.Unmarshal(b, &data); err != nil {
+	if err := json.Unmarshal(b, &body); err != nil {
 		return nil, ErrNoRetry{Err: fmt.Errorf("error deserializing response: %w", err)}
 	}
 
-	// id
-	iface, err := jmespath.Search("[0].ref", data)
-	if err != nil {
-		return nil, ErrNoRetry{Err: fmt.Errorf("error extracting ref: %w", err)}
-	}
-	if iface == nil {
-		return nil, ErrNoRetry{Err: fmt.Errorf("error extracting ref; ref is nil")}
-	}
-	id := iface.(string)
-
-	// votes
-	iface, err = jmespath.Search("[0].totalVotes", data)
-	if err != nil {
-		return nil, ErrNoRetry{Err: fmt.Errorf("error extracting votes: %w", err)}
-	}
-	if iface == nil {
-		return nil, ErrNoRetry{Err: fmt.Errorf("error extracting totalVotes; totalVotes is nil")}
-	}
-	votes := iface.(float64)
-
-	// upload the metrics to the server
-	payload := api.KaggleNotebookMetricPayload{
-		ID:       id,
-		SetVotes: true,
-		Votes:    int(math.Round(votes)),
-	}
-	b, err = json.Marshal(payload)
-	if err != nil {
-		return nil, ErrNoRetry{Err: fmt.Errorf("error serializing upload data: %w", err)}
-	}
-	return uploadMetrics(l, "/kaggle/notebook", b)
-}
-
-// Handle RequestKindKaggleDataset requests
-func (a *ActivityRequester) handleKaggleDatasetMetrics(l log.Logger, status int, b []byte) (*api.DefaultJSONResponse, error) {
+	views := []float64{}
+	durs := []float64{}
 
-	var data interface{}
-	if err := json.Unmarshal(b, &data); err != nil {
-		return nil, ErrNoRetry{Err: fmt.Errorf("error deserializing response: %w", err)}
+	for _, v := range body.Data {
+		views = append(views, float64(v.ViewCount))
+		d, err := time.ParseDuration(v.Duration)
+		if err != nil {
+			return nil, ErrNoRetry{Err: fmt.Errorf("error parsing video duration %s: %w", v.Duration, err)}
+		}
+		durs = append(durs, float64(d/time.Second))
 	}
 
-	// id
-	iface, err := jmespath.Search("[0].ref", data)
-	if err != nil {
-		return nil, ErrNoRetry{Err: fmt.Errorf("error extracting ref: %w", err)}
-	}
-	if iface == nil {
-		return nil, ErrNoRetry{Err: fmt.Errorf("error extracting ref; ref is nil")}
-	}
-	id := iface.(string)
+	slices.Sort(views)
+	slices.Sort(durs)
 
 	// views
-	iface, err = jmespath.Search("[0].viewCount", data)
-	if err != nil {
-		return nil, ErrNoRetry{Err: fmt.Errorf("error extracting viewCount: %w", err)}
-	}
-	if iface == nil {
-		return nil, ErrNoRetry{Err: fmt.Errorf("error extracting viewCount; viewCount is nil")}
-	}
-	views := iface.(float64)
-
-	// votes
-	iface, err = jmespath.Search("[0].voteCount", data)
-	if err != nil {
-		return nil, ErrNoRetry{Err: fmt.Errorf("error extracting votes: %w", err)}
-	}
-	if iface == nil {
-		return nil, ErrNoRetry{Err: fmt.Errorf("error extracting voteCount; voteCount is nil")}
-	}
-	votes := iface.(float64)
-
-	// downloads
-	iface, err = jmespath.Search("[0].downloadCount", data)
-	if err != nil {
-		return nil, ErrNoRetry{Err: fmt.Errorf("error extracting downloadCount: %w", err)}
-	}
-	if iface == nil {
-		return nil, ErrNoRetry{Err: fmt.Errorf("error extracting downloadCount; downloadCount is nil")}
-	}
-	downloads := iface.(float64)
-
-	// upload the metrics to the server
-	payload := api.KaggleDatasetMetricPayload{
-		ID:           id,
-		SetViews:     true,
-		Views:        int(math.Round(views)),
-		SetVotes:     true,
-		Votes:        int(math.Round(votes)),
-		SetDownloads: true,
-		Downloads:    int(math.Round(downloads)),
-	}
-
-	b, err = json.Marshal(payload)
-	if err != nil {
-		return nil, ErrNoRetry{Err: fmt.Errorf("error serializing upload data: %w", err)}
-	}
-	return uploadMetrics(l, "/kaggle/dataset", b)
-}
-
-// Handle RequestKindRedditPost requests
-func (a *ActivityRequester) handleRedditPostMetrics(l log.Logger, status int, b []byte) (*api.DefaultJSONResponse, error) {
-
-	var data interface{}
-	if err := json.Unmarshal(b, &data); err != nil {
-		return nil, ErrNoRetry{Err: fmt.Errorf("error deserializing response: %w", err)}
-	}
-
-	// id
-	iface, err := jmespath.Search("data.children[0].data.id", data)
-	if err != nil {
-		return nil, ErrNoRetry{Err: fmt.Errorf("error extracting id: %w", err)}
-	}
-	if iface == nil {
-		return nil, ErrNoRetry{Err: fmt.Errorf("error extracting id; id is nil")}
-	}
-	id := iface.(string)
-
-	// score
-	iface, err = jmespath.Search("data.children[0].data.score", data)
-	if err != nil {
-		return nil, ErrNoRetry{Err: fmt.Errorf("error extracting score: %w", err)}
-	}
-	if iface == nil {
-		return nil, ErrNoRetry{Err: fmt.Errorf("error extracting score; score is nil")}
-	}
-	score := iface.(float64)
-
-	// ratio
-	iface, err = jmespath.Search("data.children[0].data.upvote_ratio", data)
-	if err != nil {
-		return nil, ErrNoRetry{Err: fmt.Errorf("error extracting upvote_ratio: %w", err)}
-	}
-	if iface == nil {
-		return nil, ErrNoRetry{Err: fmt.Errorf("error extracting upvote_ratio; upvote_ratio is nil")}
-	}
-	ratio := iface.(float64)
-
-	// upload the metrics to the server
-	payload := api.RedditPostMetricPayload{
-		ID:       id,
-		SetScore: true,
-		Score:    int(math.Round(score)),
-		SetRatio: true,
-		Ratio:    float32(ratio),
-	}
-
-	b, err = json.Marshal(payload)
-	if err != nil {
-		return nil, ErrNoRetry{Err: fmt.Errorf("error serializing upload data: %w", err)}
-	}
-	return uploadMetrics(l, "/reddit/post", b)
-}
-
-// Handle RequestKindRedditComment requests
-func (a *ActivityRequester) handleRedditCommentMetrics(l log.Logger, status int, b []byte) (*api.DefaultJSONResponse, error) {
-
-	var data interface{}
-	if err := json.Unmarshal(b, &data); err != nil {
-		return nil, ErrNoRetry{Err: fmt.Errorf("error deserializing response: %w", err)}
-	}
-
-	// id
-	iface, err := jmespath.Search("data.children[0].data.id", data)
-	if err != nil {
-		return nil, ErrNoRetry{Err: fmt.Errorf("error extracting id: %w", err)}
-	}
-	if iface == nil {
-		return nil, ErrNoRetry{Err: fmt.Errorf("error extracting id; id is nil")}
-	}
-	id := iface.(string)
-
-	// score
-	iface, err = jmespath.Search("data.children[0].data.score", data)
-	if err != nil {
-		return nil, ErrNoRetry{Err: fmt.Errorf("error extracting score: %w", err)}
-	}
-	if iface == nil {
-		return nil, ErrNoRetry{Err: fmt.Errorf("error extracting score; score is nil")}
-	}
-	score := iface.(float64)
-
-	// controversiality
-	iface, err = jmespath.Search("data.children[0].data.controversiality", data)
-	if err != nil {
-		return nil, ErrNoRetry{Err: fmt.Errorf("error extracting id: %w", err)}
-	}
-	if iface == nil {
-		return nil, ErrNoRetry{Err: fmt.Errorf("error extracting controversiality; controversiality is nil")}
-	}
-	cont := iface.(float64)
-
-	// upload the metrics to the server
-	payload := api.RedditCommentMetricPayload{
-		ID:                  id,
-		SetScore:            true,
-		Score:               int(math.Round(score)),
-		SetControversiality: true,
-		Controversiality:    float32(cont),
-	}
-
-	b, err = json.Marshal(payload)
-	if err != nil {
-		return nil, ErrNoRetry{Err: fmt.Errorf("error serializing upload data: %w", err)}
-	}
-	return uploadMetrics(l, "/reddit/comment", b)
-}
-
-// Handle RequestKindRedditSubreddit requests
-func (a *ActivityRequester) handleRedditSubredditMetrics(l log.Logger, status int, b []byte) (*api.DefaultJSONResponse, error) {
-	var data interface{}
-	if err := json.Unmarshal(b, &data); err != nil {
-		return nil, ErrNoRetry{Err: fmt.Errorf("error deserializing response: %w", err)}
-	}
-	// id
-	iface, err := jmespath.Search("data.display_name", data)
-	if err != nil {
-		return nil, ErrNoRetry{Err: fmt.Errorf("error extracting id: %w", err)}
-	}
-	if iface == nil {
-		return nil, ErrNoRetry{Err: fmt.Errorf("error extracting id; id is nil")}
-	}
-	id := iface.(string)
-
-	// subscribers
-	iface, err = jmespath.Search("data.subscribers", data)
-	if err != nil {
-		return nil, ErrNoRetry{Err: fmt.Errorf("error extracting subscribers: %w", err)}
-	}
-	if iface == nil {
-		return nil, ErrNoRetry{Err: fmt.Errorf("error extracting subscribers; subscribers is nil")}
-	}
-	subscribers := iface.(float64)
+	avc := stat.Mean(views, nil)
+	mvc := stat.Quantile(0.5, stat.LinInterp, views, nil)
+	svc := stat.StdDev(views, nil)
 
-	// active user count
-	iface, err = jmespath.Search("data.active_user_count", data)
-	if err != nil {
-		return nil, ErrNoRetry{Err: fmt.Errorf("error extracting active_user_count: %w", err)}
-	}
-	if iface == nil {
-		return nil, ErrNoRetry{Err: fmt.Errorf("error extracting active_user_count; active_user_count is nil")}
-	}
-	active_user_count := iface.(float64)
+	// durations
+	ad := stat.Mean(durs, nil)
+	md := stat.Quantile(0.5, stat.LinInterp, durs, nil)
+	sd := stat.StdDev(durs, nil)
 
 	// upload the metrics to the server
-	payload := api.RedditSubredditMetricPayload{
-		ID:                 id,
-		SetSubscribers:     true,
-		Subscribers:        int(math.Round(subscribers)),
-		SetActiveUserCount: true,
-		ActiveUserCount:    int(math.Round(active_user_count)),
+	payload := api.MetricSamplesPayload{
+		RequestKind: RequestKindTwitchUserPastDec,
+		ID:          body.Data[0].UserID,
+		Metrics: map[string]float64{
+			"avg-views":    avc,
+			"med-views":    mvc,
+			"std-views":    svc,
+			"avg-duration": ad,
+			"med-duration": md,
+			"std-duration": sd,
+		},
 	}
-	b, err = json.Marshal(payload)
+	b, err := json.Marshal(payload)
 	if err != nil {
 		return nil, ErrNoRetry{Err: fmt.Errorf("error serializing upload metadata: %w", err)}
 	}
-	return uploadMetrics(l, "/reddit/subreddit", b)
+	return a.uploadMetrics(ctx, l, MetricsUploadPath, b)
 }
 
-func (a *ActivityRequester) handleRedditSubredditMonitorMetrics(l log.Logger, status int, b []byte) (*api.DefaultJSONResponse, error) {
-	err := uploadMonitorPosts(l, b)
-	if err != nil {
-		return nil, fmt.Errorf("error doing subreddit monitor upload: %w", err)
-	}
-	return &api.DefaultJSONResponse{Message: "ok"}, nil
-}
-
-// Handle RequestKindRedditUser requests
-func (a *ActivityRequester) handleRedditUserMetrics(l log.Logger, status int, b []byte) (*api.DefaultJSONResponse, error) {
-	var data interface{}
-	if err := json.Unmarshal(b, &data); err != nil {
-		return nil, ErrNoRetry{Err: fmt.Errorf("error deserializing response: %w", err)}
-	}
-	// name
-	iface, err := jmespath.Search("data.name", data)
-	if err != nil {
-		return nil, ErrNoRetry{Err: fmt.Errorf("error extracting name: %w", err)}
-	}
-	if iface == nil {
-		return nil, ErrNoRetry{Err: fmt.Errorf("error extracting name; name is nil")}
-	}
-	name := iface.(string)
-
-	// awardee karma
-	iface, err = jmespath.Search("data.awardee_karma", data)
-	if err != nil {
-		return nil, ErrNoRetry{Err: fmt.Errorf("error extracting awardee_karma: %w", err)}
-	}
-	if iface == nil {
-		return nil, ErrNoRetry{Err: fmt.Errorf("error extracting awardee_karma; awardee_karma is nil")}
-	}
-	awardee_karma := iface.(float64)
-
-	// awarder karma
-	iface, err = jmespath.Search("data.awarder_karma", data)
-	if err != nil {
-		return nil, ErrNoRetry{Err: fmt.Errorf("error extracting awarder_karma: %w", err)}
-	}
-	if iface == nil {
-		return nil, ErrNoRetry{Err: fmt.Errorf("error extracting awarder_karma; awarder_karma is nil")}
-	}
-	awarder_karma := iface.(float64)
-
-	// comment karma
-	iface, err = jmespath.Search("data.comment_karma", data)
+// Handle RequestKindKaggleCompetition requests. The team count comes from the
+// competition listing, but the top score is only available from the
+// leaderboard, so this follows up with a leaderboard request. If that fails
+// (e.g., the leaderboard is hidden), the team count is still uploaded.
+func (a *ActivityRequester) handleKaggleCompetitionMetrics(ctx context.Context, l log.Logger, status int, b []byte) (*api.DefaultJSONResponse, error) {
+	payload, err := extractKaggleCompetitionMetrics(b, nil)
 	if err != nil {
-		return nil, ErrNoRetry{Err: fmt.Errorf("error extracting comment_karma: %w", err)}
-	}
-	if iface == nil {
-		return nil, ErrNoRetry{Err: fmt.Errorf("error extracting comment_karma; comment_karma is nil")}
+		return nil, ErrNoRetry{Err: err}
 	}
-	comment_karma := iface.(float64)
-
-	// link karma
-	iface, err = jmespath.Search("data.link_karma", data)
+	lb, err := a.fetchKaggleLeaderboard(ctx, payload.ID)
 	if err != nil {
-		return nil, ErrNoRetry{Err: fmt.Errorf("error extracting link_karma: %w", err)}
-	}
-	if iface == nil {
-		return nil, ErrNoRetry{Err: fmt.Errorf("error extracting link_karma; link_karma is nil")}
-	}
-	link_karma := iface.(float64)
-
-	// total karma
-	iface, err = jmespath.Search("data.total_karma", data)
-	if err != nil {
-		return nil, ErrNoRetry{Err: fmt.Errorf("error extracting total_karma: %w", err)}
-	}
-	if iface == nil {
-		return nil, ErrNoRetry{Err: fmt.Errorf("error extracting total_karma; name is nil")}
-	}
-	total_karma := iface.(float64)
-
-	// upload the metrics to the server
-	payload := api.RedditUserMetricPayload{
-		ID:              name, // name is our internal id for reddit users
-		SetAwardeeKarma: true,
-		AwardeeKarma:    int(awardee_karma),
-		SetAwarderKarma: true,
-		AwarderKarma:    int(awarder_karma),
-		SetCommentKarma: true,
-		CommentKarma:    int(comment_karma),
-		SetLinkKarma:    true,
-		LinkKarma:       int(link_karma),
-		SetTotalKarma:   true,
-		TotalKarma:      int(total_karma),
+		l.Warn("error fetching kaggle leaderboard", "id", payload.ID, "error", err.Error())
+	} else if payload, err = extractKaggleCompetitionMetrics(b, lb); err != nil {
+		return nil, ErrNoRetry{Err: err}
 	}
 	b, err = json.Marshal(payload)
 	if err != nil {
 		return nil, ErrNoRetry{Err: fmt.Errorf("error serializing upload metadata: %w", err)}
 	}
-	return uploadMetrics(l, "/reddit/user", b)
+	return a.uploadMetrics(ctx, l, MetricsUploadPath, b)
 }
 
-func (a *ActivityRequester) handleRedditUserMonitorMetrics(l log.Logger, status int, b []byte) (*api.DefaultJSONResponse, error) {
-	err := uploadMonitorPosts(l, b)
-	if err != nil {
-		return nil, fmt.Errorf("error doing user monitor upload: %w", err)
-	}
-	return &api.DefaultJSONResponse{Message: "ok"}, nil
-}
-
-// Handle RequestKindTwitchClip requests
-func (a *ActivityRequester) handleTwitchClipMetrics(l log.Logger, status int, b []byte) (*api.DefaultJSONResponse, error) {
-	var data interface{}
-	if err := json.Unmarshal(b, &data); err != nil {
-		return nil, ErrNoRetry{Err: fmt.Errorf("error deserializing response: %w", err)}
-	}
-	// id
-	iface, err := jmespath.Search("data[0].id", data)
-	if err != nil {
-		return nil, ErrNoRetry{Err: fmt.Errorf("error extracting id: %w", err)}
-	}
-	if iface == nil {
-		return nil, ErrNoRetry{Err: fmt.Errorf("error extracting id; id is nil")}
-	}
-	id := iface.(string)
-
-	// view_count
-	iface, err = jmespath.Search("data[0].view_count", data)
-	if err != nil {
-		return nil, ErrNoRetry{Err: fmt.Errorf("error extracting view_count: %w", err)}
-	}
-	if iface == nil {
-		return nil, ErrNoRetry{Err: fmt.Errorf("error extracting view_count; view_count is nil")}
-	}
-	vc := iface.(float64)
-
-	// upload the metrics to the server
-	payload := api.TwitchClipMetricPayload{
-		ID:           id,
-		SetViewCount: true,
-		ViewCount:    int(math.Round(vc)),
-	}
-	b, err = json.Marshal(payload)
-	if err != nil {
-		return nil, ErrNoRetry{Err: fmt.Errorf("error serializing upload metadata: %w", err)}
-	}
-	return uploadMetrics(l, "/twitch/clip", b)
+// kaggleCompetitionSlug returns the competition slug from a competition ref.
+// Depending on the API version, the ref is either the slug (e.g., "titanic")
+// or the competition URL.
+func kaggleCompetitionSlug(ref string) string {
+	ref = strings.TrimRight(ref, "/")
+	return ref[strings.LastIndex(ref, "/")+1:]
 }
 
-// Handle RequestKindTwitchVideo requests
-func (a *ActivityRequester) handleTwitchVideoMetrics(l log.Logger, status int, b []byte) (*api.DefaultJSONResponse, error) {
-	var data interface{}
-	if err := json.Unmarshal(b, &data); err != nil {
-		return nil, ErrNoRetry{Err: fmt.Errorf("error deserializing response: %w", err)}
-	}
-	// id
-	iface, err := jmespath.Search("data[0].id", data)
+// fetchKaggleLeaderboard requests the competition's leaderboard. It goes
+// through doRequest like any other kaggle.competition request, so it's subject
+// to the same rate limiting and may be skipped. It isn't archived; the archive
+// only holds responses that can be replayed through their kind's extractors.
+func (a *ActivityRequester) fetchKaggleLeaderboard(ctx context.Context, id string) ([]byte, error) {
+	r, err := newKaggleCompetitionLeaderboardRequest(id)
 	if err != nil {
-		return nil, ErrNoRetry{Err: fmt.Errorf("error extracting id: %w", err)}
+		return nil, err
 	}
-	if iface == nil {
-		return nil, ErrNoRetry{Err: fmt.Errorf("error extracting id; id is nil")}
+	r = r.WithContext(ctx)
+	r.Header.Set("Accept", "application/json")
+	if err = prepareKaggleRequest(a, r); err != nil {
+		return nil, err
 	}
-	id := iface.(string)
-
-	// view_count
-	iface, err = jmespath.Search("data[0].view_count", data)
+	res, err := doRequest(ctx, RequestKindKaggleCompetition, r)
 	if err != nil {
-		return nil, ErrNoRetry{Err: fmt.Errorf("error extracting view_count: %w", err)}
-	}
-	if iface == nil {
-		return nil, ErrNoRetry{Err: fmt.Errorf("error extracting view_count; view_count is nil")}
+		return nil, fmt.Errorf("error doing leaderboard request: %w", err)
 	}
-	vc := iface.(float64)
-
-	// upload the metrics to the server
-	payload := api.TwitchVideoMetricPayload{
-		ID:           id,
-		SetViewCount: true,
-		ViewCount:    int(math.Round(vc)),
+	if res.SkipReason != "" {
+		return nil, fmt.Errorf("leaderboard request skipped: %s", res.SkipReason)
 	}
-	b, err = json.Marshal(payload)
-	if err != nil {
-		return nil, ErrNoRetry{Err: fmt.Errorf("error serializing upload metadata: %w", err)}
+	if res.ResponseStatusCode != http.StatusOK {
+		return nil, fmt.Errorf("bad leaderboard response (%s): %d: %s", res.Failure, res.ResponseStatusCode, res.ResponseBody)
 	}
-	return uploadMetrics(l, "/twitch/video", b)
+	return res.ResponseBody, nil
 }
 
-// Handle RequestKindTwitchStream requests
-func (a *ActivityRequester) handleTwitchStreamMetrics(l log.Logger, status int, b []byte) (*api.DefaultJSONResponse, error) {
-	var data interface{}
-	if err := json.Unmarshal(b, &data); err != nil {
-		return nil, ErrNoRetry{Err: fmt.Errorf("error deserializing response: %w", err)}
-	}
-
-	// short circuit if nothing is returned; streamer is probably just offline
-	iface, err := jmespath.Search("data | length(@)", data)
-	if err != nil {
-		return nil, ErrNoRetry{Err: fmt.Errorf("error counting stream results: %w", err)}
-	}
-	if iface == nil {
-		return nil, ErrNoRetry{Err: fmt.Errorf("error counting stream results; count is nil")}
-	}
-	count := iface.(float64)
-	if count < 1 {
-		return &api.DefaultJSONResponse{Message: "ok"}, nil
-	}
-
-	// id
-	iface, err = jmespath.Search("data[0].user_login", data)
-	if err != nil {
-		return nil, ErrNoRetry{Err: fmt.Errorf("error extracting user_login: %w", err)}
+// extractKaggleCompetitionMetrics builds the upload from the competition
+// listing and the (optional) leaderboard. The leaderboard is sorted by rank, so
+// the first submission has the top score regardless of whether the metric is
+// minimized or maximized. Scores are strings; an empty leaderboard or score
+// just omits top-score.
+func extractKaggleCompetitionMetrics(list, leaderboard []byte) (*api.MetricSamplesPayload, error) {
+	var body []struct {
+		Ref       string `json:"ref"`
+		TeamCount int    `json:"teamCount"`
 	}
-	if iface == nil {
-		return nil, ErrNoRetry{Err: fmt.Errorf("error extracting user_login; user_login is nil")}
+	if err := json.Unmarshal(list, &body); err != nil {
+		return nil, fmt.Errorf("error deserializing response: %w", err)
 	}
-	user_login := iface.(string)
-
-	// view_count
-	iface, err = jmespath.Search("data[0].viewer_count", data)
-	if err != nil {
-		return nil, ErrNoRetry{Err: fmt.Errorf("error extracting viewer_count: %w", err)}
+	if len(body) == 0 || body[0].Ref == "" {
+		return nil, fmt.Errorf("error extracting id; no competitions in response")
 	}
-	if iface == nil {
-		return nil, ErrNoRetry{Err: fmt.Errorf("error extracting viewer_count; viewer_count is nil")}
+	payload := api.MetricSamplesPayload{
+		RequestKind: RequestKindKaggleCompetition,
+		ID:          kaggleCompetitionSlug(body[0].Ref),
+		Metrics:     map[string]float64{"team-count": float64(body[0].TeamCount)},
 	}
-	vc := iface.(float64)
-
-	// upload the metrics to the server
-	payload := api.TwitchStreamMetricPayload{
-		ID:           user_login,
-		SetViewCount: true,
-		ViewCount:    int(math.Round(vc)),
+	if leaderboard == nil {
+		return &payload, nil
 	}
-	b, err = json.Marshal(payload)
-	if err != nil {
-		return nil, ErrNoRetry{Err: fmt.Errorf("error serializing upload metadata: %w", err)}
-	}
-	return uploadMetrics(l, "/twitch/stream", b)
-}
-
-// Handle RequestKindTwitchUserLastDec requests
-func (a *ActivityRequester) handleTwitchUserPastDecMetrics(l log.Logger, status int, b []byte) (*api.DefaultJSONResponse, error) {
-	var body struct {
-		Data []struct {
-			UserID    string `json:"user_login"`
-			ViewCount int    `json:"view_count"`
-			Duration  string `json:"duration"`
-		}
+	var lb struct {
+		Submissions []struct {
+			Score string `json:"score"`
+		} `json:"submissions"`
 	}
-
-	if err := json.Unmarshal(b, &body); err != nil {
-		return nil, ErrNoRetry{Err: fmt.Errorf("error deserializing response: %w", err)}
+	if err := json.Unmarshal(leaderboard, &lb); err != nil {
+		return nil, fmt.Errorf("error deserializing leaderboard: %w", err)
 	}
-
-	views := []float64{}
-	durs := []float64{}
-
-	for _, v := range body.Data {
-		views = append(views, float64(v.ViewCount))
-		d, err := time.ParseDuration(v.Duration)
+	if len(lb.Submissions) > 0 && lb.Submissions[0].Score != "" {
+		score, err := strconv.ParseFloat(lb.Submissions[0].Score, 64)
 		if err != nil {
-			return nil, ErrNoRetry{Err: fmt.Errorf("error parsing video duration %s: %w", v.Duration, err)}
+			return nil, fmt.Errorf("error parsing top score %q: %w", lb.Submissions[0].Score, err)
 		}
-		durs = append(durs, float64(d/time.Second))
-	}
-
-	slices.Sort(views)
-	slices.Sort(durs)
-
-	// views
-	avc := stat.Mean(views, nil)
-	mvc := stat.Quantile(0.5, stat.LinInterp, views, nil)
-	svc := stat.StdDev(views, nil)
-
-	// durations
-	ad := stat.Mean(durs, nil)
-	md := stat.Quantile(0.5, stat.LinInterp, durs, nil)
-	sd := stat.StdDev(durs, nil)
-
-	// upload the metrics to the server
-	payload := api.TwitchUserPastDecMetricPayload{
-		ID:              body.Data[0].UserID,
-		SetAvgViewCount: true,
-		AvgViewCount:    float32(avc),
-		SetMedViewCount: true,
-		MedViewCount:    float32(mvc),
-		SetStdViewCount: true,
-		StdViewCount:    float32(svc),
-		SetAvgDuration:  true,
-		AvgDuration:     float32(ad),
-		SetMedDuration:  true,
-		MedDuration:     float32(md),
-		SetStdDuration:  true,
-		StdDuration:     float32(sd),
-	}
-	b, err := json.Marshal(payload)
-	if err != nil {
-		return nil, ErrNoRetry{Err: fmt.Errorf("error serializing upload metadata: %w", err)}
+		payload.Metrics["top-score"] = score
 	}
-	return uploadMetrics(l, "/twitch/user-past-dec", b)
+	return &payload, nil
 }
@@ -0,0 +1,213 @@
+package temporal
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/brojonat/kaggo/server/alerts"
+	"github.com/brojonat/kaggo/server/api"
+	"github.com/brojonat/kaggo/server/db/dbgen"
+	"github.com/brojonat/server-tools/stools"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// Direct ingestion. By default workers upload metrics to the kaggo server over
+// HTTP. Workers inside the trusted network can instead write the samples
+// straight to the database with a MetricWriter, which buffers them and bulk
+// loads each batch with COPY. Callers wait for the batch their samples are in
+// to be written, so an upload activity still only completes once its samples
+// are stored, and samples already recorded at the same time are still dropped,
+// so retries are still harmless. Only uploads to the generic metrics endpoint are written
+// directly; the kind specific endpoints do more than store samples, so those
+// still go over HTTP. Alert rules are evaluated by the writer's Alerter once a
+// batch is written, just as the server evaluates them on the samples it
+// ingests.
+
+const (
+	DefaultMetricWriterBatchSize     = 500
+	DefaultMetricWriterFlushInterval = time.Second
+	// upper bound on writing a batch, which may be shared by several callers
+	metricWriterTimeout = 30 * time.Second
+)
+
+// MetricWriter writes metric samples to the database in batches.
+type MetricWriter struct {
+	// Alerter, if set, evaluates alert rules on the samples once they're
+	// written.
+	Alerter *alerts.Alerter
+
+	q        *dbgen.Queries
+	size     int
+	interval time.Duration
+
+	mu      sync.Mutex
+	rows    []dbgen.CopyMetricSamplesParams
+	waiters []chan error
+	timer   *time.Timer
+	closed  bool
+	wg      sync.WaitGroup
+}
+
+// NewMetricWriter returns a writer that writes a batch once it has size
+// samples, or once the oldest sample in it has waited for interval.
+func NewMetricWriter(p *pgxpool.Pool, size int, interval time.Duration) *MetricWriter {
+	return &MetricWriter{q: dbgen.New(p), size: max(size, 1), interval: interval}
+}
+
+// Write buffers the samples and waits until they're written.
+func (w *MetricWriter) Write(ctx context.Context, rows []dbgen.CopyMetricSamplesParams) error {
+	if len(rows) == 0 {
+		return nil
+	}
+	done := make(chan error, 1)
+	w.mu.Lock()
+	if w.closed {
+		w.mu.Unlock()
+		return fmt.Errorf("metric writer is closed")
+	}
+	w.rows = append(w.rows, rows...)
+	w.waiters = append(w.waiters, done)
+	if len(w.rows) >= w.size {
+		w.flushLocked()
+	} else if w.timer == nil {
+		w.timer = time.AfterFunc(w.interval, w.Flush)
+	}
+	w.mu.Unlock()
+
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Flush starts writing whatever is buffered.
+func (w *MetricWriter) Flush() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.flushLocked()
+}
+
+// Close writes whatever is buffered and waits for all the writes to finish.
+func (w *MetricWriter) Close() {
+	w.mu.Lock()
+	w.closed = true
+	w.flushLocked()
+	w.mu.Unlock()
+	w.wg.Wait()
+}
+
+func (w *MetricWriter) flushLocked() {
+	if w.timer != nil {
+		w.timer.Stop()
+		w.timer = nil
+	}
+	if len(w.rows) == 0 {
+		return
+	}
+	rows, waiters := w.rows, w.waiters
+	w.rows, w.waiters = nil, nil
+	w.wg.Add(1)
+	go func() {
+		defer w.wg.Done()
+		inserted, err := w.write(rows)
+		for _, c := range waiters {
+			c <- err
+		}
+		w.evaluate(inserted)
+	}()
+}
+
+// write bulk loads the rows and returns the ones that were inserted. If any of
+// them were already written (i.e., an upload is being retried), the COPY
+// fails, and the rows are inserted one at a time instead, skipping the ones
+// that already exist.
+func (w *MetricWriter) write(rows []dbgen.CopyMetricSamplesParams) ([]dbgen.CopyMetricSamplesParams, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), metricWriterTimeout)
+	defer cancel()
+	_, err := w.q.CopyMetricSamples(ctx, rows)
+	if err == nil {
+		return rows, nil
+	}
+	if !stools.IsPGError(err, stools.PGErrorUniqueViolation) {
+		return nil, err
+	}
+	inserted := []dbgen.CopyMetricSamplesParams{}
+	for _, r := range rows {
+		n, err := w.q.InsertMetricSample(ctx, dbgen.InsertMetricSampleParams(r))
+		if err != nil {
+			return inserted, fmt.Errorf("error inserting %s.%s for %s: %w", r.RequestKind, r.Metric, r.ID, err)
+		}
+		if n > 0 {
+			inserted = append(inserted, r)
+		}
+	}
+	return inserted, nil
+}
+
+// evaluate runs the alert rules on the written rows, grouped the way they were
+// uploaded.
+func (w *MetricWriter) evaluate(rows []dbgen.CopyMetricSamplesParams) {
+	if w.Alerter == nil || len(rows) == 0 {
+		return
+	}
+	type upload struct {
+		rk, id string
+		ts     time.Time
+	}
+	uploads := map[upload]map[string]float64{}
+	for _, r := range rows {
+		k := upload{r.RequestKind, r.ID, r.Ts.Time}
+		if uploads[k] == nil {
+			uploads[k] = map[string]float64{}
+		}
+		uploads[k][r.Metric] = r.Value
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), metricWriterTimeout)
+	defer cancel()
+	for k, ms := range uploads {
+		w.Alerter.Evaluate(ctx, k.rk, k.id, ms, k.ts)
+	}
+}
+
+// writeMetrics writes a (stamped) api.MetricSamplesPayload with the
+// requester's MetricWriter, validating it the way the server would.
+func (a *ActivityRequester) writeMetrics(ctx context.Context, b []byte) (*api.DefaultJSONResponse, error) {
+	var p api.MetricSamplesPayload
+	if err := json.Unmarshal(b, &p); err != nil {
+		return nil, ErrNoRetry{Err: fmt.Errorf("error deserializing metrics: %w", err)}
+	}
+	if _, err := GetRequestKindSpec(p.RequestKind); err != nil {
+		return nil, ErrNoRetry{Err: err}
+	}
+	if p.ID == "" || len(p.Metrics) == 0 || p.Ts == nil {
+		return nil, ErrNoRetry{Err: fmt.Errorf("must supply id, metrics, and ts")}
+	}
+	ts, err := api.SampleTime(p.Ts)
+	if err != nil {
+		return nil, ErrNoRetry{Err: err}
+	}
+	rows := []dbgen.CopyMetricSamplesParams{}
+	for m, v := range p.Metrics {
+		if err := api.ValidateMetricName(m); err != nil {
+			return nil, ErrNoRetry{Err: err}
+		}
+		rows = append(rows, dbgen.CopyMetricSamplesParams{
+			RequestKind:    p.RequestKind,
+			ID:             p.ID,
+			Metric:         m,
+			Ts:             pgtype.Timestamptz{Time: ts, Valid: true},
+			Value:          v,
+			IdempotencyKey: pgtype.Text{String: p.IdempotencyKey, Valid: p.IdempotencyKey != ""},
+		})
+	}
+	if err := a.MetricWriter.Write(ctx, rows); err != nil {
+		return nil, fmt.Errorf("error writing metrics: %w", err)
+	}
+	return &api.DefaultJSONResponse{Message: "ok"}, nil
+}
@@ -0,0 +1,128 @@
+package temporal
+
+import (
+	"encoding/json"
+	"maps"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/brojonat/kaggo/server/api"
+)
+
+func readFixture(t *testing.T, name string) []byte {
+	t.Helper()
+	b, err := os.ReadFile(filepath.Join("testdata", name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return b
+}
+
+// Runs each Kaggle kind's metrics extractor on its fixture.
+func TestKaggleMetricsExtractors(t *testing.T) {
+	cases := []struct {
+		kind    string
+		fixture string
+		// only kaggle.competition follows up with the leaderboard; this is
+		// a minimal body with the fields the extractor reads, not a recording
+		leaderboard string
+		id          string
+		metrics     map[string]float64
+	}{
+		{
+			kind:    RequestKindKaggleNotebook,
+			fixture: "kaggle.notebook.json",
+			id:      "alexisbcook/titanic-tutorial",
+			metrics: map[string]float64{"votes": 19562},
+		},
+		{
+			kind:    RequestKindKaggleDataset,
+			fixture: "kaggle.dataset.json",
+			id:      "mlg-ulb/creditcardfraud",
+			metrics: map[string]float64{"views": 6531408, "votes": 12241, "downloads": 815524},
+		},
+		{
+			kind:    RequestKindKaggleCompetition,
+			fixture: "kaggle.competition.json",
+			id:      "titanic",
+			metrics: map[string]float64{"team-count": 15923},
+		},
+		{
+			kind:        RequestKindKaggleCompetition,
+			fixture:     "kaggle.competition.json",
+			leaderboard: `{"submissions": [{"teamId": 1, "score": "1.00000"}, {"teamId": 2, "score": "0.99760"}]}`,
+			id:          "titanic",
+			metrics:     map[string]float64{"team-count": 15923, "top-score": 1},
+		},
+	}
+	for _, c := range cases {
+		name := c.kind
+		if c.leaderboard != "" {
+			name += " with leaderboard"
+		}
+		t.Run(name, func(t *testing.T) {
+			b := readFixture(t, c.fixture)
+			var p *api.MetricSamplesPayload
+			if c.kind == RequestKindKaggleCompetition {
+				var lb []byte
+				if c.leaderboard != "" {
+					lb = []byte(c.leaderboard)
+				}
+				var err error
+				if p, err = extractKaggleCompetitionMetrics(b, lb); err != nil {
+					t.Fatal(err)
+				}
+			} else {
+				s := GetExtractorSpec(c.kind)
+				if s == nil {
+					t.Fatalf("no extractor spec for %s", c.kind)
+				}
+				pb, err := s.ExtractMetrics(b)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if err = json.Unmarshal(pb, &p); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if p.RequestKind != c.kind || p.ID != c.id {
+				t.Errorf("expected %s %s, got %s %s", c.kind, c.id, p.RequestKind, p.ID)
+			}
+			if !maps.Equal(p.Metrics, c.metrics) {
+				t.Errorf("expected metrics %v, got %v", c.metrics, p.Metrics)
+			}
+		})
+	}
+}
+
+// Runs each Kaggle kind's metadata extractor on its fixture.
+func TestKaggleMetadataExtractors(t *testing.T) {
+	cases := []struct {
+		kind    string
+		fixture string
+		id      string
+	}{
+		{RequestKindKaggleNotebook, "kaggle.notebook.json", "alexisbcook/titanic-tutorial"},
+		{RequestKindKaggleDataset, "kaggle.dataset.json", "mlg-ulb/creditcardfraud"},
+		{RequestKindKaggleCompetition, "kaggle.competition.json", "titanic"},
+	}
+	for _, c := range cases {
+		t.Run(c.kind, func(t *testing.T) {
+			s := GetExtractorSpec(c.kind)
+			if s == nil {
+				t.Fatalf("no extractor spec for %s", c.kind)
+			}
+			p, err := s.ExtractMetadata(readFixture(t, c.fixture))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if p.RequestKind != c.kind || p.ID != c.id {
+				t.Errorf("expected %s %s, got %s %s", c.kind, c.id, p.RequestKind, p.ID)
+			}
+			if p.Data.HumanLabel == "" || p.Data.Link == "" {
+				t.Errorf("expected a human label and link, got %+v", p.Data)
+			}
+		})
+	}
+}
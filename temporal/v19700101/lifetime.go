@@ -0,0 +1,88 @@
+package temporal
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/brojonat/kaggo/server/api"
+)
+
+// End-of-life review. Kinds with a DefaultLifetime get an EndAt when their
+// schedule is created, but some content is still growing when it gets there
+// (e.g., a video that goes viral in its fourth week). A day before a
+// schedule's EndAt, its expiry watcher (WatchScheduleExpiryWF) asks the server
+// to review it. The server looks at how much the kind's cadence metric grew
+// over the last few days; if it's still growing by at least eolMinGrowth a
+// day, the schedule is extended by another DefaultLifetime at a reduced
+// cadence (at least twice the current interval). Otherwise the schedule is
+// left to expire, and the entity is marked as ended once it does. Schedules
+// aren't extended past eolMaxLifetime.
+
+const (
+	// EndOfLifeReviewLead is how long before EndAt the schedule is reviewed
+	EndOfLifeReviewLead = 24 * time.Hour
+	// EndOfLifeWindow is how far back growth is measured
+	EndOfLifeWindow = 3 * 24 * time.Hour
+	// relative growth per day that keeps a schedule alive
+	eolMinGrowth   = 0.01
+	eolMaxLifetime = 26 * 7 * 24 * time.Hour
+)
+
+// EndOfLifeDecision is the outcome of an end-of-life review.
+type EndOfLifeDecision struct {
+	Extend bool
+	// By is how much to extend the EndAt by and Every is the (reduced)
+	// polling interval for the extension.
+	By     time.Duration
+	Every  time.Duration
+	Reason string
+}
+
+// ReviewEndOfLife decides whether a schedule nearing its EndAt should be
+// extended given the kind's cadence metric samples (oldest first) over the
+// last EndOfLifeWindow, the schedule's current interval (0 if it isn't a
+// single interval), and how long the schedule has run for by its EndAt.
+func (s RequestKindSpec) ReviewEndOfLife(samples []CadenceSample, current, lifetime time.Duration) EndOfLifeDecision {
+	c := s.Cadence
+	if c == nil || s.DefaultLifetime == 0 {
+		return EndOfLifeDecision{Reason: fmt.Sprintf("%s schedules aren't extended", s.Kind)}
+	}
+	if lifetime+s.DefaultLifetime > eolMaxLifetime {
+		return EndOfLifeDecision{Reason: fmt.Sprintf("end-of-life review: tracked for %s already; expiring", days(lifetime))}
+	}
+	rate, elapsed, ok := relativeRate(samples)
+	if !ok || elapsed < EndOfLifeWindow/2 {
+		return EndOfLifeDecision{Reason: fmt.Sprintf("end-of-life review: not enough recent %s samples; expiring", c.Metric)}
+	}
+	daily := 24 * rate
+	if daily < eolMinGrowth {
+		return EndOfLifeDecision{Reason: fmt.Sprintf(
+			"end-of-life review: %s grew %.2f%%/day over the last %s; expiring",
+			c.Metric, 100*daily, elapsed.Round(time.Hour))}
+	}
+	every, _, _ := c.Interval(samples)
+	every = c.snap(max(every, 2*current))
+	return EndOfLifeDecision{
+		Extend: true,
+		By:     s.DefaultLifetime,
+		Every:  every,
+		Reason: fmt.Sprintf(
+			"end-of-life review: %s still growing %.2f%%/day over the last %s; extended by %s, polling every %s",
+			c.Metric, 100*daily, elapsed.Round(time.Hour), days(s.DefaultLifetime), every),
+	}
+}
+
+func days(d time.Duration) string {
+	return fmt.Sprintf("%.0f days", d.Hours()/24)
+}
+
+// ReviewScheduleEndOfLife asks the kaggo server to review a schedule that's
+// nearing its EndAt. The response holds the (possibly extended) EndAt.
+func (a *ActivityWebhooks) ReviewScheduleEndOfLife(ctx context.Context, p api.ScheduleExpiryPayload) (*api.ScheduleExpiryResponse, error) {
+	var res api.ScheduleExpiryResponse
+	if err := postToKaggo(ctx, "/schedule/end-of-life", p, &res); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
@@ -0,0 +1,97 @@
+package temporal
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/brojonat/kaggo/server/schedspec"
+	"go.temporal.io/sdk/client"
+)
+
+// Schedule presets and validation. Clients name a preset instead of sending a
+// raw client.ScheduleSpec; presets resolve per kind: "default" is the kind's
+// DefaultSchedule (with its DefaultLifetime), "aggressive" polls at the kind's
+// MinInterval for the same lifetime, and "archival" polls daily with no end.
+// Specs that are sent raw are validated: they have to fire (at least one
+// calendar or interval), can't fire more often than the kind's MinInterval,
+// and can't end in the past.
+
+const (
+	SchedulePresetAggressive = "aggressive"
+	SchedulePresetDefault    = "default"
+	SchedulePresetArchival   = "archival"
+)
+
+const archivalInterval = 24 * time.Hour
+
+// GetSchedulePreset returns the named preset's spec for the supplied kind.
+func GetSchedulePreset(rk, preset string) (client.ScheduleSpec, error) {
+	rks, err := GetRequestKindSpec(rk)
+	if err != nil {
+		return client.ScheduleSpec{}, err
+	}
+	var s client.ScheduleSpec
+	switch preset {
+	case SchedulePresetDefault:
+		s = rks.DefaultSchedule()
+	case SchedulePresetAggressive:
+		s = scheduleEvery(rks.MinInterval)
+	case SchedulePresetArchival:
+		return scheduleEvery(max(archivalInterval, rks.MinInterval)), nil
+	default:
+		return client.ScheduleSpec{}, fmt.Errorf("unsupported schedule preset: %s", preset)
+	}
+	if rks.DefaultLifetime > 0 {
+		s.EndAt = time.Now().Add(rks.DefaultLifetime)
+	}
+	return s, nil
+}
+
+func scheduleEvery(d time.Duration) client.ScheduleSpec {
+	return client.ScheduleSpec{
+		Intervals: []client.ScheduleIntervalSpec{{Every: d}},
+		Jitter:    d,
+	}
+}
+
+// ValidateScheduleSpec checks that the spec is usable for the supplied kind.
+func ValidateScheduleSpec(rk string, s client.ScheduleSpec) error {
+	rks, err := GetRequestKindSpec(rk)
+	if err != nil {
+		return err
+	}
+	return validateScheduleSpec(rks, s)
+}
+
+func validateScheduleSpec(rks RequestKindSpec, s client.ScheduleSpec) error {
+	if len(s.CronExpressions) > 0 {
+		return fmt.Errorf("cron expressions aren't supported; use calendars or intervals")
+	}
+	sp, err := schedspec.New(s)
+	if err != nil {
+		return err
+	}
+	if len(s.Calendars) == 0 && len(s.Intervals) == 0 {
+		return fmt.Errorf("schedule must have at least one calendar or interval")
+	}
+	for _, i := range s.Intervals {
+		if i.Every < rks.MinInterval {
+			return fmt.Errorf("interval %s is shorter than the minimum for %s (%s)", i.Every, rks.Kind, rks.MinInterval)
+		}
+	}
+	if gap := sp.MinGap(); gap < rks.MinInterval {
+		return fmt.Errorf("calendars fire every %s, more often than the minimum for %s (%s)", gap, rks.Kind, rks.MinInterval)
+	}
+	if s.Jitter < 0 {
+		return fmt.Errorf("jitter can't be negative")
+	}
+	if !s.EndAt.IsZero() {
+		if !s.EndAt.After(time.Now()) {
+			return fmt.Errorf("end_at must be in the future")
+		}
+		if !s.StartAt.IsZero() && !s.StartAt.Before(s.EndAt) {
+			return fmt.Errorf("start_at must be before end_at")
+		}
+	}
+	return nil
+}
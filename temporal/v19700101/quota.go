@@ -0,0 +1,133 @@
+package temporal
+
+import (
+	"context"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"sync/atomic"
+
+	"github.com/brojonat/kaggo/server/api"
+	"go.temporal.io/sdk/activity"
+)
+
+// Quota ledger client. YouTube gives each API key a daily budget of units and
+// charges every call some number of them. Before such a call is sent, it's
+// charged to the ledger on the kaggo server, which picks the key from the
+// configured pool with the most budget left (the key on the request is
+// replaced accordingly) and reports the budget remaining for each key. If the
+// budget is running short, requests for low priority IDs are skipped (see
+// server/handlers_quota.go). Like the rate limit governor, the ledger is
+// advisory: if the server can't be reached, the keys are used round robin.
+
+// QuotaSpec describes a provider's per key unit quota.
+type QuotaSpec struct {
+	// Keys returns the pool of API keys.
+	Keys func() []string
+	// Param is the query parameter that carries the key.
+	Param string
+	// IDParam is the query parameter that carries the ID(s) being requested;
+	// the ledger prioritizes requests by ID.
+	IDParam string
+	// Budget returns the units each key may spend per day.
+	Budget func() int64
+	// Cost returns the units the request will be charged.
+	Cost func(r *http.Request) int64
+}
+
+var quotaYouTube = &QuotaSpec{
+	Keys:    youtubeAPIKeys,
+	Param:   "key",
+	IDParam: "id",
+	Budget:  youtubeQuotaBudget,
+	Cost:    youtubeQuotaCost,
+}
+
+// youtubeAPIKeys returns the pool of keys in YOUTUBE_API_KEYS (comma
+// separated), falling back to the single YOUTUBE_API_KEY.
+func youtubeAPIKeys() []string {
+	keys := []string{}
+	for _, k := range strings.Split(os.Getenv("YOUTUBE_API_KEYS"), ",") {
+		if k = strings.TrimSpace(k); k != "" {
+			keys = append(keys, k)
+		}
+	}
+	if len(keys) == 0 && os.Getenv("YOUTUBE_API_KEY") != "" {
+		keys = append(keys, os.Getenv("YOUTUBE_API_KEY"))
+	}
+	return keys
+}
+
+// youtubeQuotaBudget returns YOUTUBE_QUOTA_UNITS, or the default daily quota
+// of 10,000 units.
+func youtubeQuotaBudget() int64 {
+	if n, err := strconv.ParseInt(os.Getenv("YOUTUBE_QUOTA_UNITS"), 10, 64); err == nil && n > 0 {
+		return n
+	}
+	return 10000
+}
+
+// youtubeQuotaCosts are the units charged per call (not per ID), by endpoint.
+// https://developers.google.com/youtube/v3/determine_quota_cost
+var youtubeQuotaCosts = map[string]int64{
+	"/youtube/v3/channels":      1,
+	"/youtube/v3/playlistItems": 1,
+	"/youtube/v3/search":        100,
+	"/youtube/v3/videos":        1,
+}
+
+func youtubeQuotaCost(r *http.Request) int64 {
+	if c, ok := youtubeQuotaCosts[r.URL.Path]; ok {
+		return c
+	}
+	return 1
+}
+
+// used to rotate keys when the ledger is unavailable
+var quotaFallbackCounter atomic.Uint64
+
+// chargeQuota charges the request to the ledger and sets the key it was
+// charged to on the request. If the request should be skipped, the reason is
+// returned.
+func chargeQuota(ctx context.Context, rk string, qs *QuotaSpec, r *http.Request) (string, error) {
+	keys := qs.Keys()
+	if len(keys) == 0 {
+		return "", nil
+	}
+	l := activity.GetLogger(ctx)
+	mh := activity.GetMetricsHandler(ctx)
+
+	digests := map[string]string{}
+	body := api.QuotaChargePayload{Cost: qs.Cost(r), Budget: qs.Budget(), RequestKind: rk}
+	for _, k := range keys {
+		d := credentialKey("youtube", k)
+		digests[d] = k
+		body.Keys = append(body.Keys, d)
+	}
+	for _, v := range r.URL.Query()[qs.IDParam] {
+		body.IDs = append(body.IDs, strings.Split(v, ",")...)
+	}
+
+	var res api.QuotaChargeResponse
+	key := keys[quotaFallbackCounter.Add(1)%uint64(len(keys))]
+	if err := postToKaggo(ctx, "/quota/charge", body, &res); err != nil {
+		l.Warn("error charging quota; sending request anyway", "request_kind", rk, "error", err.Error())
+	} else {
+		for d, rem := range res.Remaining {
+			mh.WithTags(map[string]string{"quota_key": d}).Gauge(MetricQuotaRemaining).Update(float64(rem))
+		}
+		if !res.Allowed {
+			l.Warn("skipping request over quota", "request_kind", rk, "reason", res.Reason)
+			mh.WithTags(map[string]string{"request_kind": rk}).Counter(MetricQuotaSkipped).Inc(1)
+			return res.Reason, nil
+		}
+		if k, ok := digests[res.Key]; ok {
+			key = k
+		}
+	}
+	q := r.URL.Query()
+	q.Set(qs.Param, key)
+	r.URL.RawQuery = q.Encode()
+	return "", nil
+}
@@ -0,0 +1,161 @@
+package temporal
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/brojonat/kaggo/server/api"
+	"go.temporal.io/sdk/activity"
+)
+
+// Rate limit governor client. Every worker shares the same provider
+// credentials, so before a request to a rate limited provider is sent, a
+// token is acquired from the credential's bucket on the kaggo server, and the
+// rate limit headers on the response are reported back to refill it. When the
+// provider is close to its limit the server tells us to wait a bit or to skip
+// the request entirely; skipped requests are returned as a DoRequestActResult
+// with a SkipReason rather than an error. The governor is advisory: if the
+// server can't be reached, the request goes ahead.
+
+// rateLimitMaxWait is how long a request will wait for a token. It needs to
+// leave room for the request itself within the DoRequest timeout.
+const rateLimitMaxWait = 10 * time.Second
+
+// RateLimitSpec describes the provider rate limit a kind's requests count
+// against.
+type RateLimitSpec struct {
+	// Key identifies the bucket; it's derived from the credential the
+	// requests are made with.
+	Key func() string
+	// Window is the period over which the provider's limit applies.
+	Window time.Duration
+	// Parse extracts the limit, the remaining requests, and the time of the
+	// next reset from the response headers.
+	Parse func(h http.Header, now time.Time) (limit, remaining float64, reset time.Time, ok bool)
+}
+
+var (
+	rateLimitReddit = &RateLimitSpec{
+		Key:    func() string { return credentialKey("reddit", os.Getenv("REDDIT_CLIENT_ID")) },
+		Window: 10 * time.Minute,
+		Parse:  parseRedditRateLimit,
+	}
+	rateLimitRedditListener = &RateLimitSpec{
+		Key:    func() string { return credentialKey("reddit", os.Getenv("REDDIT_LISTENER_CLIENT_ID")) },
+		Window: 10 * time.Minute,
+		Parse:  parseRedditRateLimit,
+	}
+	rateLimitTwitch = &RateLimitSpec{
+		Key:    func() string { return credentialKey("twitch", os.Getenv("TWITCH_CLIENT_ID")) },
+		Window: time.Minute,
+		Parse:  parseTwitchRateLimit,
+	}
+)
+
+// credentialKey returns the key for a provider credential. Only a digest
+// of the credential is used so the key can be shown to anyone.
+func credentialKey(provider, credential string) string {
+	h := sha256.Sum256([]byte(credential))
+	return provider + ":" + hex.EncodeToString(h[:6])
+}
+
+// Reddit reports the requests used and remaining in the current window, and
+// the seconds until the window resets.
+func parseRedditRateLimit(h http.Header, now time.Time) (float64, float64, time.Time, bool) {
+	used, err := strconv.ParseFloat(h.Get("X-Ratelimit-Used"), 64)
+	if err != nil {
+		return 0, 0, time.Time{}, false
+	}
+	remaining, err := strconv.ParseFloat(h.Get("X-Ratelimit-Remaining"), 64)
+	if err != nil {
+		return 0, 0, time.Time{}, false
+	}
+	reset, err := strconv.ParseFloat(h.Get("X-Ratelimit-Reset"), 64)
+	if err != nil {
+		return 0, 0, time.Time{}, false
+	}
+	return used + remaining, remaining, now.Add(time.Duration(reset * float64(time.Second))), true
+}
+
+// Twitch reports the bucket size, the tokens remaining, and the unix time at
+// which the bucket will be full again.
+// https://dev.twitch.tv/docs/api/guide/#how-it-works
+func parseTwitchRateLimit(h http.Header, now time.Time) (float64, float64, time.Time, bool) {
+	limit, err := strconv.ParseFloat(h.Get("Ratelimit-Limit"), 64)
+	if err != nil {
+		return 0, 0, time.Time{}, false
+	}
+	remaining, err := strconv.ParseFloat(h.Get("Ratelimit-Remaining"), 64)
+	if err != nil {
+		return 0, 0, time.Time{}, false
+	}
+	reset, err := strconv.ParseInt(h.Get("Ratelimit-Reset"), 10, 64)
+	if err != nil {
+		return 0, 0, time.Time{}, false
+	}
+	return limit, remaining, time.Unix(reset, 0), true
+}
+
+// awaitRateLimit acquires a token for the request, waiting if the server says
+// to. If the request should be skipped, the reason is returned.
+func awaitRateLimit(ctx context.Context, rk string, rl *RateLimitSpec) (string, error) {
+	l := activity.GetLogger(ctx)
+	mh := activity.GetMetricsHandler(ctx).WithTags(map[string]string{"request_kind": rk})
+	var res api.RateLimitAcquireResponse
+	err := postToKaggo(ctx, "/rate-limits/acquire", api.RateLimitAcquirePayload{
+		Key:            rl.Key(),
+		RequestKind:    rk,
+		MaxWaitSeconds: rateLimitMaxWait.Seconds(),
+	}, &res)
+	if err != nil {
+		l.Warn("error acquiring rate limit token; sending request anyway", "request_kind", rk, "error", err.Error())
+		return "", nil
+	}
+	if !res.Allowed {
+		l.Warn("skipping rate limited request", "reason", res.Reason)
+		mh.Counter(MetricRateLimitSkipped).Inc(1)
+		return res.Reason, nil
+	}
+	if res.WaitSeconds <= 0 {
+		return "", nil
+	}
+	l.Info("delaying rate limited request", "reason", res.Reason)
+	mh.Counter(MetricRateLimitDelayed).Inc(1)
+	t := time.NewTimer(time.Duration(res.WaitSeconds * float64(time.Second)))
+	defer t.Stop()
+	select {
+	case <-t.C:
+		return "", nil
+	case <-ctx.Done():
+		return "", ctx.Err()
+	}
+}
+
+// observeRateLimit reports the rate limit headers on the response. A 429 means
+// there's nothing left, whatever the headers say.
+func observeRateLimit(ctx context.Context, rl *RateLimitSpec, status int, h http.Header) {
+	now := time.Now()
+	limit, remaining, reset, ok := rl.Parse(h, now)
+	if !ok {
+		return
+	}
+	if status == http.StatusTooManyRequests {
+		remaining = 0
+	}
+	var res api.DefaultJSONResponse
+	err := postToKaggo(ctx, "/rate-limits/observe", api.RateLimitObservation{
+		Key:           rl.Key(),
+		Limit:         limit,
+		Remaining:     remaining,
+		Reset:         reset,
+		WindowSeconds: rl.Window.Seconds(),
+	}, &res)
+	if err != nil {
+		activity.GetLogger(ctx).Warn("error reporting rate limit", "error", err.Error())
+	}
+}
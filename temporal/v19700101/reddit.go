@@ -1,73 +1,32 @@
 package temporal
 
 import (
-	"encoding/json"
-	"fmt"
-	"io"
 	"net/http"
 	"net/url"
 	"os"
 	"strings"
-	"time"
 )
 
-func (a *ActivityRequester) ensureValidRedditToken(minDur time.Duration) error {
-	// reddit@reddit-VirtualBox:~$ curl -X POST -d 'grant_type=password&username=reddit_bot&password=snoo' --user 'dummy-cid-stuff:dummy-secret-stuff' https://www.reddit.com/api/v1/access_token
-	// {
-	// 	"access_token": "some.jwt.thing",
-	// 	"expires_in": 3600,
-	// 	"scope": "*",
-	// 	"token_type": "bearer"
-	// }
-
-	// short circuit early if the token doesn't need to be refreshed
-	if time.Until(a.RedditAuthTokenExp) > minDur {
-		return nil
-	}
+// Reddit uses the password grant, so tokens are issued per user (and app).
+// The pollers and the monitors use separate accounts, each configured with
+// its own set of envs (REDDIT_* and REDDIT_LISTENER_*).
+var (
+	oauthReddit         = newRedditOAuthSource("REDDIT")
+	oauthRedditListener = newRedditOAuthSource("REDDIT_LISTENER")
+)
 
-	// otherwise hit the reddit API for a new token
-	formData := url.Values{
-		"grant_type": {"password"},
-		"username":   {os.Getenv("REDDIT_USERNAME")},
-		"password":   {os.Getenv("REDDIT_PASSWORD")},
-	}
-	r, err := http.NewRequest(http.MethodPost, "https://www.reddit.com/api/v1/access_token", strings.NewReader(formData.Encode()))
-	if err != nil {
-		return err
-	}
-	r.SetBasicAuth(os.Getenv("REDDIT_CLIENT_ID"), os.Getenv("REDDIT_CLIENT_SECRET"))
-	r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
-	r.Header.Add("User-Agent", os.Getenv("REDDIT_USER_AGENT"))
-	resp, err := http.DefaultClient.Do(r)
-	if err != nil {
-		return err
-	}
-	defer resp.Body.Close()
-	var body struct {
-		AccessToken string `json:"access_token"`
-		ExpiresIn   int    `json:"expires_in"`
-		Scope       string `json:"scope"`
-		TokenType   string `json:"token_type"`
+func newRedditOAuthSource(envPrefix string) *OAuthSource {
+	return &OAuthSource{
+		Key: func() string {
+			return credentialKey("reddit", os.Getenv(envPrefix+"_CLIENT_ID")+":"+os.Getenv(envPrefix+"_USERNAME"))
+		},
+		NewTokenRequest: func() (*http.Request, error) {
+			return newRedditTokenRequest(envPrefix)
+		},
 	}
-	b, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return err
-	}
-	if resp.StatusCode != 200 {
-		return fmt.Errorf("bad response %d code for getting reddit auth token: %s", resp.StatusCode, string(b))
-	}
-	err = json.Unmarshal(b, &body)
-	if err != nil {
-		return err
-	}
-
-	a.RedditAuthToken = body.AccessToken
-	dur := time.Duration(body.ExpiresIn * int(time.Second))
-	a.RedditAuthTokenExp = time.Now().Add(dur)
-	return nil
 }
 
-func (a *ActivityRequester) ensureValidRedditListenerToken(minDur time.Duration) error {
+func newRedditTokenRequest(envPrefix string) (*http.Request, error) {
 	// reddit@reddit-VirtualBox:~$ curl -X POST -d 'grant_type=password&username=reddit_bot&password=snoo' --user 'dummy-cid-stuff:dummy-secret-stuff' https://www.reddit.com/api/v1/access_token
 	// {
 	// 	"access_token": "some.jwt.thing",
@@ -75,50 +34,17 @@ func (a *ActivityRequester) ensureValidRedditListenerToken(minDur time.Duration)
 	// 	"scope": "*",
 	// 	"token_type": "bearer"
 	// }
-
-	// short circuit early if the token doesn't need to be refreshed
-	if time.Until(a.RedditListenerAuthTokenExp) > minDur {
-		return nil
-	}
-
-	// otherwise hit the reddit API for a new token
 	formData := url.Values{
 		"grant_type": {"password"},
-		"username":   {os.Getenv("REDDIT_LISTENER_USERNAME")},
-		"password":   {os.Getenv("REDDIT_LISTENER_PASSWORD")},
+		"username":   {os.Getenv(envPrefix + "_USERNAME")},
+		"password":   {os.Getenv(envPrefix + "_PASSWORD")},
 	}
 	r, err := http.NewRequest(http.MethodPost, "https://www.reddit.com/api/v1/access_token", strings.NewReader(formData.Encode()))
 	if err != nil {
-		return err
+		return nil, err
 	}
-	r.SetBasicAuth(os.Getenv("REDDIT_LISTENER_CLIENT_ID"), os.Getenv("REDDIT_LISTENER_CLIENT_SECRET"))
+	r.SetBasicAuth(os.Getenv(envPrefix+"_CLIENT_ID"), os.Getenv(envPrefix+"_CLIENT_SECRET"))
 	r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
-	r.Header.Add("User-Agent", os.Getenv("REDDIT_LISTENER_USER_AGENT"))
-	resp, err := http.DefaultClient.Do(r)
-	if err != nil {
-		return err
-	}
-	defer resp.Body.Close()
-	var body struct {
-		AccessToken string `json:"access_token"`
-		ExpiresIn   int    `json:"expires_in"`
-		Scope       string `json:"scope"`
-		TokenType   string `json:"token_type"`
-	}
-	b, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return err
-	}
-	if resp.StatusCode != 200 {
-		return fmt.Errorf("bad response %d code for getting reddit auth token: %s", resp.StatusCode, string(b))
-	}
-	err = json.Unmarshal(b, &body)
-	if err != nil {
-		return err
-	}
-
-	a.RedditListenerAuthToken = body.AccessToken
-	dur := time.Duration(body.ExpiresIn * int(time.Second))
-	a.RedditListenerAuthTokenExp = time.Now().Add(dur)
-	return nil
+	r.Header.Add("User-Agent", os.Getenv(envPrefix+"_USER_AGENT"))
+	return r, nil
 }
@@ -0,0 +1,473 @@
+package temporal
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/brojonat/kaggo/server/api"
+	"github.com/brojonat/kaggo/server/db/dbgen"
+	"go.temporal.io/sdk/client"
+	"go.temporal.io/sdk/log"
+)
+
+var ErrUnsupportedRequestKind = errors.New("unsupported request kind")
+
+// RequestBuilder returns a PROTOTYPE request for the supplied identifier. See
+// the note in requests.go about what is and isn't allowed in these.
+type RequestBuilder func(q *dbgen.Queries, id string) (*http.Request, error)
+
+// RequestPreparer applies the finishing touches (auth tokens, api keys, etc.)
+// to a deserialized request immediately before it is sent. The request
+// carries the activity's context.
+type RequestPreparer func(a *ActivityRequester, r *http.Request) error
+
+// ResponseHandler extracts data from a response body and uploads it to the
+// kaggo server. The context is the activity's; uploads use it to stamp the
+// samples (see uploadMetrics).
+type ResponseHandler func(a *ActivityRequester, ctx context.Context, l log.Logger, status int, b []byte) (*api.DefaultJSONResponse, error)
+
+// WorkerMetricSetter sets worker prom metrics from the response headers.
+type WorkerMetricSetter func(a *ActivityRequester, l log.Logger, mh client.MetricsHandler, h http.Header)
+
+// RequestKindSpec declares everything the application needs to know about a
+// RequestKind. Adding a new RequestKind should be a matter of adding an entry
+// to registeredRequestKindSpecs below (plus the server side storage); nothing else in
+// this package should switch over RequestKind.
+type RequestKindSpec struct {
+	Kind string
+	// NewRequest builds the polling request.
+	NewRequest RequestBuilder
+	// NewMetadataRequest builds the metadata request. If nil, NewRequest is
+	// used for both.
+	NewMetadataRequest RequestBuilder
+	Prepare            RequestPreparer
+	// HandleMetadata and HandleMetrics are only needed for kinds that can't
+	// be described by an extractor spec (see extractors.go). If an extractor
+	// spec is loaded for the kind, it takes precedence.
+	HandleMetadata ResponseHandler
+	HandleMetrics  ResponseHandler
+	// SetWorkerMetrics is optional; most kinds don't report anything useful
+	// in their response headers.
+	SetWorkerMetrics WorkerMetricSetter
+	// DefaultSchedule returns the base schedule for the kind; DefaultLifetime
+	// is used to set the EndAt. A zero DefaultLifetime means the schedule
+	// runs indefinitely.
+	DefaultSchedule func() client.ScheduleSpec
+	DefaultLifetime time.Duration
+	// MinInterval is the shortest interval a schedule for the kind may poll
+	// at; it protects the provider's rate limit (see presets.go).
+	MinInterval time.Duration
+	// RateLimit is optional; it's set for kinds whose provider reports its
+	// rate limit in the response headers (see ratelimit.go).
+	RateLimit *RateLimitSpec
+	// Quota is optional; it's set for kinds whose provider charges each call
+	// against a per key budget (see quota.go).
+	Quota *QuotaSpec
+	// Batch is optional; it's set for kinds whose API can fetch many IDs in a
+	// single request (see batch.go).
+	Batch *BatchSpec
+	// ClassifyFailure is optional; it's set for kinds whose provider's status
+	// codes don't mean what they usually do (see failures.go).
+	ClassifyFailure FailureClassifier
+	// Cadence is optional; it's set for kinds whose polling interval adapts
+	// to how quickly their metrics change (see cadence.go).
+	Cadence *CadenceSpec
+	// Children is optional; it's set for kinds whose entities own entities of
+	// another kind (e.g., a channel's videos).
+	Children *ChildrenSpec
+}
+
+// ChildrenSpec describes the children of a kind: their Kind, and the field of
+// their metadata that holds the parent's ID.
+type ChildrenSpec struct {
+	Kind        string
+	ParentField string
+}
+
+const (
+	lifetimeShort        = 7 * 24 * time.Hour
+	lifetimeIntermediate = 4 * 7 * 24 * time.Hour
+)
+
+// requestKindSpecs is the registry. Order matters only insofar as it's the
+// order returned by GetSupportedRequestKinds. It's populated in init because
+// some of the handlers refer back to the registry (e.g., monitors look up the
+// default schedule of the kinds they create).
+var (
+	requestKindSpecs    []RequestKindSpec
+	requestKindRegistry map[string]RequestKindSpec
+)
+
+func init() {
+	requestKindSpecs = registeredRequestKindSpecs()
+	requestKindRegistry = make(map[string]RequestKindSpec, len(requestKindSpecs))
+	for _, s := range requestKindSpecs {
+		requestKindRegistry[s.Kind] = s
+	}
+}
+
+func registeredRequestKindSpecs() []RequestKindSpec {
+	return []RequestKindSpec{
+		{
+			Kind:            RequestKindInternalRandom,
+			NewRequest:      newInternalRandomRequest,
+			Prepare:         prepareInternalRequest,
+			DefaultSchedule: scheduleEvery30Seconds,
+			MinInterval:     30 * time.Second,
+		},
+		{
+			Kind:            RequestKindKaggleNotebook,
+			NewRequest:      newKaggleNotebookRequest,
+			Prepare:         prepareKaggleRequest,
+			DefaultSchedule: scheduleEvery15Minutes,
+			MinInterval:     5 * time.Minute,
+		},
+		{
+			Kind:            RequestKindKaggleDataset,
+			NewRequest:      newKaggleDatasetRequest,
+			Prepare:         prepareKaggleRequest,
+			DefaultSchedule: scheduleEvery15Minutes,
+			MinInterval:     5 * time.Minute,
+		},
+		{
+			Kind:            RequestKindKaggleCompetition,
+			NewRequest:      newKaggleCompetitionRequest,
+			Prepare:         prepareKaggleRequest,
+			HandleMetrics:   (*ActivityRequester).handleKaggleCompetitionMetrics,
+			DefaultSchedule: scheduleHourly,
+			MinInterval:     15 * time.Minute,
+		},
+		{
+			Kind:            RequestKindYouTubeVideo,
+			NewRequest:      newYouTubeVideoRequest,
+			Prepare:         prepareYouTubeRequest,
+			Quota:           quotaYouTube,
+			DefaultSchedule: scheduleHourly,
+			MinInterval:     30 * time.Minute,
+			DefaultLifetime: lifetimeIntermediate,
+			Batch:           &BatchSpec{Size: 50, Param: "id", Split: splitListBody("id", "items")},
+			ClassifyFailure: classifyYouTubeFailure,
+			Cadence:         &CadenceSpec{Metric: "views", Min: 30 * time.Minute, Max: 24 * time.Hour},
+		},
+		{
+			Kind:            RequestKindYouTubeChannel,
+			NewRequest:      newYouTubeChannelRequest,
+			Prepare:         prepareYouTubeRequest,
+			Quota:           quotaYouTube,
+			DefaultSchedule: scheduleHourly,
+			MinInterval:     30 * time.Minute,
+			Batch:           &BatchSpec{Size: 50, Param: "id", Split: splitListBody("id", "items")},
+			ClassifyFailure: classifyYouTubeFailure,
+			Children:        &ChildrenSpec{Kind: RequestKindYouTubeVideo, ParentField: "parent_channel_id"},
+		},
+		{
+			Kind:             RequestKindRedditPost,
+			NewRequest:       newRedditPostRequest,
+			Prepare:          (*ActivityRequester).prepareRedditRequest,
+			SetWorkerMetrics: setRedditPollerMetrics,
+			RateLimit:        rateLimitReddit,
+			DefaultSchedule:  scheduleEvery15Minutes,
+			MinInterval:      5 * time.Minute,
+			DefaultLifetime:  lifetimeIntermediate,
+			Batch:            &BatchSpec{Size: 100, Param: "id", Split: splitListBody("data.name", "data", "children")},
+			Cadence:          &CadenceSpec{Metric: "score", Min: 5 * time.Minute, Max: 6 * time.Hour},
+		},
+		{
+			Kind:             RequestKindRedditComment,
+			NewRequest:       newRedditCommentRequest,
+			Prepare:          (*ActivityRequester).prepareRedditRequest,
+			SetWorkerMetrics: setRedditPollerMetrics,
+			RateLimit:        rateLimitReddit,
+			DefaultSchedule:  scheduleEvery15Minutes,
+			MinInterval:      5 * time.Minute,
+			DefaultLifetime:  lifetimeShort,
+			Batch:            &BatchSpec{Size: 100, Param: "id", Split: splitListBody("data.name", "data", "children")},
+			Cadence:          &CadenceSpec{Metric: "score", Min: 5 * time.Minute, Max: 6 * time.Hour},
+		},
+		{
+			Kind:             RequestKindRedditSubreddit,
+			NewRequest:       newRedditSubredditRequest,
+			Prepare:          (*ActivityRequester).prepareRedditRequest,
+			SetWorkerMetrics: setRedditPollerMetrics,
+			RateLimit:        rateLimitReddit,
+			DefaultSchedule:  scheduleEvery15Minutes,
+			MinInterval:      5 * time.Minute,
+			Children:         &ChildrenSpec{Kind: RequestKindRedditPost, ParentField: "parent_subreddit"},
+		},
+		{
+			Kind:               RequestKindRedditSubredditMonitor,
+			NewRequest:         newRedditSubredditMonitorRequest,
+			NewMetadataRequest: newRedditSubredditMonitorMetaRequest,
+			Prepare:            (*ActivityRequester).prepareRedditListenerRequest,
+			HandleMetrics:      (*ActivityRequester).handleRedditSubredditMonitorMetrics,
+			SetWorkerMetrics:   setRedditMonitorMetrics,
+			RateLimit:          rateLimitRedditListener,
+			DefaultSchedule:    scheduleEveryMinute,
+			MinInterval:        time.Minute,
+		},
+		{
+			Kind:             RequestKindRedditUser,
+			NewRequest:       newRedditUserRequest,
+			Prepare:          (*ActivityRequester).prepareRedditRequest,
+			SetWorkerMetrics: setRedditPollerMetrics,
+			RateLimit:        rateLimitReddit,
+			DefaultSchedule:  scheduleEvery15Minutes,
+			MinInterval:      5 * time.Minute,
+			Children:         &ChildrenSpec{Kind: RequestKindRedditPost, ParentField: "parent_user_name"},
+		},
+		{
+			Kind:               RequestKindRedditUserMonitor,
+			NewRequest:         newRedditUserMonitorRequest,
+			NewMetadataRequest: newRedditUserMonitorMetaRequest,
+			Prepare:            (*ActivityRequester).prepareRedditListenerRequest,
+			HandleMetrics:      (*ActivityRequester).handleRedditUserMonitorMetrics,
+			SetWorkerMetrics:   setRedditMonitorMetrics,
+			RateLimit:          rateLimitRedditListener,
+			DefaultSchedule:    scheduleEveryMinute,
+			MinInterval:        time.Minute,
+		},
+		{
+			Kind:             RequestKindTwitchClip,
+			NewRequest:       newTwitchClipRequest,
+			Prepare:          (*ActivityRequester).prepareTwitchRequest,
+			SetWorkerMetrics: setTwitchMetrics,
+			RateLimit:        rateLimitTwitch,
+			DefaultSchedule:  scheduleEvery15Minutes,
+			MinInterval:      5 * time.Minute,
+			DefaultLifetime:  lifetimeShort,
+			Batch:            &BatchSpec{Size: 100, Param: "id", Repeat: true, Split: splitListBody("id", "data")},
+			Cadence:          &CadenceSpec{Metric: "views", Min: 5 * time.Minute, Max: 12 * time.Hour},
+		},
+		{
+			Kind:             RequestKindTwitchVideo,
+			NewRequest:       newTwitchVideoRequest,
+			Prepare:          (*ActivityRequester).prepareTwitchRequest,
+			SetWorkerMetrics: setTwitchMetrics,
+			RateLimit:        rateLimitTwitch,
+			DefaultSchedule:  scheduleEvery15Minutes,
+			MinInterval:      15 * time.Minute,
+			DefaultLifetime:  lifetimeIntermediate,
+			Batch:            &BatchSpec{Size: 100, Param: "id", Repeat: true, Split: splitListBody("id", "data")},
+			Cadence:          &CadenceSpec{Metric: "views", Min: 15 * time.Minute, Max: 24 * time.Hour},
+		},
+		{
+			Kind:               RequestKindTwitchStream,
+			NewRequest:         newTwitchStreamRequest,
+			NewMetadataRequest: newTwitchStreamMetaRequest,
+			Prepare:            (*ActivityRequester).prepareTwitchRequest,
+			SetWorkerMetrics:   setTwitchMetrics,
+			RateLimit:          rateLimitTwitch,
+			DefaultSchedule:    scheduleEveryMinute,
+			MinInterval:        time.Minute,
+		},
+		{
+			Kind:               RequestKindTwitchUserPastDec,
+			NewRequest:         newTwitchUserPastDecRequest,
+			NewMetadataRequest: newTwitchUserPastDecMetaRequest,
+			Prepare:            (*ActivityRequester).prepareTwitchRequest,
+			HandleMetrics:      (*ActivityRequester).handleTwitchUserPastDecMetrics,
+			SetWorkerMetrics:   setTwitchMetrics,
+			RateLimit:          rateLimitTwitch,
+			DefaultSchedule:    scheduleEvery15Minutes,
+			MinInterval:        5 * time.Minute,
+		},
+	}
+}
+
+// GetRequestKindSpec returns the registered spec for the supplied RequestKind.
+// The returned error wraps ErrUnsupportedRequestKind if the kind isn't
+// registered.
+func GetRequestKindSpec(rk string) (RequestKindSpec, error) {
+	s, ok := requestKindRegistry[rk]
+	if !ok {
+		return RequestKindSpec{}, fmt.Errorf("%w: %s", ErrUnsupportedRequestKind, rk)
+	}
+	return s, nil
+}
+
+func GetSupportedRequestKinds() []string {
+	rks := make([]string, 0, len(requestKindSpecs))
+	for _, s := range requestKindSpecs {
+		rks = append(rks, s.Kind)
+	}
+	return rks
+}
+
+// ValidateRequestKindRegistry checks that every registered RequestKind has all
+// of its required pieces. This should be called on startup by anything that
+// dispatches on RequestKind so that a half-registered kind fails loudly
+// instead of at request time.
+func ValidateRequestKindRegistry() error {
+	var errs []error
+	if extractorSpecLoadErr != nil {
+		errs = append(errs, fmt.Errorf("error loading extractor specs: %w", extractorSpecLoadErr))
+	}
+	for rk := range extractorSpecs {
+		if _, ok := requestKindRegistry[rk]; !ok {
+			errs = append(errs, fmt.Errorf("extractor spec for unregistered kind %s", rk))
+		}
+	}
+	seen := map[string]bool{}
+	for i, s := range requestKindSpecs {
+		if s.Kind == "" {
+			errs = append(errs, fmt.Errorf("request kind spec %d: missing Kind", i))
+			continue
+		}
+		if seen[s.Kind] {
+			errs = append(errs, fmt.Errorf("%s: registered more than once", s.Kind))
+		}
+		seen[s.Kind] = true
+		if s.NewRequest == nil {
+			errs = append(errs, fmt.Errorf("%s: missing NewRequest", s.Kind))
+		}
+		if s.Prepare == nil {
+			errs = append(errs, fmt.Errorf("%s: missing Prepare", s.Kind))
+		}
+		if s.metadataHandler() == nil {
+			errs = append(errs, fmt.Errorf("%s: missing HandleMetadata or metadata extractor", s.Kind))
+		}
+		if s.metricsHandler() == nil {
+			errs = append(errs, fmt.Errorf("%s: missing HandleMetrics or metrics extractor", s.Kind))
+		}
+		if s.DefaultSchedule == nil {
+			errs = append(errs, fmt.Errorf("%s: missing DefaultSchedule", s.Kind))
+		} else if err := validateScheduleSpec(s, s.DefaultSchedule()); err != nil {
+			errs = append(errs, fmt.Errorf("%s: invalid DefaultSchedule: %w", s.Kind, err))
+		}
+		if s.MinInterval <= 0 {
+			errs = append(errs, fmt.Errorf("%s: missing MinInterval", s.Kind))
+		}
+		if c := s.Cadence; c != nil && c.Min < s.MinInterval {
+			errs = append(errs, fmt.Errorf("%s: Cadence.Min is below MinInterval", s.Kind))
+		}
+		if rl := s.RateLimit; rl != nil && (rl.Key == nil || rl.Parse == nil || rl.Window <= 0) {
+			errs = append(errs, fmt.Errorf("%s: RateLimit needs a Key, Parse and Window", s.Kind))
+		}
+		if qs := s.Quota; qs != nil && (qs.Keys == nil || qs.Param == "" || qs.Budget == nil || qs.Cost == nil) {
+			errs = append(errs, fmt.Errorf("%s: Quota needs Keys, a Param, a Budget and a Cost", s.Kind))
+		}
+		if b := s.Batch; b != nil && (b.Size < 1 || b.Param == "" || b.Split == nil) {
+			errs = append(errs, fmt.Errorf("%s: Batch needs a Size, Param and Split", s.Kind))
+		}
+		if c := s.Children; c != nil {
+			if _, ok := requestKindRegistry[c.Kind]; !ok || c.ParentField == "" {
+				errs = append(errs, fmt.Errorf("%s: Children needs a registered Kind and a ParentField", s.Kind))
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// metricsHandler returns the extractor backed handler if there's a metrics
+// extractor loaded for the kind, otherwise HandleMetrics.
+func (s RequestKindSpec) metricsHandler() ResponseHandler {
+	if es := GetExtractorSpec(s.Kind); es != nil && es.Metrics != nil {
+		return extractorMetricsHandler(s.Kind)
+	}
+	return s.HandleMetrics
+}
+
+// metadataHandler returns the extractor backed handler if there's a metadata
+// extractor loaded for the kind, otherwise HandleMetadata.
+func (s RequestKindSpec) metadataHandler() ResponseHandler {
+	if es := GetExtractorSpec(s.Kind); es != nil && es.Metadata != nil {
+		return extractorMetadataHandler(s.Kind)
+	}
+	return s.HandleMetadata
+}
+
+// BuildRequest returns the polling (or metadata) request prototype for the
+// supplied RequestKind and identifier.
+func BuildRequest(q *dbgen.Queries, rk, id string, isMeta bool) (*http.Request, error) {
+	s, err := GetRequestKindSpec(rk)
+	if err != nil {
+		return nil, err
+	}
+	if isMeta && s.NewMetadataRequest != nil {
+		return s.NewMetadataRequest(q, id)
+	}
+	return s.NewRequest(q, id)
+}
+
+// preparers
+
+func prepareInternalRequest(a *ActivityRequester, r *http.Request) error {
+	// for internal requests, just set the authorization token
+	r.Header.Set("Authorization", fmt.Sprintf("Bearer %s", os.Getenv("AUTH_TOKEN")))
+	return nil
+}
+
+func prepareKaggleRequest(a *ActivityRequester, r *http.Request) error {
+	// basic auth
+	r.SetBasicAuth(os.Getenv("KAGGLE_USERNAME"), os.Getenv("KAGGLE_API_KEY"))
+	return nil
+}
+
+func prepareYouTubeRequest(a *ActivityRequester, r *http.Request) error {
+	// for youtube requests, set the non-identifier query params; the key is
+	// picked from the pool when the request is charged to the quota ledger
+	q := r.URL.Query()
+	q.Set("part", "snippet,contentDetails,statistics")
+	if keys := youtubeAPIKeys(); len(keys) > 0 {
+		q.Set("key", keys[0])
+	}
+	r.URL.RawQuery = q.Encode()
+	return nil
+}
+
+func (a *ActivityRequester) prepareRedditRequest(r *http.Request) error {
+	token, err := a.credentials().Token(r.Context(), oauthReddit)
+	if err != nil {
+		return err
+	}
+	r.Header.Set("User-Agent", os.Getenv("REDDIT_USER_AGENT"))
+	r.Header.Set("Authorization", "bearer "+token)
+	return nil
+}
+
+func (a *ActivityRequester) prepareRedditListenerRequest(r *http.Request) error {
+	token, err := a.credentials().Token(r.Context(), oauthRedditListener)
+	if err != nil {
+		return err
+	}
+	r.Header.Set("User-Agent", os.Getenv("REDDIT_LISTENER_USER_AGENT"))
+	r.Header.Set("Authorization", "bearer "+token)
+	// FIXME: we should set the `after` param here, but we'd need to stick
+	// a db cursor onto this struct and start tracking the last monitored
+	// post for users and subreddits. Not worth it at the moment.
+	return nil
+}
+
+func (a *ActivityRequester) prepareTwitchRequest(r *http.Request) error {
+	token, err := a.credentials().Token(r.Context(), oauthTwitch)
+	if err != nil {
+		return err
+	}
+	r.Header.Set("Client-Id", os.Getenv("TWITCH_CLIENT_ID"))
+	r.Header.Set("Authorization", "Bearer "+token)
+	return nil
+}
+
+// worker metric setters
+
+func setRedditPollerMetrics(a *ActivityRequester, l log.Logger, mh client.MetricsHandler, h http.Header) {
+	// set X-Ratelimit-Foo headers
+	labels := map[string]string{"polling_client": "reddit_poller"}
+	a.setRedditPromMetrics(l, mh.WithTags(labels), h)
+}
+
+func setRedditMonitorMetrics(a *ActivityRequester, l log.Logger, mh client.MetricsHandler, h http.Header) {
+	// set X-Ratelimit-Foo headers
+	labels := map[string]string{"polling_client": "reddit_monitor"}
+	a.setRedditPromMetrics(l, mh.WithTags(labels), h)
+}
+
+func setTwitchMetrics(a *ActivityRequester, l log.Logger, mh client.MetricsHandler, h http.Header) {
+	// set Ratelimit-Foo headers
+	labels := map[string]string{"polling_client": "twitch"}
+	a.setTwitchPromMetrics(l, mh.WithTags(labels), h)
+}
@@ -22,133 +22,195 @@ type DoPollingRequestWFRequest struct {
 	Serial      []byte `json:"serial"`
 }
 
+// DoPollingRequestWFResult is handed to the next run of the schedule (see
+// failures.go and cadence.go).
+type DoPollingRequestWFResult struct {
+	// ConsecutiveFailures is the number of runs in a row, up to and
+	// including this one, that failed permanently.
+	ConsecutiveFailures int    `json:"consecutive_failures"`
+	LastFailure         string `json:"last_failure,omitempty"`
+	// CadenceReviewedAt is when the schedule's cadence was last reviewed
+	// (see cadence.go).
+	CadenceReviewedAt time.Time `json:"cadence_reviewed_at"`
+}
+
+// DoBatchPollingRequestWFRequest carries the requests that were still pending
+// when the batch workflow continued as new.
+type DoBatchPollingRequestWFRequest struct {
+	RequestKind string   `json:"request_kind"`
+	Pending     [][]byte `json:"pending,omitempty"`
+	// Schedules maps the IDs of pending requests to the schedules that
+	// enqueued them.
+	Schedules map[string]string `json:"schedules,omitempty"`
+	// Failures counts the permanent failures each schedule has had in a row
+	// (see countBatchFailures).
+	Failures map[string]int `json:"failures,omitempty"`
+}
+
+// BatchPollRequest is signaled to the batch workflow. ScheduleID is the
+// schedule that enqueued the request, if a schedule did.
+type BatchPollRequest struct {
+	DoRequestActRequest
+	ScheduleID string `json:"schedule_id,omitempty"`
+}
+
+// DeliverWebhookWFRequest carries an event that has already been serialized by
+// the server. Each delivery attempt has the server sign it; the worker never
+// sees the webhook secret.
+type DeliverWebhookWFRequest struct {
+	WebhookID int64  `json:"webhook_id"`
+	URL       string `json:"url"`
+	EventID   string `json:"event_id"`
+	EventType string `json:"event_type"`
+	Body      []byte `json:"body"`
+}
+
+// BackfillWFRequest selects the archived responses to replay. IDs and Metrics
+// optionally restrict the backfill to those IDs and (short) metric names.
+type BackfillWFRequest struct {
+	RequestKind string    `json:"request_kind"`
+	IDs         []string  `json:"ids,omitempty"`
+	Metrics     []string  `json:"metrics,omitempty"`
+	Start       time.Time `json:"start"`
+	End         time.Time `json:"end"`
+}
+
+type BackfillResult struct {
+	Responses int `json:"responses"`
+	Samples   int `json:"samples"`
+	Skipped   int `json:"skipped"`
+	Failed    int `json:"failed"`
+}
+
+type WatchScheduleExpiryWFRequest struct {
+	ScheduleID string    `json:"schedule_id"`
+	EndAt      time.Time `json:"end_at"`
+}
+
 // activities
 
 type YouTubeChannelSubActRequest struct {
 	ChannelIDs []string `json:"channel_ids"`
 }
 
+// DoRequestActRequest carries a serialized request. IDs and Metadata only
+// label the archived response (see archive.go).
 type DoRequestActRequest struct {
-	RequestKind string `json:"request_kind"`
-	Serial      []byte `json:"serial"`
+	RequestKind string   `json:"request_kind"`
+	Serial      []byte   `json:"serial"`
+	IDs         []string `json:"ids,omitempty"`
+	Metadata    bool     `json:"metadata,omitempty"`
+}
+type DoBatchRequestActRequest struct {
+	RequestKind string   `json:"request_kind"`
+	Serials     [][]byte `json:"serials"`
+}
+type UploadBatchActRequest struct {
+	DoRequestActResult
+	// Serials are the requests that were batched, so that the IDs missing
+	// from the response can be reported.
+	Serials [][]byte `json:"serials"`
+}
+type UploadBatchResult struct {
+	Message string `json:"message"`
+	// Uploaded and Gone are the IDs of the items that were uploaded and the
+	// ones that are gone (see UploadBatchResponseData).
+	Uploaded []string `json:"uploaded"`
+	Gone     []string `json:"gone"`
+}
+type BackfillDayActRequest struct {
+	BackfillWFRequest
+	Day time.Time `json:"day"`
 }
 type DoRequestActResult struct {
 	RequestKind        string      `json:"request_kind"`
 	ResponseStatusCode int         `json:"response_status_code"`
 	ResponseBody       []byte      `json:"response_body"`
 	ResponseHeader     http.Header `json:"response_header"`
+	// FetchedAt is when the response was received, by the worker's clock;
+	// it's the observation time of the metrics extracted from it.
+	FetchedAt time.Time `json:"fetched_at"`
+	// ProviderDate is the time from the response's Date header, if it had
+	// one; it's only good to the second, but it's the provider's clock.
+	ProviderDate time.Time `json:"provider_date"`
+	// Failure classifies a non-200 response (see failures.go).
+	Failure FailureClass `json:"failure,omitempty"`
+	// SkipReason is set (and nothing else is) if the request wasn't sent
+	// because the provider's rate limit is nearly exhausted.
+	SkipReason string `json:"skip_reason,omitempty"`
 }
 
+// GetDefaultScheduleSpec returns the default schedule for the supplied
+// RequestKind. Unregistered kinds default to every 15 minutes.
 func GetDefaultScheduleSpec(rk, id string) client.ScheduleSpec {
-	var s client.ScheduleSpec
-
-	// first switch over request kinds to get the base schedule
-	switch rk {
-	case RequestKindInternalRandom:
-		// internal queries are frequent since they're cheap
-		s = client.ScheduleSpec{
-			Calendars: []client.ScheduleCalendarSpec{
-				{
-					Second:  []client.ScheduleRange{{Start: 0, End: 59, Step: 30}},
-					Minute:  []client.ScheduleRange{{Start: 0, End: 59, Step: 1}},
-					Hour:    []client.ScheduleRange{{Start: 0, End: 23, Step: 1}},
-					Comment: "every 30 seconds, no jitter",
-				},
-			},
-		}
-	case RequestKindYouTubeChannel, RequestKindYouTubeVideo:
-		// do youtube queries every 10 minutes; high res isn't super necessary,
-		// we have a lot of IDs to query, and the rate limit is pretty much fixed
-		s = client.ScheduleSpec{
-			Calendars: []client.ScheduleCalendarSpec{
-				{
-					Second:  []client.ScheduleRange{{Start: 0}},
-					Minute:  []client.ScheduleRange{{Start: 0}},
-					Hour:    []client.ScheduleRange{{Start: 0, End: 23, Step: 1}},
-					Comment: "every hour, with an hour of jitter",
-				},
-			},
-			Jitter: 60 * 60 * 1e9,
-		}
-
-	case RequestKindRedditSubredditMonitor, RequestKindRedditUserMonitor:
-		// do reddit monitor queries every minute; we want to find posts ASAP, this
-		// runs under a different reddit client id and we don't have a ton of ids to
-		// monitor
-		s = client.ScheduleSpec{
-			Calendars: []client.ScheduleCalendarSpec{
-				{
-					Second:  []client.ScheduleRange{{Start: 0}},
-					Minute:  []client.ScheduleRange{{Start: 0, End: 59, Step: 1}},
-					Hour:    []client.ScheduleRange{{Start: 0, End: 23, Step: 1}},
-					Comment: "every minute, with a minute of jitter",
-				},
-			},
-			Jitter: 60 * 1e9,
-		}
-	case RequestKindTwitchStream:
-		// do twitch stream queries every minute
-		s = client.ScheduleSpec{
-			Calendars: []client.ScheduleCalendarSpec{
-				{
-					Second:  []client.ScheduleRange{{Start: 0}},
-					Minute:  []client.ScheduleRange{{Start: 0, End: 59, Step: 1}},
-					Hour:    []client.ScheduleRange{{Start: 0, End: 23, Step: 1}},
-					Comment: "every minute, with a minute of jitter",
-				},
+	rks, err := GetRequestKindSpec(rk)
+	if err != nil {
+		return scheduleEvery15Minutes()
+	}
+	s := rks.DefaultSchedule()
+	if rks.DefaultLifetime > 0 {
+		s.EndAt = time.Now().Add(rks.DefaultLifetime)
+	}
+	return s
+}
+
+// internal queries are frequent since they're cheap
+func scheduleEvery30Seconds() client.ScheduleSpec {
+	return client.ScheduleSpec{
+		Calendars: []client.ScheduleCalendarSpec{
+			{
+				Second:  []client.ScheduleRange{{Start: 0, End: 59, Step: 30}},
+				Minute:  []client.ScheduleRange{{Start: 0, End: 59, Step: 1}},
+				Hour:    []client.ScheduleRange{{Start: 0, End: 23, Step: 1}},
+				Comment: "every 30 seconds, no jitter",
 			},
-			Jitter: 60 * 1e9,
-		}
-	default:
-		// default to every 15 minutes
-		s = client.ScheduleSpec{
-			Calendars: []client.ScheduleCalendarSpec{
-				{
-					Second:  []client.ScheduleRange{{Start: 0}},
-					Minute:  []client.ScheduleRange{{Start: 0, End: 59, Step: 15}},
-					Hour:    []client.ScheduleRange{{Start: 0, End: 23}},
-					Comment: "every 15 minutes with 15 minutes of jitter",
-				},
+		},
+	}
+}
+
+// youtube queries run every hour; high res isn't super necessary, we have a lot
+// of IDs to query, and the rate limit is pretty much fixed
+func scheduleHourly() client.ScheduleSpec {
+	return client.ScheduleSpec{
+		Calendars: []client.ScheduleCalendarSpec{
+			{
+				Second:  []client.ScheduleRange{{Start: 0}},
+				Minute:  []client.ScheduleRange{{Start: 0}},
+				Hour:    []client.ScheduleRange{{Start: 0, End: 23, Step: 1}},
+				Comment: "every hour, with an hour of jitter",
 			},
-			Jitter: 15 * 60 * 1e9,
-		}
+		},
+		Jitter: 60 * 60 * 1e9,
 	}
+}
 
-	// now apply the EndAt depending on the RequestKind
-	switch rk {
-	case
-		// these schedules should run indefinitely; this is the default behavior
-		RequestKindInternalRandom,
-		RequestKindKaggleNotebook,
-		RequestKindKaggleDataset,
-		RequestKindRedditSubreddit,
-		RequestKindRedditSubredditMonitor,
-		RequestKindRedditUser,
-		RequestKindRedditUserMonitor,
-		RequestKindYouTubeChannel,
-		RequestKindTwitchStream,
-		RequestKindTwitchUserPastDec:
-		// this is a no-op
-
-	case
-		// these schedules should run for an intermediate amount of time
-		RequestKindRedditPost,
-		RequestKindYouTubeVideo,
-		RequestKindTwitchVideo:
-		// run for 4 weeks
-		s.EndAt = time.Now().Add(4 * 7 * 24 * time.Hour)
-
-	case
-		// these schedules are relatively short lived and should terminate after
-		// a relatively short time
-		RequestKindRedditComment,
-		RequestKindTwitchClip:
-		// run for 1 week
-		s.EndAt = time.Now().Add(7 * 24 * time.Hour)
-
-	default:
-		// this is a no-op
+// reddit monitor queries run every minute; we want to find posts ASAP, this
+// runs under a different reddit client id and we don't have a ton of ids to
+// monitor. Twitch streams are short lived so they're polled frequently too.
+func scheduleEveryMinute() client.ScheduleSpec {
+	return client.ScheduleSpec{
+		Calendars: []client.ScheduleCalendarSpec{
+			{
+				Second:  []client.ScheduleRange{{Start: 0}},
+				Minute:  []client.ScheduleRange{{Start: 0, End: 59, Step: 1}},
+				Hour:    []client.ScheduleRange{{Start: 0, End: 23, Step: 1}},
+				Comment: "every minute, with a minute of jitter",
+			},
+		},
+		Jitter: 60 * 1e9,
 	}
+}
 
-	return s
+func scheduleEvery15Minutes() client.ScheduleSpec {
+	return client.ScheduleSpec{
+		Calendars: []client.ScheduleCalendarSpec{
+			{
+				Second:  []client.ScheduleRange{{Start: 0}},
+				Minute:  []client.ScheduleRange{{Start: 0, End: 59, Step: 15}},
+				Hour:    []client.ScheduleRange{{Start: 0, End: 23}},
+				Comment: "every 15 minutes with 15 minutes of jitter",
+			},
+		},
+		Jitter: 15 * 60 * 1e9,
+	}
 }
@@ -0,0 +1,267 @@
+package temporal
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/brojonat/kaggo/server/db/dbgen"
+)
+
+// NOTE: the following newFooRequest functions will return a PROTOTYPE of a
+// request to be made to an external resource. They all satisfy RequestBuilder
+// so they can be referenced from the RequestKind registry; most of them ignore
+// the supplied *dbgen.Queries. The implementation of these
+// functions MUST NOT include dynamic values! The returned request may be
+// serialized and a hash of the request bytes may be used as part of the
+// schedule identifier. If any dynamic values are included and later on change,
+// the hash of the resultant request will also change, and we won't be able to
+// trivially prevent duplicate schedules from being created.
+
+func newInternalRandomRequest(q *dbgen.Queries, id string) (*http.Request, error) {
+	r, err := http.NewRequest(http.MethodGet, "https://api.kaggo.brojonat.com/internal/generate", nil)
+	if err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+func newYouTubeVideoRequest(q *dbgen.Queries, id string) (*http.Request, error) {
+	r, err := http.NewRequest(http.MethodGet, "https://youtube.googleapis.com/youtube/v3/videos", nil)
+	if err != nil {
+		return nil, err
+	}
+	qs := r.URL.Query()
+	qs.Set("part", "snippet,contentDetails,statistics")
+	qs.Set("id", id)
+	r.URL.RawQuery = qs.Encode()
+	return r, nil
+}
+
+func newYouTubeChannelRequest(q *dbgen.Queries, id string) (*http.Request, error) {
+	r, err := http.NewRequest(http.MethodGet, "https://youtube.googleapis.com/youtube/v3/channels", nil)
+	if err != nil {
+		return nil, err
+	}
+	qs := r.URL.Query()
+	qs.Set("part", "snippet,contentDetails,statistics")
+	qs.Set("id", id)
+	r.URL.RawQuery = qs.Encode()
+	return r, nil
+}
+
+func newKaggleNotebookRequest(q *dbgen.Queries, id string) (*http.Request, error) {
+	// https://github.com/Kaggle/kaggle-api/blob/48d0433575cac8dd20cf7557c5d749987f5c14a2/kaggle/api/kaggle_api.py#L3052
+	r, err := http.NewRequest(http.MethodGet, "https://www.kaggle.com/api/v1/kernels/list", nil)
+	if err != nil {
+		return nil, err
+	}
+	// filter to notebooks only and search using the supplied ref
+	qs := r.URL.Query()
+	qs.Set("search", id)
+	r.URL.RawQuery = qs.Encode()
+	return r, nil
+}
+
+func newKaggleDatasetRequest(q *dbgen.Queries, id string) (*http.Request, error) {
+	// https: //github.com/Kaggle/kaggle-api/blob/48d0433575cac8dd20cf7557c5d749987f5c14a2/kaggle/api/kaggle_api.py#L1731
+	r, err := http.NewRequest(http.MethodGet, "https://www.kaggle.com/api/v1/datasets/list", nil)
+	if err != nil {
+		return nil, err
+	}
+	// search using the supplied ref
+	qs := r.URL.Query()
+	qs.Set("search", id)
+	r.URL.RawQuery = qs.Encode()
+	return r, nil
+}
+
+func newKaggleCompetitionRequest(q *dbgen.Queries, id string) (*http.Request, error) {
+	// see competitions_list in the kaggle-api client
+	r, err := http.NewRequest(http.MethodGet, "https://www.kaggle.com/api/v1/competitions/list", nil)
+	if err != nil {
+		return nil, err
+	}
+	// search using the supplied slug (e.g., titanic)
+	qs := r.URL.Query()
+	qs.Set("search", id)
+	r.URL.RawQuery = qs.Encode()
+	return r, nil
+}
+
+// The leaderboard isn't part of the competition listing, so the competition
+// metrics handler follows up with this request. Unlike the others, this isn't
+// scheduled, so it's not subject to the no dynamic values rule.
+func newKaggleCompetitionLeaderboardRequest(id string) (*http.Request, error) {
+	// see competition_view_leaderboard in the kaggle-api client
+	u := fmt.Sprintf("https://www.kaggle.com/api/v1/competitions/%s/leaderboard/view", url.PathEscape(id))
+	return http.NewRequest(http.MethodGet, u, nil)
+}
+
+func newRedditPostRequest(q *dbgen.Queries, id string) (*http.Request, error) {
+	r, err := http.NewRequest(http.MethodGet, "https://oauth.reddit.com/api/info.json", nil)
+	if err != nil {
+		return nil, err
+	}
+	qs := r.URL.Query()
+	qs.Set("id", fmt.Sprintf("t3_%s", id))
+	r.URL.RawQuery = qs.Encode()
+	return r, nil
+}
+
+func newRedditCommentRequest(q *dbgen.Queries, id string) (*http.Request, error) {
+	r, err := http.NewRequest(http.MethodGet, "https://oauth.reddit.com/api/info.json", nil)
+	if err != nil {
+		return nil, err
+	}
+	qs := r.URL.Query()
+	qs.Set("id", fmt.Sprintf("t1_%s", id))
+	r.URL.RawQuery = qs.Encode()
+	return r, nil
+}
+
+func newRedditSubredditRequest(q *dbgen.Queries, id string) (*http.Request, error) {
+	r, err := http.NewRequest(http.MethodGet, fmt.Sprintf("https://oauth.reddit.com/r/%s/about.json", id), nil)
+	if err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+func newRedditSubredditMonitorMetaRequest(q *dbgen.Queries, id string) (*http.Request, error) {
+	r, err := http.NewRequest(http.MethodGet, fmt.Sprintf("https://oauth.reddit.com/r/%s/about.json", id), nil)
+	if err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+func newRedditSubredditMonitorRequest(q *dbgen.Queries, id string) (*http.Request, error) {
+	r, err := http.NewRequest(http.MethodGet, fmt.Sprintf("https://oauth.reddit.com/r/%s.json", id), nil)
+	if err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+func newRedditUserRequest(q *dbgen.Queries, id string) (*http.Request, error) {
+	r, err := http.NewRequest(http.MethodGet, fmt.Sprintf("https://oauth.reddit.com/user/%s/about.json", id), nil)
+	if err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+func newRedditUserMonitorMetaRequest(q *dbgen.Queries, id string) (*http.Request, error) {
+	r, err := http.NewRequest(http.MethodGet, fmt.Sprintf("https://oauth.reddit.com/user/%s/about.json", id), nil)
+	if err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+func newRedditUserMonitorRequest(q *dbgen.Queries, id string) (*http.Request, error) {
+	r, err := http.NewRequest(http.MethodGet, fmt.Sprintf("https://oauth.reddit.com/user/%s/submitted.json", id), nil)
+	if err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+func newTwitchClipRequest(q *dbgen.Queries, id string) (*http.Request, error) {
+	r, err := http.NewRequest(http.MethodGet, "https://api.twitch.tv/helix/clips", nil)
+	if err != nil {
+		return nil, err
+	}
+	qs := r.URL.Query()
+	qs.Add("id", id)
+	r.URL.RawQuery = qs.Encode()
+	return r, nil
+}
+
+func newTwitchVideoRequest(q *dbgen.Queries, id string) (*http.Request, error) {
+	r, err := http.NewRequest(http.MethodGet, "https://api.twitch.tv/helix/videos", nil)
+	if err != nil {
+		return nil, err
+	}
+	qs := r.URL.Query()
+	qs.Add("id", id)
+	r.URL.RawQuery = qs.Encode()
+	return r, nil
+}
+
+func newTwitchStreamMetaRequest(q *dbgen.Queries, username string) (*http.Request, error) {
+	r, err := http.NewRequest(http.MethodGet, "https://api.twitch.tv/helix/users", nil)
+	if err != nil {
+		return nil, err
+	}
+	qs := r.URL.Query()
+	qs.Add("login", username)
+	qs.Add("sort", "time")
+	r.URL.RawQuery = qs.Encode()
+	return r, nil
+}
+
+func newTwitchStreamRequest(q *dbgen.Queries, username string) (*http.Request, error) {
+	r, err := http.NewRequest(http.MethodGet, "https://api.twitch.tv/helix/streams", nil)
+	if err != nil {
+		return nil, err
+	}
+	qs := r.URL.Query()
+	qs.Add("user_login", username)
+	qs.Add("sort", "time")
+	r.URL.RawQuery = qs.Encode()
+	return r, nil
+}
+
+func newTwitchUserPastDecMetaRequest(q *dbgen.Queries, username string) (*http.Request, error) {
+	r, err := http.NewRequest(http.MethodGet, "https://api.twitch.tv/helix/users", nil)
+	if err != nil {
+		return nil, err
+	}
+	qs := r.URL.Query()
+	qs.Add("login", username)
+	r.URL.RawQuery = qs.Encode()
+	return r, nil
+}
+
+func newTwitchUserPastDecRequest(q *dbgen.Queries, username string) (*http.Request, error) {
+	r, err := http.NewRequest(http.MethodGet, "https://api.twitch.tv/helix/videos", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	// This assumes that we've already run the metadata workflow for this
+	// request. This is necessary because we need the Twitch user_id to fetch a
+	// user's recent videos. Our choices are to query the Twitch API here, or
+	// leverage the fact that the application has already run the metadata
+	// workflow and that the corresponding entry exists in the DB under the
+	// supplied username. If a caller is pathological, they can find a way to
+	// skip the metadata workflow, but then this will simply return an error.
+	if q == nil {
+		return nil, fmt.Errorf("error getting twitch user_id from metadata: no db connection")
+	}
+	mds, err := q.GetMetadataByIDs(context.Background(), []string{username})
+	if err != nil {
+		return nil, fmt.Errorf("error getting twitch user_id from metadata: %w", err)
+	}
+	if len(mds) == 0 {
+		return nil, fmt.Errorf("error getting twitch user_id from metadata: no result rows")
+	}
+
+	var user_id string
+	for _, md := range mds {
+		if md.RequestKind == RequestKindTwitchUserPastDec {
+			user_id = md.Data.UserID
+			break
+		}
+	}
+	if user_id == "" {
+		return nil, fmt.Errorf("error getting twitch user_id from metadata: no user_id found in metadata")
+	}
+	qs := r.URL.Query()
+	qs.Add("user_id", user_id)
+	r.URL.RawQuery = qs.Encode()
+	return r, nil
+}
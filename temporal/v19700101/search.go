@@ -0,0 +1,148 @@
+package temporal
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	commonpb "go.temporal.io/api/common/v1"
+	enumspb "go.temporal.io/api/enums/v1"
+	"go.temporal.io/api/operatorservice/v1"
+	"go.temporal.io/sdk/client"
+	"go.temporal.io/sdk/converter"
+)
+
+// Search attributes. Schedules, and the workflows they start, are created with
+// these (Keyword) search attributes so that they can be listed and filtered by
+// Temporal's visibility store instead of by parsing schedule IDs. Tier is the
+// schedule preset the schedule was created with ("custom" for raw specs).
+const (
+	SearchAttributeRequestKind = "RequestKind"
+	SearchAttributeEntityID    = "EntityID"
+	SearchAttributeOwnerEmail  = "OwnerEmail"
+	SearchAttributeTier        = "Tier"
+
+	ScheduleTierCustom = "custom"
+)
+
+var searchAttributeNames = []string{
+	SearchAttributeRequestKind,
+	SearchAttributeEntityID,
+	SearchAttributeOwnerEmail,
+	SearchAttributeTier,
+}
+
+// SearchAttributes are the search attributes of a schedule or workflow. Empty
+// fields are unset (or, in a filter, unconstrained).
+type SearchAttributes struct {
+	RequestKind string `json:"request_kind,omitempty"`
+	EntityID    string `json:"entity_id,omitempty"`
+	OwnerEmail  string `json:"owner_email,omitempty"`
+	Tier        string `json:"tier,omitempty"`
+}
+
+func (a SearchAttributes) fields() map[string]string {
+	return map[string]string{
+		SearchAttributeRequestKind: a.RequestKind,
+		SearchAttributeEntityID:    a.EntityID,
+		SearchAttributeOwnerEmail:  a.OwnerEmail,
+		SearchAttributeTier:        a.Tier,
+	}
+}
+
+// Map returns the set attributes in the form the SDK's options take.
+func (a SearchAttributes) Map() map[string]interface{} {
+	m := map[string]interface{}{}
+	for k, v := range a.fields() {
+		if v != "" {
+			m[k] = v
+		}
+	}
+	return m
+}
+
+// Matches reports whether every set field of the filter f equals the
+// corresponding field of a.
+func (a SearchAttributes) Matches(f SearchAttributes) bool {
+	af := a.fields()
+	for k, v := range f.fields() {
+		if v != "" && af[k] != v {
+			return false
+		}
+	}
+	return true
+}
+
+// Query returns the visibility query (i.e., the WHERE clause) matching the set
+// fields, e.g., "RequestKind = 'reddit.post' AND Tier = 'default'". It's empty
+// if no fields are set.
+func (a SearchAttributes) Query() string {
+	f := a.fields()
+	var cs []string
+	for _, k := range searchAttributeNames {
+		if f[k] != "" {
+			cs = append(cs, fmt.Sprintf("%s = %s", k, quoteVisibilityString(f[k])))
+		}
+	}
+	return strings.Join(cs, " AND ")
+}
+
+func quoteVisibilityString(s string) string {
+	return "'" + strings.NewReplacer(`\`, `\\`, `'`, `\'`).Replace(s) + "'"
+}
+
+// DecodeSearchAttributes decodes the attributes of a schedule or workflow;
+// the ones that aren't set (or can't be decoded) are left empty.
+func DecodeSearchAttributes(sa *commonpb.SearchAttributes) SearchAttributes {
+	var a SearchAttributes
+	if sa == nil {
+		return a
+	}
+	dc := converter.GetDefaultDataConverter()
+	for k, dst := range map[string]*string{
+		SearchAttributeRequestKind: &a.RequestKind,
+		SearchAttributeEntityID:    &a.EntityID,
+		SearchAttributeOwnerEmail:  &a.OwnerEmail,
+		SearchAttributeTier:        &a.Tier,
+	} {
+		if p := sa.IndexedFields[k]; p != nil {
+			if err := dc.FromPayload(p, dst); err != nil {
+				*dst = ""
+			}
+		}
+	}
+	return a
+}
+
+// RegisterSearchAttributes adds any of the search attributes that aren't yet
+// registered in the namespace. This requires access to the operator service,
+// which isn't available on every deployment (e.g., Temporal Cloud); register
+// them out of band there.
+func RegisterSearchAttributes(ctx context.Context, c client.Client, namespace string) error {
+	res, err := c.OperatorService().ListSearchAttributes(ctx, &operatorservice.ListSearchAttributesRequest{Namespace: namespace})
+	if err != nil {
+		return fmt.Errorf("could not list search attributes: %w", err)
+	}
+	missing := map[string]enumspb.IndexedValueType{}
+	for _, k := range searchAttributeNames {
+		t, ok := res.CustomAttributes[k]
+		if !ok {
+			missing[k] = enumspb.INDEXED_VALUE_TYPE_KEYWORD
+			continue
+		}
+		if t != enumspb.INDEXED_VALUE_TYPE_KEYWORD {
+			return fmt.Errorf("search attribute %s is registered as %s, not Keyword", k, t)
+		}
+	}
+	if len(missing) == 0 {
+		return nil
+	}
+	_, err = c.OperatorService().AddSearchAttributes(ctx, &operatorservice.AddSearchAttributesRequest{
+		Namespace:        namespace,
+		SearchAttributes: missing,
+	})
+	if err != nil {
+		return fmt.Errorf("could not add search attributes: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,221 @@
+package temporal
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/brojonat/kaggo/server/alerts"
+	"github.com/brojonat/kaggo/server/api"
+	"go.temporal.io/sdk/activity"
+	"go.temporal.io/sdk/temporal"
+	"go.temporal.io/sdk/workflow"
+)
+
+// Headers set on every webhook delivery. The signature is the hex encoded
+// HMAC-SHA256 of "<timestamp>.<body>" keyed with the webhook's secret.
+const (
+	WebhookHeaderEvent     = "X-Kaggo-Event"
+	WebhookHeaderDelivery  = "X-Kaggo-Delivery"
+	WebhookHeaderTimestamp = "X-Kaggo-Timestamp"
+	WebhookHeaderSignature = "X-Kaggo-Signature"
+)
+
+type ActivityWebhooks struct{}
+
+// Webhook URLs are user supplied, so deliveries only go to public addresses.
+var webhookClient = alerts.NewPublicHTTPClient()
+
+// DeliverWebhookWF POSTs a signed event to a webhook endpoint. Delivery is
+// retried with backoff for a couple hours; if it still fails, the event is
+// recorded as a dead letter on the kaggo server.
+func DeliverWebhookWF(ctx workflow.Context, r DeliverWebhookWFRequest) error {
+	var a *ActivityWebhooks
+
+	activityOptions := workflow.ActivityOptions{
+		StartToCloseTimeout: 30 * time.Second,
+		RetryPolicy: &temporal.RetryPolicy{
+			InitialInterval:        10 * time.Second,
+			BackoffCoefficient:     2,
+			MaximumInterval:        30 * time.Minute,
+			MaximumAttempts:        10,
+			NonRetryableErrorTypes: []string{"ErrNoRetry"},
+		},
+	}
+	ctx = workflow.WithActivityOptions(ctx, activityOptions)
+	derr := workflow.ExecuteActivity(ctx, a.DeliverWebhook, r).Get(ctx, nil)
+	if derr == nil {
+		return nil
+	}
+
+	// Record the dead letter. Our server could be down for any number of
+	// reasons, so this gets a generous number of retries.
+	activityOptions = workflow.ActivityOptions{
+		StartToCloseTimeout: 30 * time.Second,
+		RetryPolicy:         &temporal.RetryPolicy{MaximumAttempts: 20, BackoffCoefficient: 5},
+	}
+	ctx = workflow.WithActivityOptions(ctx, activityOptions)
+	dl := api.WebhookDeadLetterPayload{
+		WebhookID: r.WebhookID,
+		EventID:   r.EventID,
+		EventType: r.EventType,
+		Payload:   string(r.Body),
+		Error:     derr.Error(),
+	}
+	if err := workflow.ExecuteActivity(ctx, a.RecordWebhookDeadLetter, dl).Get(ctx, nil); err != nil {
+		return err
+	}
+	return derr
+}
+
+// WatchScheduleExpiryWF sleeps until the schedule's EndAt and then asks the
+// kaggo server to emit the schedule.expired event. Schedules can be extended,
+// so the server may instead hand back a new EndAt to wait for. A day before
+// each EndAt, the server reviews whether the schedule should be extended (see
+// lifetime.go).
+func WatchScheduleExpiryWF(ctx workflow.Context, r WatchScheduleExpiryWFRequest) error {
+	var a *ActivityWebhooks
+
+	activityOptions := workflow.ActivityOptions{
+		StartToCloseTimeout: 30 * time.Second,
+		RetryPolicy:         &temporal.RetryPolicy{MaximumAttempts: 20, BackoffCoefficient: 5},
+	}
+	ctx = workflow.WithActivityOptions(ctx, activityOptions)
+
+	// watchers started before the review existed don't do it
+	review := workflow.GetVersion(ctx, "end-of-life-review", workflow.DefaultVersion, 1) == 1
+	var reviewed time.Time
+
+	endAt := r.EndAt
+	for {
+		if review && !endAt.Equal(reviewed) {
+			if d := endAt.Add(-EndOfLifeReviewLead).Sub(workflow.Now(ctx)); d > 0 {
+				if err := workflow.Sleep(ctx, d); err != nil {
+					return err
+				}
+			}
+			reviewed = endAt
+			var res api.ScheduleExpiryResponse
+			p := api.ScheduleExpiryPayload{ScheduleID: r.ScheduleID}
+			err := workflow.ExecuteActivity(ctx, a.ReviewScheduleEndOfLife, p).Get(ctx, &res)
+			switch {
+			case err != nil:
+				// the schedule still expires; it just isn't extended
+				workflow.GetLogger(ctx).Warn("error reviewing schedule end of life", "schedule_id", r.ScheduleID, "error", err.Error())
+			case res.Done:
+				return nil
+			case !res.EndAt.IsZero() && !res.EndAt.Equal(endAt):
+				endAt = res.EndAt
+				continue
+			}
+		}
+		// don't spin if the server keeps handing back an EndAt in the past
+		d := max(endAt.Sub(workflow.Now(ctx)), time.Minute)
+		if err := workflow.Sleep(ctx, d); err != nil {
+			return err
+		}
+		var res api.ScheduleExpiryResponse
+		p := api.ScheduleExpiryPayload{ScheduleID: r.ScheduleID}
+		if err := workflow.ExecuteActivity(ctx, a.CheckScheduleExpiry, p).Get(ctx, &res); err != nil {
+			return err
+		}
+		if res.Done {
+			return nil
+		}
+		endAt = res.EndAt
+	}
+}
+
+// DeliverWebhook does a single delivery attempt. 4xx responses (other than
+// 408 and 429) aren't retried; the endpoint is telling us it won't accept the
+// event. Neither are URLs that resolve to addresses that aren't public. The
+// server signs each attempt with the time it's made, so the timestamp is fresh
+// on retries.
+func (a *ActivityWebhooks) DeliverWebhook(ctx context.Context, r DeliverWebhookWFRequest) error {
+	var sig api.WebhookSignResponse
+	if err := postToKaggo(ctx, "/webhooks/sign", api.WebhookSignPayload{WebhookID: r.WebhookID, Body: r.Body}, &sig); err != nil {
+		return err
+	}
+	if sig.Gone {
+		activity.GetLogger(ctx).Info("webhook is gone; dropping delivery", "webhook_id", r.WebhookID, "event_id", r.EventID)
+		return nil
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, r.URL, bytes.NewReader(r.Body))
+	if err != nil {
+		return ErrNoRetry{Err: fmt.Errorf("error making webhook request: %w", err)}
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(WebhookHeaderEvent, r.EventType)
+	req.Header.Set(WebhookHeaderDelivery, r.EventID)
+	req.Header.Set(WebhookHeaderTimestamp, fmt.Sprint(sig.Timestamp))
+	req.Header.Set(WebhookHeaderSignature, "sha256="+sig.Signature)
+	res, err := webhookClient.Do(req)
+	if err != nil {
+		err = fmt.Errorf("error doing webhook request: %w", err)
+		if errors.Is(err, alerts.ErrNotPublic) {
+			return ErrNoRetry{Err: err}
+		}
+		return err
+	}
+	defer res.Body.Close()
+	if res.StatusCode >= 200 && res.StatusCode < 300 {
+		return nil
+	}
+	b, _ := io.ReadAll(io.LimitReader(res.Body, 1024))
+	err = fmt.Errorf("bad webhook response: %d: %s", res.StatusCode, b)
+	if res.StatusCode >= 400 && res.StatusCode < 500 &&
+		res.StatusCode != http.StatusRequestTimeout && res.StatusCode != http.StatusTooManyRequests {
+		return ErrNoRetry{Err: err}
+	}
+	return err
+}
+
+func (a *ActivityWebhooks) RecordWebhookDeadLetter(ctx context.Context, p api.WebhookDeadLetterPayload) error {
+	activity.GetLogger(ctx).Error("webhook delivery failed", "webhook_id", p.WebhookID, "event_id", p.EventID, "error", p.Error)
+	var res api.DefaultJSONResponse
+	return postToKaggo(ctx, "/webhooks/dead-letters", p, &res)
+}
+
+func (a *ActivityWebhooks) CheckScheduleExpiry(ctx context.Context, p api.ScheduleExpiryPayload) (*api.ScheduleExpiryResponse, error) {
+	var res api.ScheduleExpiryResponse
+	if err := postToKaggo(ctx, "/schedule/expired", p, &res); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// Helper that POSTs a JSON body to the kaggo backend and parses the response
+// into dst.
+func postToKaggo(ctx context.Context, path string, body, dst any) error {
+	b, err := json.Marshal(body)
+	if err != nil {
+		return fmt.Errorf("error serializing request: %w", err)
+	}
+	r, err := http.NewRequestWithContext(ctx, http.MethodPost, os.Getenv("KAGGO_ENDPOINT")+path, bytes.NewReader(b))
+	if err != nil {
+		return fmt.Errorf("error making request to %s: %w", path, err)
+	}
+	r.Header.Add("Authorization", fmt.Sprintf("Bearer %s", os.Getenv("AUTH_TOKEN")))
+	res, err := http.DefaultClient.Do(r)
+	if err != nil {
+		return fmt.Errorf("error doing request to %s: %w", path, err)
+	}
+	defer res.Body.Close()
+	b, err = io.ReadAll(res.Body)
+	if err != nil {
+		return fmt.Errorf("error reading %s response body: %w", path, err)
+	}
+	if res.StatusCode != http.StatusOK {
+		return fmt.Errorf("bad response code from %s: %d: %s", path, res.StatusCode, b)
+	}
+	if err = json.Unmarshal(b, dst); err != nil {
+		return fmt.Errorf("error parsing %s response: %w", path, err)
+	}
+	return nil
+}
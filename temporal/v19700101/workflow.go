@@ -53,11 +53,14 @@ func DoMetadataRequestWF(ctx workflow.Context, r DoMetadataRequestWFRequest) err
 		RetryPolicy:         &temporal.RetryPolicy{MaximumAttempts: 10, BackoffCoefficient: 5},
 	}
 	ctx = workflow.WithActivityOptions(ctx, activityOptions)
-	doReqActReq := DoRequestActRequest(r)
+	doReqActReq := newDoRequestActRequest(ctx, r.RequestKind, r.Serial, true)
 	var doReqActRes DoRequestActResult
 	if err := workflow.ExecuteActivity(ctx, a.DoRequest, doReqActReq).Get(ctx, &doReqActRes); err != nil {
 		return err
 	}
+	if doReqActRes.SkipReason != "" {
+		return fmt.Errorf("request skipped: %s", doReqActRes.SkipReason)
+	}
 	if doReqActRes.ResponseStatusCode != http.StatusOK {
 		return fmt.Errorf("non-200 response: %d (%s): %s",
 			doReqActRes.ResponseStatusCode, http.StatusText(doReqActRes.ResponseStatusCode), doReqActRes.ResponseBody)
@@ -96,10 +99,50 @@ func DoMetadataRequestWF(ctx workflow.Context, r DoMetadataRequestWFRequest) err
 
 // DoPollingRequestWF workflow performs a request against some external API and
 // passes the response to a handler that parses metrics from the response and
-// uploads the metrics to the kaggo server.
-func DoPollingRequestWF(ctx workflow.Context, r DoPollingRequestWFRequest) error {
+// uploads the metrics to the kaggo server. Permanent failures (e.g., the
+// content was deleted) are counted across runs of the schedule rather than
+// failing the run (see failures.go).
+func DoPollingRequestWF(ctx workflow.Context, r DoPollingRequestWFRequest) (*DoPollingRequestWFResult, error) {
+
+	// runs started before batching and failure tracking existed poll the
+	// way they always did
+	if workflow.GetVersion(ctx, "batching-and-failure-tracking", workflow.DefaultVersion, 1) == workflow.DefaultVersion {
+		return nil, doPollingRequestV0(ctx, r)
+	}
 
 	var a *ActivityRequester
+	var prev DoPollingRequestWFResult
+	if workflow.HasLastCompletionResult(ctx) {
+		if err := workflow.GetLastCompletionResult(ctx, &prev); err != nil {
+			workflow.GetLogger(ctx).Warn("error reading last completion result", "error", err.Error())
+		}
+	}
+
+	// If the kind is polled in batches, hand the request off to the batch
+	// workflow (see batch.go). This is a side effect so that replays don't
+	// depend on the worker's configuration.
+	var batched bool
+	err := workflow.SideEffect(ctx, func(ctx workflow.Context) interface{} {
+		return BatchPollingEnabled(r.RequestKind)
+	}).Get(&batched)
+	if err != nil {
+		return nil, err
+	}
+	if batched {
+		var ab *ActivityBatchPoller
+		activityOptions := workflow.ActivityOptions{
+			StartToCloseTimeout: 10 * time.Second,
+			RetryPolicy:         &temporal.RetryPolicy{MaximumAttempts: 5},
+		}
+		ctx = workflow.WithActivityOptions(ctx, activityOptions)
+		req := BatchPollRequest{DoRequestActRequest: newDoRequestActRequest(ctx, r.RequestKind, r.Serial, false), ScheduleID: scheduledByID(ctx)}
+		if err := workflow.ExecuteActivity(ctx, ab.EnqueueBatchPoll, req).Get(ctx, nil); err != nil {
+			return nil, err
+		}
+		res := &DoPollingRequestWFResult{CadenceReviewedAt: prev.CadenceReviewedAt}
+		reviewCadence(ctx, r, res)
+		return res, nil
+	}
 
 	// Do the long polling request. Don't retry; these are "cheap" requests and
 	// it's better to miss some window of data than risk spamming the external
@@ -109,14 +152,24 @@ func DoPollingRequestWF(ctx workflow.Context, r DoPollingRequestWFRequest) error
 		RetryPolicy:         &temporal.RetryPolicy{MaximumAttempts: 1},
 	}
 	ctx = workflow.WithActivityOptions(ctx, activityOptions)
-	doReqActReq := DoRequestActRequest(r)
+	doReqActReq := newDoRequestActRequest(ctx, r.RequestKind, r.Serial, false)
 	var doReqActRes DoRequestActResult
 	if err := workflow.ExecuteActivity(ctx, a.DoRequest, doReqActReq).Get(ctx, &doReqActRes); err != nil {
-		return err
+		return nil, err
+	}
+	// Rate limited requests are skipped rather than sent; there will be
+	// another polling loop anyway.
+	if doReqActRes.SkipReason != "" {
+		workflow.GetLogger(ctx).Warn("polling request skipped", "request_kind", r.RequestKind, "reason", doReqActRes.SkipReason)
+		return &prev, nil
 	}
 	if doReqActRes.ResponseStatusCode != http.StatusOK {
-		return fmt.Errorf("non-200 response: %d (%s): %s",
+		err := fmt.Errorf("non-200 response: %d (%s): %s",
 			doReqActRes.ResponseStatusCode, http.StatusText(doReqActRes.ResponseStatusCode), doReqActRes.ResponseBody)
+		if doReqActRes.Failure.Permanent() {
+			return pollingFailedPermanently(ctx, r, prev, doReqActRes.Failure, err)
+		}
+		return nil, err
 	}
 
 	// Upload the response to our server. We can retry a couple times over a
@@ -128,7 +181,11 @@ func DoPollingRequestWF(ctx workflow.Context, r DoPollingRequestWFRequest) error
 	// because schedule creation is blocked by the initial metadata workflow.
 	activityOptions = workflow.ActivityOptions{
 		StartToCloseTimeout: 1 * time.Minute,
-		RetryPolicy:         &temporal.RetryPolicy{MaximumAttempts: 5, BackoffCoefficient: 5},
+		RetryPolicy: &temporal.RetryPolicy{
+			MaximumAttempts:        5,
+			BackoffCoefficient:     5,
+			NonRetryableErrorTypes: []string{"ErrContentGone"},
+		},
 	}
 
 	ctx = workflow.WithActivityOptions(ctx, activityOptions)
@@ -137,13 +194,60 @@ func DoPollingRequestWF(ctx workflow.Context, r DoPollingRequestWFRequest) error
 
 	// Set the metrics after handling the request
 	if err := workflow.ExecuteActivity(ctx, a.UploadResponseData, doReqActRes).Get(ctx, &dataUploadResponse); err != nil {
-		return err
+		if isContentGone(err) {
+			return pollingFailedPermanently(ctx, r, prev, FailureDeleted, err)
+		}
+		return nil, err
 	}
 	// Set the metrics after handling the metadata request. This can share the same
 	// activity params as above, but this should be stuff local to the host, so
 	// it shouldn't really have transient failures.
 	if err := workflow.ExecuteActivity(ctx, a.SetWorkerMetrics, doReqActRes).Get(ctx, &metricsResponse); err != nil {
+		return nil, err
+	}
+	res := &DoPollingRequestWFResult{CadenceReviewedAt: prev.CadenceReviewedAt}
+	reviewCadence(ctx, r, res)
+	return res, nil
+}
+
+// doPollingRequestV0 is DoPollingRequestWF as it was before batching and
+// failure tracking; it's kept for replaying the runs started back then.
+func doPollingRequestV0(ctx workflow.Context, r DoPollingRequestWFRequest) error {
+	var a *ActivityRequester
+
+	activityOptions := workflow.ActivityOptions{
+		StartToCloseTimeout: 30 * time.Second,
+		RetryPolicy:         &temporal.RetryPolicy{MaximumAttempts: 1},
+	}
+	ctx = workflow.WithActivityOptions(ctx, activityOptions)
+	var doReqActRes DoRequestActResult
+	if err := workflow.ExecuteActivity(ctx, a.DoRequest, newDoRequestActRequest(ctx, r.RequestKind, r.Serial, false)).Get(ctx, &doReqActRes); err != nil {
 		return err
 	}
-	return nil
+	if doReqActRes.ResponseStatusCode != http.StatusOK {
+		return fmt.Errorf("non-200 response: %d (%s): %s",
+			doReqActRes.ResponseStatusCode, http.StatusText(doReqActRes.ResponseStatusCode), doReqActRes.ResponseBody)
+	}
+
+	activityOptions = workflow.ActivityOptions{
+		StartToCloseTimeout: 1 * time.Minute,
+		RetryPolicy:         &temporal.RetryPolicy{MaximumAttempts: 5, BackoffCoefficient: 5},
+	}
+	ctx = workflow.WithActivityOptions(ctx, activityOptions)
+	if err := workflow.ExecuteActivity(ctx, a.UploadResponseData, doReqActRes).Get(ctx, nil); err != nil {
+		return err
+	}
+	return workflow.ExecuteActivity(ctx, a.SetWorkerMetrics, doReqActRes).Get(ctx, nil)
+}
+
+// newDoRequestActRequest builds the DoRequest activity's request. The entity
+// ID comes from the workflow's search attributes, which the server sets when
+// it starts the metadata workflow or creates the schedule; runs that predate
+// them aren't labelled with an ID.
+func newDoRequestActRequest(ctx workflow.Context, rk string, serial []byte, metadata bool) DoRequestActRequest {
+	r := DoRequestActRequest{RequestKind: rk, Serial: serial, Metadata: metadata}
+	if id := DecodeSearchAttributes(workflow.GetInfo(ctx).SearchAttributes).EntityID; id != "" {
+		r.IDs = []string{id}
+	}
+	return r
 }
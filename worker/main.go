@@ -2,12 +2,17 @@ package worker
 
 import (
 	"context"
+	"fmt"
 	"log"
 	"log/slog"
 	"os"
 	"time"
 
+	"github.com/brojonat/kaggo/server/alerts"
+	"github.com/brojonat/kaggo/server/db/dbgen"
 	kt "github.com/brojonat/kaggo/temporal/v19700101"
+	"github.com/brojonat/server-tools/stools"
+	"github.com/jackc/pgx/v5"
 	prom "github.com/prometheus/client_golang/prometheus"
 	"github.com/uber-go/tally/v4"
 	"github.com/uber-go/tally/v4/prometheus"
@@ -16,7 +21,21 @@ import (
 	"go.temporal.io/sdk/worker"
 )
 
-func RunWorker(ctx context.Context, l *slog.Logger, thp string) error {
+// RunWorker runs the Temporal worker. If dbHost is set, OAuth tokens are
+// shared with the other workers through the database; otherwise each worker
+// fetches its own. If directIngest is also set, metrics are written directly
+// to the database instead of being uploaded to the server (see
+// kt.MetricWriter). If archiveURL is set, every response is archived to the
+// blob store it describes (see kt.NewBlobStore).
+func RunWorker(ctx context.Context, l *slog.Logger, thp, dbHost, archiveURL string, directIngest bool) error {
+	// fail fast if any RequestKind is missing a piece
+	if err := kt.ValidateRequestKindRegistry(); err != nil {
+		return fmt.Errorf("invalid request kind registry: %w", err)
+	}
+	if directIngest && dbHost == "" {
+		return fmt.Errorf("direct ingestion requires a database")
+	}
+
 	// connect to temporal
 	c, err := client.Dial(client.Options{
 		Logger:   l,
@@ -36,15 +55,45 @@ func RunWorker(ctx context.Context, l *slog.Logger, thp string) error {
 	w.RegisterWorkflow(kt.DoPollingRequestWF)
 	w.RegisterWorkflow(kt.DoMetadataRequestWF)
 	w.RegisterWorkflow(kt.RunYouTubeListenerWF)
+	w.RegisterWorkflow(kt.DeliverWebhookWF)
+	w.RegisterWorkflow(kt.WatchScheduleExpiryWF)
+	w.RegisterWorkflow(kt.DoBatchPollingRequestWF)
+	w.RegisterWorkflow(kt.BackfillWF)
 
 	// register activities
 	// NOTE: you MUST NOT have any identical methods on these activity structs,
 	// or you will encounter a runtime error that prevents all of your workers
 	// from starting :O
-	a := &kt.ActivityRequester{}
+	a := &kt.ActivityRequester{Credentials: kt.NewMemoryCredentialProvider()}
+	if dbHost != "" {
+		p, err := stools.GetConnPool(
+			ctx, dbHost,
+			func(ctx context.Context, c *pgx.Conn) error { return nil },
+		)
+		if err != nil {
+			return fmt.Errorf("could not connect to db: %w", err)
+		}
+		defer p.Close()
+		a.Credentials = kt.NewPostgresCredentialProvider(p)
+		if directIngest {
+			// closed before the pool so the buffered samples get written
+			a.MetricWriter = kt.NewMetricWriter(p, kt.DefaultMetricWriterBatchSize, kt.DefaultMetricWriterFlushInterval)
+			a.MetricWriter.Alerter = alerts.NewAlerter(l, dbgen.New(p), alerts.GetDefaultNotifiers())
+			defer a.MetricWriter.Close()
+		}
+	}
+	if archiveURL != "" {
+		if a.Archive, err = kt.NewBlobStore(archiveURL); err != nil {
+			return fmt.Errorf("could not open response archive: %w", err)
+		}
+	}
 	ysub := &kt.ActivityYouTubeListener{}
+	wh := &kt.ActivityWebhooks{}
+	bp := &kt.ActivityBatchPoller{Client: c}
 	w.RegisterActivity(a)
 	w.RegisterActivity(ysub)
+	w.RegisterActivity(wh)
+	w.RegisterActivity(bp)
 	return w.Run(worker.InterruptCh())
 
 }